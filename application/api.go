@@ -14,17 +14,16 @@ import (
 	"service_admin_contractor/application/middleware"
 	"service_admin_contractor/application/respond"
 	"service_admin_contractor/application/service"
-	"service_admin_contractor/infrastructure/logging"
+	"service_admin_contractor/application/stream"
+	"service_admin_contractor/domain/model"
 	"service_admin_contractor/infrastructure/persistence/postgres"
+	"service_admin_contractor/infrastructure/storage"
 )
 
 // NewApi конфигурирует API
-func NewApi() (http.Handler, error) {
-	logging.ConfigureLogger()
+func NewApi(pc *pgxpool.Pool, broker *stream.Broker) (http.Handler, error) {
 	cvalidator.ConfigureValidator()
 
-	pc := postgres.DBConn()
-
 	r := mux.NewRouter()
 	r.NotFoundHandler = http.HandlerFunc(handleNotFoundError)
 
@@ -40,7 +39,7 @@ func NewApi() (http.Handler, error) {
 		middleware.AllowedHeaders(viper.GetStringSlice(config.CorsAllowedHeaders)),
 		middleware.AllowCredentials()))
 
-	err := configureRoutes(r, pc)
+	err := configureRoutes(r, pc, broker)
 	if err != nil {
 		return nil, err
 	}
@@ -60,16 +59,61 @@ func configureHealthchecks(r *mux.Router, pc *pgxpool.Pool) {
 	))
 }
 
-func configureRoutes(r *mux.Router, pc *pgxpool.Pool) error {
+func configureRoutes(r *mux.Router, pc *pgxpool.Pool, broker *stream.Broker) error {
 	contractorRepo := postgres.NewContractorRepository(pc)
+	savedSearchRepo := postgres.NewSavedSearchRepository(pc)
+	auditRepo := postgres.NewAuditRepository(pc)
+	outboxRepo := postgres.NewOutboxRepository(pc)
+	changeRepo := postgres.NewChangeRepository(pc)
+	webhookRepo := postgres.NewWebhookRepository(pc)
+	contactRepo := postgres.NewContactRepository(pc)
+	documentRepo := postgres.NewDocumentRepository(pc)
+	contractRepo := postgres.NewContractRepository(pc)
+	tagRepo := postgres.NewTagRepository(pc)
+	bankAccountRepo := postgres.NewBankAccountRepository(pc)
 	bpmsUserRepo := postgres.NewBpmsUserRepository(pc)
-	contractorSrvc := service.NewContractorService(contractorRepo)
+	emailScope, err := model.ParseEmployeeEmailScope(viper.GetString(config.EmployeeEmailScope))
+	if err != nil {
+		return err
+	}
+
+	documentStorage, err := storage.NewLocalStorage(viper.GetString(config.DocumentStoragePath))
+	if err != nil {
+		return err
+	}
+	documentMaxSize := viper.GetInt64(config.DocumentMaxSize)
+
+	financeRoles := make([]model.RoleCode, 0)
+	for _, role := range viper.GetStringSlice(config.FinanceRoles) {
+		financeRoles = append(financeRoles, model.RoleCode(role))
+	}
+
+	contractorSrvc := service.NewContractorService(contractorRepo, auditRepo, outboxRepo, contactRepo, emailScope)
+	contactSrvc := service.NewContactService(contractorRepo, contactRepo, auditRepo, outboxRepo)
+	documentSrvc := service.NewDocumentService(contractorRepo, documentRepo, documentStorage,
+		documentMaxSize, viper.GetStringSlice(config.DocumentAllowedTypes))
+	contractSrvc := service.NewContractService(contractorRepo, contractRepo, auditRepo, outboxRepo)
+	tagSrvc := service.NewTagService(contractorRepo, tagRepo, auditRepo, outboxRepo)
+	bankAccountSrvc := service.NewBankAccountService(contractorRepo, bankAccountRepo, auditRepo)
+	savedSearchSrvc := service.NewSavedSearchService(savedSearchRepo)
+	changeSrvc := service.NewChangeService(changeRepo)
+	webhookSrvc := service.NewWebhookService(webhookRepo)
 
 	//region Contractor routes
 	api := r.PathPrefix("/api/v1/admin").Subrouter()
 	api.Use(middleware.AuthHandler(bpmsUserRepo))
 
-	controller.NewContractorController(contractorSrvc).HandleRoutes(api)
+	// Маршруты изменений регистрируются раньше контрагентов, чтобы `/contractors/stream` не совпал с `/contractors/{id}`
+	controller.NewChangeController(changeSrvc, broker, viper.GetDuration(config.SseHeartbeatInterval)).HandleRoutes(api)
+	controller.NewContractorController(contractorSrvc, savedSearchSrvc).HandleRoutes(api)
+	controller.NewContactController(contactSrvc).HandleRoutes(api)
+	controller.NewDocumentController(documentSrvc, documentMaxSize).HandleRoutes(api)
+	controller.NewContractController(contractSrvc).HandleRoutes(api)
+	controller.NewTagController(tagSrvc).HandleRoutes(api)
+	controller.NewBankAccountController(bankAccountSrvc, financeRoles).HandleRoutes(api)
+	controller.NewCountryController().HandleRoutes(api)
+	controller.NewSavedSearchController(savedSearchSrvc).HandleRoutes(api)
+	controller.NewWebhookController(webhookSrvc).HandleRoutes(api)
 	//endregion
 
 	return nil
@@ -0,0 +1,182 @@
+package backup
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"service_admin_contractor/domain/model"
+	"service_admin_contractor/domain/repository"
+	"time"
+)
+
+// maxLineSize ограничивает размер строки архива при восстановлении
+const maxLineSize = 16 * 1024 * 1024
+
+// Dump записывает в w архив NDJSON: заголовок, строки таблиц model.BackupTables и завершение с количеством строк.
+// Все таблицы читаются в одной транзакции, поэтому архив согласован.
+func Dump(ctx context.Context, br repository.BackupRepository, w io.Writer) (map[string]int64, error) {
+	tx, err := br.WithSnapshotTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer br.RollbackQuietly(tx, ctx)
+
+	buf := bufio.NewWriter(w)
+	encoder := json.NewEncoder(buf)
+
+	err = encoder.Encode(model.BackupLine{Header: &model.BackupHeader{
+		Format:    model.BackupFormat,
+		Version:   model.BackupVersion,
+		CreatedAt: time.Now().UTC(),
+		Tables:    model.BackupTables,
+	}})
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(model.BackupTables))
+	for _, table := range model.BackupTables {
+		err = br.ForEachTableRow(ctx, tx, table, func(row json.RawMessage) error {
+			counts[table]++
+			return encoder.Encode(model.BackupLine{Record: &model.BackupRecord{Table: table, Row: row}})
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", table, err)
+		}
+	}
+
+	if err = encoder.Encode(model.BackupLine{Trailer: &model.BackupTrailer{Counts: counts}}); err != nil {
+		return nil, err
+	}
+
+	return counts, buf.Flush()
+}
+
+// Restore загружает архив, созданный Dump, в пустые таблицы с сохранением id и сбрасывает последовательности.
+// Колонки, отсутствующие в архиве более старой версии схемы, получают значения по умолчанию.
+// Восстановление выполняется в одной транзакции: при любой ошибке, в том числе обрезанном архиве,
+// изменения не сохраняются.
+func Restore(ctx context.Context, br repository.BackupRepository, r io.Reader) (map[string]int64, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	header, err := readHeader(scanner)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := br.WithTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer br.RollbackQuietly(tx, ctx)
+
+	for _, table := range header.Tables {
+		count, err := br.CountTableRows(ctx, tx, table)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", table, err)
+		}
+		if count > 0 {
+			return nil, fmt.Errorf("таблица %s не пуста (%d строк), восстановление возможно только в пустую схему", table, count)
+		}
+	}
+
+	// Восстановленные строки не являются изменениями для потребителей журнала и потока изменений
+	if err = br.DisableChangeLog(ctx, tx); err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string][]string, len(header.Tables))
+	for _, table := range header.Tables {
+		if columns[table], err = br.FindTableColumns(ctx, tx, table); err != nil {
+			return nil, fmt.Errorf("%s: %w", table, err)
+		}
+	}
+
+	counts := make(map[string]int64, len(header.Tables))
+	var trailer *model.BackupTrailer
+	for lineNumber := 2; scanner.Scan(); lineNumber++ {
+		var line model.BackupLine
+		if err = json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			return nil, fmt.Errorf("строка %d: %w", lineNumber, err)
+		}
+
+		switch {
+		case trailer != nil:
+			return nil, fmt.Errorf("строка %d: данные после завершения архива", lineNumber)
+		case line.Trailer != nil:
+			trailer = line.Trailer
+		case line.Record != nil:
+			if !containsTable(header.Tables, line.Record.Table) {
+				return nil, fmt.Errorf("строка %d: таблица %s не указана в заголовке", lineNumber, line.Record.Table)
+			}
+			if err = br.InsertTableRow(ctx, tx, line.Record.Table, columns[line.Record.Table], line.Record.Row); err != nil {
+				return nil, fmt.Errorf("строка %d: %s: %w", lineNumber, line.Record.Table, err)
+			}
+			counts[line.Record.Table]++
+		default:
+			return nil, fmt.Errorf("строка %d: неизвестный тип строки", lineNumber)
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if trailer == nil {
+		return nil, fmt.Errorf("архив обрезан: отсутствует завершение")
+	}
+	for _, table := range header.Tables {
+		if trailer.Counts[table] != counts[table] {
+			return nil, fmt.Errorf("таблица %s: в архиве %d строк, ожидалось %d", table, counts[table], trailer.Counts[table])
+		}
+	}
+
+	for _, table := range header.Tables {
+		if err = br.ResetTableSequence(ctx, tx, table); err != nil {
+			return nil, fmt.Errorf("%s: %w", table, err)
+		}
+	}
+
+	return counts, tx.Commit(ctx)
+}
+
+func readHeader(scanner *bufio.Scanner) (*model.BackupHeader, error) {
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("архив пуст")
+	}
+
+	var line model.BackupLine
+	if err := json.Unmarshal(scanner.Bytes(), &line); err != nil || line.Header == nil {
+		return nil, fmt.Errorf("строка 1: ожидался заголовок архива")
+	}
+
+	header := line.Header
+	if header.Format != model.BackupFormat {
+		return nil, fmt.Errorf("неизвестный формат архива '%s'", header.Format)
+	}
+	if header.Version != model.BackupVersion {
+		return nil, fmt.Errorf("версия архива %d не поддерживается, ожидается %d", header.Version, model.BackupVersion)
+	}
+	for _, table := range header.Tables {
+		if !model.IsBackupTable(table) {
+			return nil, fmt.Errorf("таблица %s не входит в резервную копию", table)
+		}
+	}
+
+	return header, nil
+}
+
+func containsTable(tables []string, table string) bool {
+	for _, t := range tables {
+		if t == table {
+			return true
+		}
+	}
+
+	return false
+}
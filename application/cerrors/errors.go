@@ -1,9 +1,11 @@
 package cerrors
 
 import (
+	"errors"
 	"fmt"
 	"github.com/go-playground/validator/v10"
 	"net/http"
+	"service_admin_contractor/domain/model"
 )
 
 type AppError struct {
@@ -61,6 +63,9 @@ const (
 	BadRequest            = 50001
 	ConfigurationError    = 50002
 	ResourceNotFoundError = 50004
+	EntityNotFoundError   = 50005
+	EntityConflictError   = 50006
+	EntityValidationError = 50007
 
 	CouldNotOpenDbConnection = 51000
 	CouldNotPingDb           = 51001
@@ -68,6 +73,9 @@ const (
 	CouldNotGetContractorById = 52000
 	CouldNotCreateContractor  = 52001
 	CouldNotUpdateContractor  = 52002
+
+	SavedSearchAccessDenied = 53001
+	BankAccountWriteDenied  = 53002
 )
 
 // endregion
@@ -100,6 +108,11 @@ func ErrBadRequestVar(err error, name string) *AppError {
 
 			data = append(data, s)
 		}
+	} else if err != nil {
+		data = append(data, map[string]interface{}{
+			"problem_param":   name,
+			"problem_message": err.Error(),
+		})
 	}
 
 	ae.data = data
@@ -138,6 +151,64 @@ func ErrResourceNotFound(r *http.Request) error {
 	}
 }
 
+// ErrFromDomainError преобразует доменную ошибку (в том числе обернутую) в AppError
+// с соответствующим HTTP статусом. Для прочих ошибок возвращает nil.
+func ErrFromDomainError(err error) *AppError {
+	var notFound *model.NotFoundError
+	if errors.As(err, &notFound) {
+		return &AppError{
+			error:          err,
+			httpStatusCode: http.StatusNotFound,
+			code:           EntityNotFoundError,
+			userMessage:    notFound.Error(),
+			data: map[string]interface{}{
+				"entity": notFound.Entity,
+				"id":     notFound.Id,
+			},
+		}
+	}
+
+	var conflict *model.ConflictError
+	if errors.As(err, &conflict) {
+		data := map[string]interface{}{
+			"entity": conflict.Entity,
+		}
+		if conflict.Field != "" {
+			data["field"] = conflict.Field
+		}
+		if conflict.Value != nil {
+			data["value"] = conflict.Value
+		}
+		if conflict.ConflictingId != nil {
+			data["conflicting_id"] = *conflict.ConflictingId
+		}
+
+		return &AppError{
+			error:          err,
+			httpStatusCode: http.StatusConflict,
+			code:           EntityConflictError,
+			userMessage:    conflict.Message,
+			data:           data,
+		}
+	}
+
+	var validation *model.ValidationError
+	if errors.As(err, &validation) {
+		return &AppError{
+			error:          err,
+			httpStatusCode: http.StatusUnprocessableEntity,
+			code:           EntityValidationError,
+			userMessage:    "данные не прошли проверку",
+			data: []map[string]interface{}{{
+				"problem_param":   validation.Field,
+				"problem_message": validation.Message,
+			}},
+		}
+	}
+
+	return nil
+}
+
 func ErrCouldNotConnectToDb(err error) *AppError {
 	return &AppError{
 		error:       err,
@@ -181,4 +252,20 @@ func ErrCouldNotUpdateContractor(err error, text string) *AppError {
 	}
 }
 
+func ErrSavedSearchAccessDenied(id int64) *AppError {
+	return &AppError{
+		httpStatusCode: http.StatusForbidden,
+		code:           SavedSearchAccessDenied,
+		userMessage:    fmt.Sprintf("нет доступа к сохраненному поиску с ИД %d", id),
+	}
+}
+
+func ErrBankAccountWriteDenied() *AppError {
+	return &AppError{
+		httpStatusCode: http.StatusForbidden,
+		code:           BankAccountWriteDenied,
+		userMessage:    "изменять банковские счета могут только пользователи с финансовой ролью",
+	}
+}
+
 // endregion
@@ -23,6 +23,26 @@ const (
 	DatasourcesPostgresPassword = "DATASOURCES_POSTGRES_PASSWORD"
 	DatasourcesPostgresDatabase = "DATASOURCES_POSTGRES_DATABASE"
 	DatasourcesPostgresSchema   = "DATASOURCES_POSTGRES_SCHEMA"
+	OutboxEnabled               = "OUTBOX_ENABLED"
+	OutboxPublisher             = "OUTBOX_PUBLISHER"
+	OutboxPollInterval          = "OUTBOX_POLL_INTERVAL"
+	OutboxBatchSize             = "OUTBOX_BATCH_SIZE"
+	OutboxMaxAttempts           = "OUTBOX_MAX_ATTEMPTS"
+	WebhookEnabled              = "WEBHOOK_ENABLED"
+	WebhookPollInterval         = "WEBHOOK_POLL_INTERVAL"
+	WebhookBatchSize            = "WEBHOOK_BATCH_SIZE"
+	WebhookMaxAttempts          = "WEBHOOK_MAX_ATTEMPTS"
+	SseHeartbeatInterval        = "SSE_HEARTBEAT_INTERVAL"
+	EmployeeEmailScope          = "EMPLOYEE_EMAIL_SCOPE"
+	DocumentStoragePath         = "DOCUMENT_STORAGE_PATH"
+	DocumentMaxSize             = "DOCUMENT_MAX_SIZE"
+	DocumentAllowedTypes        = "DOCUMENT_ALLOWED_TYPES"
+	ContractMonitorEnabled      = "CONTRACT_MONITOR_ENABLED"
+	ContractMonitorInterval     = "CONTRACT_MONITOR_INTERVAL"
+	ContractMonitorBatchSize    = "CONTRACT_MONITOR_BATCH_SIZE"
+	ContractExpiryWarningDays   = "CONTRACT_EXPIRY_WARNING_DAYS"
+	ContractRequired            = "CONTRACT_REQUIRED"
+	FinanceRoles                = "FINANCE_ROLES"
 )
 
 var EncRegex = `(?m)ENC\((.*)\)`
@@ -51,9 +71,29 @@ var RequiredEnvs = []string{
 type defaultEnvValueGetter = func() interface{}
 
 var DefaultEnvs = map[string]interface{}{
-	LogLevel:           "info",
-	LogPrettyPrint:     false,
-	HttpRequestTimeout: time.Second * 60,
+	LogLevel:                  "info",
+	LogPrettyPrint:            false,
+	HttpRequestTimeout:        time.Second * 60,
+	OutboxEnabled:             true,
+	OutboxPublisher:           "webhook",
+	OutboxPollInterval:        time.Second * 5,
+	OutboxBatchSize:           100,
+	OutboxMaxAttempts:         10,
+	WebhookEnabled:            true,
+	WebhookPollInterval:       time.Second * 5,
+	WebhookBatchSize:          20,
+	WebhookMaxAttempts:        8,
+	SseHeartbeatInterval:      time.Second * 15,
+	EmployeeEmailScope:        "contractor",
+	DocumentStoragePath:       "./data/documents",
+	DocumentMaxSize:           20 << 20,
+	DocumentAllowedTypes:      "application/pdf image/jpeg image/png",
+	ContractMonitorEnabled:    true,
+	ContractMonitorInterval:   time.Hour,
+	ContractMonitorBatchSize:  100,
+	ContractExpiryWarningDays: 14,
+	ContractRequired:          false,
+	FinanceRoles:              "FINANCE",
 }
 
 // CheckEnv проверяет заданные ENV переменные
@@ -0,0 +1,71 @@
+package contract
+
+import (
+	"context"
+	log "github.com/sirupsen/logrus"
+	"service_admin_contractor/application/service"
+	"service_admin_contractor/domain/model"
+	"time"
+)
+
+// ExpiryMonitor периодически предупреждает об окончании последних действующих договоров контрагентов
+// за WarningDays дней и блокирует контрагентов, у которых не осталось действующих договоров.
+// Контрагенты, у которых договоров никогда не было, блокируются только при BlockWithoutContracts.
+type ExpiryMonitor struct {
+	s service.ContractService
+
+	PollInterval          time.Duration
+	WarningDays           int
+	BatchSize             int
+	BlockWithoutContracts bool
+}
+
+func NewExpiryMonitor(s service.ContractService) *ExpiryMonitor {
+	return &ExpiryMonitor{
+		s:            s,
+		PollInterval: time.Hour,
+		WarningDays:  14,
+		BatchSize:    100,
+	}
+}
+
+// Run проверяет договоры до отмены ctx
+func (m *ExpiryMonitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.Check(ctx, model.Today()); err != nil && ctx.Err() == nil {
+			log.Error("contract expiry monitor: ", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check обрабатывает все истекающие договоры и контрагентов без действующих договоров на дату today
+func (m *ExpiryMonitor) Check(ctx context.Context, today time.Time) error {
+	for {
+		count, err := m.s.WarnExpiringContracts(ctx, today, m.WarningDays, m.BatchSize)
+		if err != nil {
+			return err
+		}
+		if count < m.BatchSize {
+			break
+		}
+	}
+
+	for {
+		count, err := m.s.BlockContractorsWithoutContract(ctx, today, m.BlockWithoutContracts, m.BatchSize)
+		if err != nil {
+			return err
+		}
+		if count < m.BatchSize {
+			return nil
+		}
+	}
+}
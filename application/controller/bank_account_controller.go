@@ -0,0 +1,164 @@
+package controller
+
+import (
+	"github.com/gorilla/mux"
+	"net/http"
+	"service_admin_contractor/application/cerrors"
+	"service_admin_contractor/application/dto"
+	"service_admin_contractor/application/middleware"
+	"service_admin_contractor/application/respond"
+	"service_admin_contractor/application/service"
+	"service_admin_contractor/domain/model"
+)
+
+// BankAccountController отдает полные номера счетов только пользователям с одной из ролей financeRoles,
+// остальным номера отдаются маскированными. Изменять счета могут только пользователи с этими ролями.
+type BankAccountController struct {
+	s            service.BankAccountService
+	financeRoles []model.RoleCode
+}
+
+func NewBankAccountController(s service.BankAccountService, financeRoles []model.RoleCode) *BankAccountController {
+	return &BankAccountController{s, financeRoles}
+}
+
+func (c *BankAccountController) HandleRoutes(r *mux.Router) {
+	r.HandleFunc("/contractors/{id}/bank-accounts", c.GetBankAccounts).Methods(http.MethodOptions, http.MethodGet)
+	r.HandleFunc("/contractors/{id}/bank-accounts", c.CreateBankAccount).Methods(http.MethodOptions, http.MethodPost)
+	r.HandleFunc("/contractors/{id}/bank-accounts/{accountId}", c.GetBankAccount).Methods(http.MethodOptions, http.MethodGet)
+	r.HandleFunc("/contractors/{id}/bank-accounts/{accountId}", c.UpdateBankAccount).Methods(http.MethodOptions, http.MethodPut)
+	r.HandleFunc("/contractors/{id}/bank-accounts/{accountId}", c.DeleteBankAccount).Methods(http.MethodOptions, http.MethodDelete)
+}
+
+func (c *BankAccountController) GetBankAccounts(w http.ResponseWriter, r *http.Request) {
+	contractorId, err := parsePathId(r, "id")
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	res, err := c.s.FindBankAccounts(r.Context(), contractorId)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	respond.With(w, r, dto.ConvertBankAccounts(res, c.mask(r)))
+}
+
+func (c *BankAccountController) GetBankAccount(w http.ResponseWriter, r *http.Request) {
+	contractorId, accountId, err := parseBankAccountPath(r)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	data, err := c.s.GetBankAccount(r.Context(), contractorId, accountId)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	respond.With(w, r, dto.ConvertBankAccount(data, c.mask(r)))
+}
+
+func (c *BankAccountController) CreateBankAccount(w http.ResponseWriter, r *http.Request) {
+	if !c.isFinanceUser(r) {
+		respond.WithError(w, r, cerrors.ErrBankAccountWriteDenied())
+		return
+	}
+
+	contractorId, err := parsePathId(r, "id")
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	requestDto := &dto.BankAccountDto{}
+	if err = decodeAndValidate(r, requestDto); err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	account := dto.ConvertBankAccountDtoToEntity(contractorId, requestDto)
+	if err = c.s.CreateBankAccount(r.Context(), *middleware.GetUserInfo(r.Context()), account); err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	respond.With(w, r, dto.ConvertBankAccount(*account, false))
+}
+
+func (c *BankAccountController) UpdateBankAccount(w http.ResponseWriter, r *http.Request) {
+	if !c.isFinanceUser(r) {
+		respond.WithError(w, r, cerrors.ErrBankAccountWriteDenied())
+		return
+	}
+
+	contractorId, accountId, err := parseBankAccountPath(r)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	requestDto := &dto.BankAccountDto{}
+	if err = decodeAndValidate(r, requestDto); err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	requestDto.Id = accountId
+	account := dto.ConvertBankAccountDtoToEntity(contractorId, requestDto)
+	if err = c.s.UpdateBankAccount(r.Context(), *middleware.GetUserInfo(r.Context()), account); err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	respond.With(w, r, dto.ConvertBankAccount(*account, false))
+}
+
+func (c *BankAccountController) DeleteBankAccount(w http.ResponseWriter, r *http.Request) {
+	if !c.isFinanceUser(r) {
+		respond.WithError(w, r, cerrors.ErrBankAccountWriteDenied())
+		return
+	}
+
+	contractorId, accountId, err := parseBankAccountPath(r)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	err = c.s.DeleteBankAccount(r.Context(), *middleware.GetUserInfo(r.Context()), contractorId, accountId)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	respond.With(w, r, true)
+}
+
+// mask возвращает true, если у пользователя запроса нет финансовой роли
+func (c *BankAccountController) mask(r *http.Request) bool {
+	return !c.isFinanceUser(r)
+}
+
+// isFinanceUser возвращает true, если у пользователя запроса есть одна из ролей financeRoles
+func (c *BankAccountController) isFinanceUser(r *http.Request) bool {
+	user := middleware.GetUserInfo(r.Context())
+	return user != nil && user.HasAnyRole(c.financeRoles)
+}
+
+func parseBankAccountPath(r *http.Request) (int64, int64, error) {
+	contractorId, err := parsePathId(r, "id")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	accountId, err := parsePathId(r, "accountId")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return contractorId, accountId, nil
+}
@@ -0,0 +1,159 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/mux"
+	"net/http"
+	"service_admin_contractor/application/cerrors"
+	"service_admin_contractor/application/dto"
+	"service_admin_contractor/application/respond"
+	"service_admin_contractor/application/service"
+	"service_admin_contractor/application/stream"
+	"service_admin_contractor/domain/model"
+	"time"
+)
+
+// streamReplayLimit является размером страницы журнала при досылке пропущенных изменений
+const streamReplayLimit = 1000
+
+type ChangeController struct {
+	s         service.ChangeService
+	b         *stream.Broker
+	heartbeat time.Duration
+}
+
+func NewChangeController(s service.ChangeService, b *stream.Broker, heartbeat time.Duration) *ChangeController {
+	return &ChangeController{s, b, heartbeat}
+}
+
+// HandleRoutes регистрирует маршруты. Должен вызываться до регистрации `/contractors/{id}`,
+// иначе `/contractors/stream` будет принят за ИД контрагента.
+func (c *ChangeController) HandleRoutes(r *mux.Router) {
+	r.HandleFunc("/changes", c.GetChanges).Methods(http.MethodOptions, http.MethodGet)
+	r.HandleFunc("/contractors/stream", c.StreamChanges).Methods(http.MethodOptions, http.MethodGet)
+}
+
+func (c *ChangeController) GetChanges(w http.ResponseWriter, r *http.Request) {
+	since, limit, err := dto.ParseChangeFeedParameters(r.URL.Query())
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	feed, err := c.s.FindChanges(r.Context(), since, limit)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	respond.With(w, r, dto.ConvertChangeFeed(feed))
+}
+
+// StreamChanges передает изменения контрагентов и сотрудников как Server-Sent Events.
+// Идентификатором события является токен журнала изменений: при переподключении с Last-Event-ID
+// пропущенные изменения досылаются из журнала, без него передаются только новые изменения.
+// Уведомления об изменениях и пинги только будят поток: изменения всегда читаются из журнала
+// после последнего отправленного токена, поэтому поток отдает те же записи, что и `GET /changes`.
+func (c *ChangeController) StreamChanges(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		respond.WithError(w, r, cerrors.ErrInternalServerError(fmt.Errorf("потоковая передача не поддерживается")))
+		return
+	}
+
+	lastEventId := r.Header.Get("Last-Event-ID")
+	if lastEventId == "" {
+		lastEventId = r.URL.Query().Get("lastEventId")
+	}
+
+	ctx := r.Context()
+
+	// Подписка оформляется до чтения журнала, чтобы не пропустить уведомления об изменениях,
+	// зафиксированных во время чтения
+	changes, unsubscribe := c.b.Subscribe()
+	defer unsubscribe()
+
+	var last model.ChangeToken
+	if lastEventId != "" {
+		token, err := model.ParseChangeToken(lastEventId)
+		if err != nil {
+			respond.WithError(w, r, cerrors.ErrBadRequestVar(err, "Last-Event-ID"))
+			return
+		}
+		last = token
+	} else {
+		token, err := c.s.GetLatestChangeToken(ctx)
+		if err != nil {
+			respond.WithError(w, r, err)
+			return
+		}
+		last = token
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(c.heartbeat)
+	defer heartbeat.Stop()
+
+	var err error
+	for {
+		if last, err = c.replayChanges(ctx, w, last); err != nil {
+			return
+		}
+		flusher.Flush()
+
+		// Журнал перечитывается и по пингу: запись, скрытая во время уведомления более старой транзакцией,
+		// будет отправлена после ее завершения
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-changes:
+			if !ok {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err = fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// replayChanges отправляет изменения журнала после since и возвращает токен последнего из них
+func (c *ChangeController) replayChanges(ctx context.Context, w http.ResponseWriter,
+	since model.ChangeToken) (model.ChangeToken, error) {
+	for {
+		feed, err := c.s.FindChanges(ctx, since, streamReplayLimit)
+		if err != nil {
+			return since, err
+		}
+
+		for _, change := range feed.Changes {
+			if err = writeChangeEvent(w, change); err != nil {
+				return since, err
+			}
+		}
+
+		since = feed.Next
+		if !feed.HasMore {
+			return since, nil
+		}
+	}
+}
+
+func writeChangeEvent(w http.ResponseWriter, change model.Change) error {
+	data, err := json.Marshal(dto.ConvertChange(change))
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %s\nevent: change\ndata: %s\n\n", change.Token.String(), data)
+	return err
+}
@@ -0,0 +1,243 @@
+package controller
+
+import (
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"net/http"
+	"service_admin_contractor/application/cerrors"
+	"service_admin_contractor/application/cvalidator"
+	"service_admin_contractor/application/dto"
+	"service_admin_contractor/application/middleware"
+	"service_admin_contractor/application/respond"
+	"service_admin_contractor/application/service"
+)
+
+type ContactController struct {
+	s service.ContactService
+}
+
+func NewContactController(s service.ContactService) *ContactController {
+	return &ContactController{s}
+}
+
+func (c *ContactController) HandleRoutes(r *mux.Router) {
+	r.HandleFunc("/contractors/{id}/addresses", c.GetAddresses).Methods(http.MethodOptions, http.MethodGet)
+	r.HandleFunc("/contractors/{id}/addresses", c.CreateAddress).Methods(http.MethodOptions, http.MethodPost)
+	r.HandleFunc("/contractors/{id}/addresses/{addressId}", c.UpdateAddress).Methods(http.MethodOptions, http.MethodPut)
+	r.HandleFunc("/contractors/{id}/addresses/{addressId}", c.DeleteAddress).Methods(http.MethodOptions, http.MethodDelete)
+
+	r.HandleFunc("/contractors/{id}/contacts", c.GetContacts).Methods(http.MethodOptions, http.MethodGet)
+	r.HandleFunc("/contractors/{id}/contacts", c.CreateContact).Methods(http.MethodOptions, http.MethodPost)
+	r.HandleFunc("/contractors/{id}/contacts/{contactId}", c.GetContact).Methods(http.MethodOptions, http.MethodGet)
+	r.HandleFunc("/contractors/{id}/contacts/{contactId}", c.UpdateContact).Methods(http.MethodOptions, http.MethodPut)
+	r.HandleFunc("/contractors/{id}/contacts/{contactId}", c.DeleteContact).Methods(http.MethodOptions, http.MethodDelete)
+}
+
+func (c *ContactController) GetAddresses(w http.ResponseWriter, r *http.Request) {
+	contractorId, err := parsePathId(r, "id")
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	res, err := c.s.FindAddresses(r.Context(), contractorId)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	respond.With(w, r, dto.ConvertAddresses(res))
+}
+
+func (c *ContactController) CreateAddress(w http.ResponseWriter, r *http.Request) {
+	contractorId, err := parsePathId(r, "id")
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	requestDto := &dto.AddressDto{}
+	if err = decodeAndValidate(r, requestDto); err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	address := dto.ConvertAddressDtoToEntity(contractorId, requestDto)
+	if err = c.s.CreateAddress(r.Context(), *middleware.GetUserInfo(r.Context()), address); err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	respond.With(w, r, dto.ConvertAddress(*address))
+}
+
+func (c *ContactController) UpdateAddress(w http.ResponseWriter, r *http.Request) {
+	contractorId, err := parsePathId(r, "id")
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	addressId, err := parsePathId(r, "addressId")
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	requestDto := &dto.AddressDto{}
+	if err = decodeAndValidate(r, requestDto); err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	address := dto.ConvertAddressDtoToEntity(contractorId, requestDto)
+	address.Id = addressId
+	if err = c.s.UpdateAddress(r.Context(), *middleware.GetUserInfo(r.Context()), address); err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	respond.With(w, r, dto.ConvertAddress(*address))
+}
+
+func (c *ContactController) DeleteAddress(w http.ResponseWriter, r *http.Request) {
+	contractorId, err := parsePathId(r, "id")
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	addressId, err := parsePathId(r, "addressId")
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	if err = c.s.DeleteAddress(r.Context(), *middleware.GetUserInfo(r.Context()), contractorId, addressId); err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	respond.With(w, r, true)
+}
+
+func (c *ContactController) GetContacts(w http.ResponseWriter, r *http.Request) {
+	contractorId, err := parsePathId(r, "id")
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	res, err := c.s.FindContacts(r.Context(), contractorId)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	respond.With(w, r, dto.ConvertContacts(res))
+}
+
+func (c *ContactController) GetContact(w http.ResponseWriter, r *http.Request) {
+	contractorId, err := parsePathId(r, "id")
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	contactId, err := parsePathId(r, "contactId")
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	data, err := c.s.GetContact(r.Context(), contractorId, contactId)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	respond.With(w, r, dto.ConvertContact(data))
+}
+
+func (c *ContactController) CreateContact(w http.ResponseWriter, r *http.Request) {
+	contractorId, err := parsePathId(r, "id")
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	requestDto := &dto.ContactDto{}
+	if err = decodeAndValidate(r, requestDto); err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	contact := dto.ConvertContactDtoToEntity(contractorId, requestDto)
+	if err = c.s.CreateContact(r.Context(), *middleware.GetUserInfo(r.Context()), contact); err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	respond.With(w, r, dto.ConvertContact(*contact))
+}
+
+func (c *ContactController) UpdateContact(w http.ResponseWriter, r *http.Request) {
+	contractorId, err := parsePathId(r, "id")
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	contactId, err := parsePathId(r, "contactId")
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	requestDto := &dto.ContactDto{}
+	if err = decodeAndValidate(r, requestDto); err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	contact := dto.ConvertContactDtoToEntity(contractorId, requestDto)
+	contact.Id = contactId
+	if err = c.s.UpdateContact(r.Context(), *middleware.GetUserInfo(r.Context()), contact); err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	respond.With(w, r, dto.ConvertContact(*contact))
+}
+
+func (c *ContactController) DeleteContact(w http.ResponseWriter, r *http.Request) {
+	contractorId, err := parsePathId(r, "id")
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	contactId, err := parsePathId(r, "contactId")
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	if err = c.s.DeleteContact(r.Context(), *middleware.GetUserInfo(r.Context()), contractorId, contactId); err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	respond.With(w, r, true)
+}
+
+// decodeAndValidate разбирает тело запроса в requestDto и проверяет его
+func decodeAndValidate(r *http.Request, requestDto interface{}) error {
+	defer r.Body.Close()
+	err := json.NewDecoder(r.Body).Decode(requestDto)
+	if err != nil {
+		return cerrors.ErrCouldNotDecodeBody(err)
+	}
+
+	return cvalidator.Validate.Struct(requestDto)
+}
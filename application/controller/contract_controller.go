@@ -0,0 +1,151 @@
+package controller
+
+import (
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"net/http"
+	"service_admin_contractor/application/cerrors"
+	"service_admin_contractor/application/cvalidator"
+	"service_admin_contractor/application/dto"
+	"service_admin_contractor/application/middleware"
+	"service_admin_contractor/application/respond"
+	"service_admin_contractor/application/service"
+)
+
+type ContractController struct {
+	s service.ContractService
+}
+
+func NewContractController(s service.ContractService) *ContractController {
+	return &ContractController{s}
+}
+
+func (c *ContractController) HandleRoutes(r *mux.Router) {
+	r.HandleFunc("/contractors/{id}/contracts", c.GetContracts).Methods(http.MethodOptions, http.MethodGet)
+	r.HandleFunc("/contractors/{id}/contracts", c.CreateContract).Methods(http.MethodOptions, http.MethodPost)
+	r.HandleFunc("/contractors/{id}/contracts/{contractId}", c.GetContract).Methods(http.MethodOptions, http.MethodGet)
+	r.HandleFunc("/contractors/{id}/contracts/{contractId}", c.UpdateContract).Methods(http.MethodOptions, http.MethodPut)
+	r.HandleFunc("/contractors/{id}/contracts/{contractId}", c.DeleteContract).Methods(http.MethodOptions, http.MethodDelete)
+}
+
+func (c *ContractController) GetContracts(w http.ResponseWriter, r *http.Request) {
+	contractorId, err := parsePathId(r, "id")
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	res, err := c.s.FindContracts(r.Context(), contractorId)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	respond.With(w, r, dto.ConvertContracts(res))
+}
+
+func (c *ContractController) GetContract(w http.ResponseWriter, r *http.Request) {
+	contractorId, contractId, err := parseContractPath(r)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	data, err := c.s.GetContract(r.Context(), contractorId, contractId)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	respond.With(w, r, dto.ConvertContract(data))
+}
+
+func (c *ContractController) CreateContract(w http.ResponseWriter, r *http.Request) {
+	contractorId, err := parsePathId(r, "id")
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	requestDto, err := decodeContract(r)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	contract := dto.ConvertContractDtoToEntity(contractorId, requestDto)
+	if err = c.s.CreateContract(r.Context(), *middleware.GetUserInfo(r.Context()), contract); err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	respond.With(w, r, dto.ConvertContract(*contract))
+}
+
+func (c *ContractController) UpdateContract(w http.ResponseWriter, r *http.Request) {
+	contractorId, contractId, err := parseContractPath(r)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	requestDto, err := decodeContract(r)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	requestDto.Id = contractId
+	contract := dto.ConvertContractDtoToEntity(contractorId, requestDto)
+	if err = c.s.UpdateContract(r.Context(), *middleware.GetUserInfo(r.Context()), contract); err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	respond.With(w, r, dto.ConvertContract(*contract))
+}
+
+func (c *ContractController) DeleteContract(w http.ResponseWriter, r *http.Request) {
+	contractorId, contractId, err := parseContractPath(r)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	err = c.s.DeleteContract(r.Context(), *middleware.GetUserInfo(r.Context()), contractorId, contractId)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	respond.With(w, r, true)
+}
+
+func parseContractPath(r *http.Request) (int64, int64, error) {
+	contractorId, err := parsePathId(r, "id")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	contractId, err := parsePathId(r, "contractId")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return contractorId, contractId, nil
+}
+
+func decodeContract(r *http.Request) (*dto.ContractDto, error) {
+	requestDto := &dto.ContractDto{}
+	defer r.Body.Close()
+	err := json.NewDecoder(r.Body).Decode(requestDto)
+	if err != nil {
+		return nil, cerrors.ErrCouldNotDecodeBody(err)
+	}
+
+	if err = cvalidator.ValidateStruct(requestDto); err != nil {
+		return nil, err
+	}
+
+	return requestDto, nil
+}
@@ -0,0 +1,42 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"service_admin_contractor/application/cerrors"
+	"service_admin_contractor/application/cvalidator"
+	"service_admin_contractor/application/dto"
+	"service_admin_contractor/application/middleware"
+	"service_admin_contractor/application/respond"
+)
+
+func (c *ContractorController) BulkContractorAction(w http.ResponseWriter, r *http.Request) {
+	requestDto := &dto.ContractorBulkActionDto{}
+	defer r.Body.Close()
+	err := json.NewDecoder(r.Body).Decode(&requestDto)
+	if err != nil {
+		respond.WithError(w, r, cerrors.ErrCouldNotDecodeBody(err))
+		return
+	}
+
+	err = cvalidator.Validate.Struct(requestDto)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	request, err := dto.ConvertContractorBulkActionDtoToEntity(requestDto)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	ctx := r.Context()
+	result, err := c.s.BulkContractorAction(ctx, *middleware.GetUserInfo(ctx), *request)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	respond.With(w, r, dto.ConvertContractorBulkResult(*result))
+}
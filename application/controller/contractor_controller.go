@@ -4,30 +4,39 @@ import (
 	"encoding/json"
 	"github.com/gorilla/mux"
 	"net/http"
+	"net/url"
 	"service_admin_contractor/application/cerrors"
 	"service_admin_contractor/application/cvalidator"
 	"service_admin_contractor/application/dto"
+	"service_admin_contractor/application/middleware"
 	"service_admin_contractor/application/respond"
 	"service_admin_contractor/application/service"
+	"service_admin_contractor/domain/model"
 	"strconv"
 )
 
 type ContractorController struct {
-	s service.ContractorService
+	s  service.ContractorService
+	ss service.SavedSearchService
 }
 
-func NewContractorController(s service.ContractorService) *ContractorController {
-	return &ContractorController{s}
+func NewContractorController(s service.ContractorService, ss service.SavedSearchService) *ContractorController {
+	return &ContractorController{s, ss}
 }
 
 func (c *ContractorController) HandleRoutes(r *mux.Router) {
 	r.HandleFunc("/contractors", c.GetAllContractors).Methods(http.MethodOptions, http.MethodGet)
 	r.HandleFunc("/contractors", c.CreateContractor).Methods(http.MethodOptions, http.MethodPost)
+	r.HandleFunc("/contractors/import", c.ImportContractors).Methods(http.MethodOptions, http.MethodPost)
+	r.HandleFunc("/contractors/bulk", c.BulkContractorAction).Methods(http.MethodOptions, http.MethodPost)
+	r.HandleFunc("/contractors/export.csv", c.ExportContractorsCsv).Methods(http.MethodOptions, http.MethodGet)
 	r.HandleFunc("/contractors/{id}", c.GetContractor).Methods(http.MethodOptions, http.MethodGet)
 	r.HandleFunc("/contractors/{id}", c.UpdateContractor).Methods(http.MethodOptions, http.MethodPut)
 	r.HandleFunc("/contractors/{id}", c.DeleteContractor).Methods(http.MethodOptions, http.MethodDelete)
+	r.HandleFunc("/contractors/{id}/tree", c.GetContractorTree).Methods(http.MethodOptions, http.MethodGet)
 
 	r.HandleFunc("/contractors/{id}/employee", c.CreateContractorEmployee).Methods(http.MethodOptions, http.MethodPost)
+	r.HandleFunc("/contractors/{id}/employee/bulk", c.UpsertContractorEmployees).Methods(http.MethodOptions, http.MethodPost)
 	r.HandleFunc("/contractors/{id}/employee/{employeeId}", c.UpdateContractorEmployee).Methods(http.MethodOptions, http.MethodPut)
 	r.HandleFunc("/contractors/{id}/employee/{employeeId}", c.DeleteContractorEmployee).Methods(http.MethodOptions, http.MethodDelete)
 
@@ -42,19 +51,70 @@ func (c *ContractorController) GetAllContractors(w http.ResponseWriter, r *http.
 		return
 	}
 
-	searchParameters, err := dto.ParseContractorSearchParameters(r.Form)
+	values, err := c.applySavedSearch(r)
 	if err != nil {
 		respond.WithError(w, r, err)
 		return
 	}
 
+	searchParameters, err := dto.ParseContractorSearchParameters(values)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	if acceptsXlsx(r) {
+		c.exportContractorsXlsx(w, r, *searchParameters)
+		return
+	}
+
 	res, total, err := c.s.FindContractors(r.Context(), *searchParameters)
 	if err != nil {
 		respond.WithError(w, r, err)
 		return
 	}
 
-	respond.WithPagination(w, r, dto.ConvertContractors(res), total)
+	if len(searchParameters.Facets) == 0 {
+		respond.WithPagination(w, r, dto.ConvertContractors(res), total)
+		return
+	}
+
+	facets, err := c.s.FindContractorFacets(r.Context(), *searchParameters)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	result := dto.ConvertContractors(res)
+	respond.WithMeta(w, r, result, dto.ContractorSearchMetaDto{
+		PaginationMetaDto: dto.PaginationMetaDto{Total: total, Count: int64(len(result))},
+		Facets:            dto.ConvertContractorFacets(facets),
+	})
+}
+
+// applySavedSearch подставляет параметры сохраненного поиска, указанного в параметре `savedSearch`
+func (c *ContractorController) applySavedSearch(r *http.Request) (url.Values, error) {
+	rid := r.Form.Get("savedSearch")
+	if rid == "" {
+		return r.Form, nil
+	}
+
+	err := cvalidator.Validate.Var(rid, "numeric")
+	if err != nil {
+		return nil, cerrors.ErrBadRequestVar(err, "savedSearch")
+	}
+
+	id, err := strconv.ParseInt(rid, 10, 64)
+	if err != nil {
+		return nil, cerrors.ErrBadRequestVar(err, "savedSearch")
+	}
+
+	search, err := c.ss.GetSavedSearch(r.Context(), *middleware.GetUserInfo(r.Context()), id)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ApplySavedSearch(search, r.Form)
 }
 
 func (c *ContractorController) GetContractor(w http.ResponseWriter, r *http.Request) {
@@ -126,6 +186,12 @@ func (c *ContractorController) UpdateContractor(w http.ResponseWriter, r *http.R
 		return
 	}
 
+	propagate, err := dto.ParseBoolFilter(r.URL.Query(), "propagate", false)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
 	requestDto := &dto.ContractorDto{}
 	defer r.Body.Close()
 	err = json.NewDecoder(r.Body).Decode(&requestDto)
@@ -134,6 +200,23 @@ func (c *ContractorController) UpdateContractor(w http.ResponseWriter, r *http.R
 		return
 	}
 
+	// Адреса и контактные лица изменяются отдельными запросами, молча игнорировать их нельзя
+	if requestDto.Addresses != nil {
+		respond.WithError(w, r, model.NewValidationError("addresses",
+			"адреса изменяются через /contractors/{id}/addresses"))
+		return
+	}
+	if requestDto.Contacts != nil {
+		respond.WithError(w, r, model.NewValidationError("contacts",
+			"контактные лица изменяются через /contractors/{id}/contacts"))
+		return
+	}
+
+	if err = requestDto.ValidateUpdate(); err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
 	contractor := dto.ConvertContractorDtoToEntity(requestDto)
 	if err != nil {
 		respond.WithError(w, r, err)
@@ -141,7 +224,7 @@ func (c *ContractorController) UpdateContractor(w http.ResponseWriter, r *http.R
 	}
 
 	ctx := r.Context()
-	err = c.s.UpdateContractor(ctx, id, contractor)
+	err = c.s.UpdateContractor(ctx, id, contractor, propagate)
 	if err != nil {
 		respond.WithError(w, r, err)
 		return
@@ -163,7 +246,7 @@ func (c *ContractorController) DeleteContractor(w http.ResponseWriter, r *http.R
 		respond.WithError(w, r, err)
 		return
 	}
-	err = c.s.DeleteContractor(id)
+	err = c.s.DeleteContractor(r.Context(), id)
 	if err != nil {
 		respond.WithError(w, r, err)
 		return
@@ -172,6 +255,23 @@ func (c *ContractorController) DeleteContractor(w http.ResponseWriter, r *http.R
 	respond.With(w, r, true)
 }
 
+// GetContractorTree возвращает иерархию, в которую входит контрагент, начиная с ее корня
+func (c *ContractorController) GetContractorTree(w http.ResponseWriter, r *http.Request) {
+	id, err := parsePathId(r, "id")
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	nodes, err := c.s.GetContractorTree(r.Context(), id)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	respond.With(w, r, dto.ConvertContractorTree(nodes))
+}
+
 func (c *ContractorController) CreateContractorEmployee(w http.ResponseWriter, r *http.Request) {
 	rid := mux.Vars(r)["id"]
 	err := cvalidator.Validate.Var(rid, "required,numeric")
@@ -238,6 +338,12 @@ func (c *ContractorController) UpdateContractorEmployee(w http.ResponseWriter, r
 		return
 	}
 
+	err = cvalidator.Validate.Struct(requestDto)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
 	employee := dto.ConvertEmployeeDtoToEntity(requestDto)
 	if err != nil {
 		respond.WithError(w, r, err)
@@ -267,7 +373,7 @@ func (c *ContractorController) DeleteContractorEmployee(w http.ResponseWriter, r
 		respond.WithError(w, r, err)
 		return
 	}
-	err = c.s.DeleteContractorEmployee(id)
+	err = c.s.DeleteContractorEmployee(r.Context(), id)
 	if err != nil {
 		respond.WithError(w, r, err)
 		return
@@ -0,0 +1,172 @@
+package controller
+
+import (
+	"encoding/csv"
+	"net/http"
+	"service_admin_contractor/application/dto"
+	"service_admin_contractor/application/export"
+	"service_admin_contractor/application/respond"
+	"service_admin_contractor/domain/model"
+	"service_admin_contractor/infrastructure/logging"
+	"strings"
+)
+
+const (
+	contentTypeCsv = "text/csv; charset=utf-8"
+	utf8Bom        = "\xEF\xBB\xBF"
+
+	// csvFlushRows определяет, через сколько строк выгрузка отправляется клиенту
+	csvFlushRows = 500
+)
+
+func (c *ContractorController) ExportContractorsCsv(w http.ResponseWriter, r *http.Request) {
+	err := r.ParseForm()
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	values, err := c.applySavedSearch(r)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	params, err := dto.ParseContractorExportParameters(values)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	export := c.s.ExportContractors
+	filename := "contractors.csv"
+	if params.WithEmployees {
+		export = c.s.ExportContractorEmployees
+		filename = "contractor_employees.csv"
+	}
+
+	cw := newCsvExportWriter(w, r, params, filename)
+	err = export(r.Context(), params.Search, cw.Write)
+	if err == nil {
+		err = cw.Close()
+	}
+
+	if err != nil {
+		if !cw.started {
+			respond.WithError(w, r, err)
+			return
+		}
+		logging.GetLogEntry(r).WithError(err).Error("выгрузка контрагентов в CSV прервана")
+	}
+}
+
+// csvExportWriter откладывает запись заголовков ответа до первой строки,
+// чтобы ошибки запроса к БД можно было вернуть в обычном формате
+type csvExportWriter struct {
+	w        http.ResponseWriter
+	r        *http.Request
+	params   *dto.ContractorExportParameters
+	filename string
+	csv      *csv.Writer
+	started  bool
+	rows     int
+}
+
+func newCsvExportWriter(w http.ResponseWriter, r *http.Request, params *dto.ContractorExportParameters,
+	filename string) *csvExportWriter {
+	cw := csv.NewWriter(w)
+	cw.Comma = params.Delimiter
+	cw.UseCRLF = true
+
+	return &csvExportWriter{w: w, r: r, params: params, filename: filename, csv: cw}
+}
+
+func (e *csvExportWriter) start() error {
+	e.started = true
+	respond.WithAttachment(e.w, e.r, contentTypeCsv, e.filename)
+	e.w.WriteHeader(http.StatusOK)
+
+	if e.params.Bom {
+		if _, err := e.w.Write([]byte(utf8Bom)); err != nil {
+			return err
+		}
+	}
+
+	return e.csv.Write(dto.ExportHeaders(e.params.Columns))
+}
+
+func (e *csvExportWriter) Write(row model.ContractorEmployeeRow) error {
+	if !e.started {
+		if err := e.start(); err != nil {
+			return err
+		}
+	}
+
+	if err := e.csv.Write(dto.ExportRecord(e.params.Columns, row)); err != nil {
+		return err
+	}
+
+	e.rows++
+	if e.rows%csvFlushRows == 0 {
+		return e.flush()
+	}
+
+	return nil
+}
+
+func (e *csvExportWriter) Close() error {
+	if !e.started {
+		if err := e.start(); err != nil {
+			return err
+		}
+	}
+
+	return e.flush()
+}
+
+func (e *csvExportWriter) flush() error {
+	e.csv.Flush()
+	if err := e.csv.Error(); err != nil {
+		return err
+	}
+
+	if f, ok := e.w.(http.Flusher); ok {
+		f.Flush()
+	}
+
+	return nil
+}
+
+// acceptsXlsx возвращает true, если клиент запросил ответ в виде книги Excel
+func acceptsXlsx(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), export.ContentTypeXlsx)
+}
+
+// exportContractorsXlsx отдает всех контрагентов, удовлетворяющих фильтрам, вместе с сотрудниками
+// в виде книги Excel. Пагинация не применяется.
+func (c *ContractorController) exportContractorsXlsx(w http.ResponseWriter, r *http.Request,
+	params model.ContractorSearchParameters) {
+	params.Pagination = *model.NewMaxPagination()
+	params.Facets = nil
+	params.WithEmployees = true
+
+	res, _, err := c.s.FindContractors(r.Context(), params)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	workbook, err := export.ContractorWorkbook(res)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+	defer workbook.Close()
+
+	respond.WithAttachment(w, r, export.ContentTypeXlsx, "contractors.xlsx")
+	w.WriteHeader(http.StatusOK)
+
+	if err = workbook.Write(w); err != nil {
+		logging.GetLogEntry(r).WithError(err).Error("выгрузка контрагентов в XLSX прервана")
+	}
+}
@@ -0,0 +1,125 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"service_admin_contractor/application/cerrors"
+	"service_admin_contractor/application/dto"
+	"service_admin_contractor/application/importer"
+	"service_admin_contractor/application/respond"
+	"service_admin_contractor/domain/model"
+	"strings"
+)
+
+const importMaxFileSize = 10 << 20
+
+func (c *ContractorController) ImportContractors(w http.ResponseWriter, r *http.Request) {
+	dryRun, err := dto.ParseBoolFilter(r.URL.Query(), "dryRun", false)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, importMaxFileSize)
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		respond.WithError(w, r, cerrors.ErrCouldNotDecodeBody(err))
+		return
+	}
+	defer file.Close()
+
+	format, err := importer.DetectFormat(header.Filename, header.Header.Get("Content-Type"))
+	if err != nil {
+		respond.WithError(w, r, cerrors.ErrBadRequestVar(err, "file"))
+		return
+	}
+
+	rows, err := importer.ReadContractorImportRows(file, format)
+	if err != nil {
+		respond.WithError(w, r, cerrors.ErrBadRequestVar(err, "file"))
+		return
+	}
+
+	report, err := c.s.ImportContractors(r.Context(), rows, dryRun)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	statusCode := http.StatusOK
+	if report.Failed > 0 && !dryRun {
+		statusCode = http.StatusUnprocessableEntity
+	}
+
+	respond.WithStatus(w, r, statusCode, dto.ConvertContractorImportReport(*report), nil)
+}
+
+// UpsertContractorEmployees принимает JSON массив сотрудников, CSV в теле запроса
+// или CSV/XLSX файл в поле `file` multipart формы
+func (c *ContractorController) UpsertContractorEmployees(w http.ResponseWriter, r *http.Request) {
+	contractorId, err := parsePathId(r, "id")
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, importMaxFileSize)
+	defer r.Body.Close()
+
+	rows, err := readEmployeeImportRows(r)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	report, err := c.s.UpsertContractorEmployees(r.Context(), contractorId, rows)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	respond.With(w, r, dto.ConvertEmployeeImportReport(*report))
+}
+
+func readEmployeeImportRows(r *http.Request) ([]model.EmployeeImportRow, error) {
+	contentType := r.Header.Get("Content-Type")
+
+	if strings.HasPrefix(contentType, "multipart/form-data") {
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			return nil, cerrors.ErrCouldNotDecodeBody(err)
+		}
+		defer file.Close()
+
+		format, err := importer.DetectFormat(header.Filename, header.Header.Get("Content-Type"))
+		if err != nil {
+			return nil, cerrors.ErrBadRequestVar(err, "file")
+		}
+
+		rows, err := importer.ReadEmployeeImportRows(file, format)
+		if err != nil {
+			return nil, cerrors.ErrBadRequestVar(err, "file")
+		}
+		return rows, nil
+	}
+
+	if strings.HasPrefix(contentType, "text/csv") {
+		rows, err := importer.ReadEmployeeImportRows(r.Body, importer.FormatCsv)
+		if err != nil {
+			return nil, cerrors.ErrCouldNotDecodeBody(err)
+		}
+		return rows, nil
+	}
+
+	requestDtos := make([]dto.EmployeeDto, 0)
+	if err := json.NewDecoder(r.Body).Decode(&requestDtos); err != nil {
+		return nil, cerrors.ErrCouldNotDecodeBody(err)
+	}
+
+	rows := make([]model.EmployeeImportRow, len(requestDtos))
+	for i := range requestDtos {
+		rows[i] = importer.ReadEmployeeImportRow(i+1, &requestDtos[i])
+	}
+
+	return rows, nil
+}
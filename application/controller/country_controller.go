@@ -0,0 +1,24 @@
+package controller
+
+import (
+	"github.com/gorilla/mux"
+	"net/http"
+	"service_admin_contractor/application/dto"
+	"service_admin_contractor/application/respond"
+	"service_admin_contractor/domain/model"
+)
+
+// CountryController отдает справочник стран ISO 3166-1, которым заполняется страна нерезидента
+type CountryController struct{}
+
+func NewCountryController() *CountryController {
+	return &CountryController{}
+}
+
+func (c *CountryController) HandleRoutes(r *mux.Router) {
+	r.HandleFunc("/countries", c.GetCountries).Methods(http.MethodOptions, http.MethodGet)
+}
+
+func (c *CountryController) GetCountries(w http.ResponseWriter, r *http.Request) {
+	respond.With(w, r, dto.ConvertCountries(model.Countries))
+}
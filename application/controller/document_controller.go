@@ -0,0 +1,192 @@
+package controller
+
+import (
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+	"io"
+	"net/http"
+	"path/filepath"
+	"service_admin_contractor/application/cerrors"
+	"service_admin_contractor/application/cvalidator"
+	"service_admin_contractor/application/dto"
+	"service_admin_contractor/application/middleware"
+	"service_admin_contractor/application/respond"
+	"service_admin_contractor/application/service"
+	"strconv"
+	"strings"
+)
+
+const (
+	// documentFormMemory является объемом multipart формы, хранимым в памяти; остальное пишется во временные файлы
+	documentFormMemory = 8 << 20
+	// documentFormOverhead допускает поля метаданных и заголовки частей сверх размера файла
+	documentFormOverhead = 1 << 20
+)
+
+type DocumentController struct {
+	s       service.DocumentService
+	maxSize int64
+}
+
+func NewDocumentController(s service.DocumentService, maxSize int64) *DocumentController {
+	return &DocumentController{s, maxSize}
+}
+
+func (c *DocumentController) HandleRoutes(r *mux.Router) {
+	r.HandleFunc("/contractors/{id}/documents", c.GetDocuments).Methods(http.MethodOptions, http.MethodGet)
+	r.HandleFunc("/contractors/{id}/documents", c.UploadDocument).Methods(http.MethodOptions, http.MethodPost)
+	r.HandleFunc("/contractors/{id}/documents/{documentId}", c.GetDocument).Methods(http.MethodOptions, http.MethodGet)
+	r.HandleFunc("/contractors/{id}/documents/{documentId}", c.DeleteDocument).Methods(http.MethodOptions, http.MethodDelete)
+	r.HandleFunc("/contractors/{id}/documents/{documentId}/content", c.DownloadDocument).
+		Methods(http.MethodOptions, http.MethodGet)
+}
+
+func (c *DocumentController) GetDocuments(w http.ResponseWriter, r *http.Request) {
+	contractorId, err := parsePathId(r, "id")
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	res, err := c.s.FindDocuments(r.Context(), contractorId)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	respond.With(w, r, dto.ConvertDocuments(res))
+}
+
+func (c *DocumentController) GetDocument(w http.ResponseWriter, r *http.Request) {
+	contractorId, documentId, err := parseDocumentPath(r)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	data, err := c.s.GetDocument(r.Context(), contractorId, documentId)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	respond.With(w, r, dto.ConvertDocument(data))
+}
+
+// UploadDocument принимает multipart форму с файлом в поле `file` и метаданными в полях
+// `type`, `number`, `issueDate` и `expiryDate`
+func (c *DocumentController) UploadDocument(w http.ResponseWriter, r *http.Request) {
+	contractorId, err := parsePathId(r, "id")
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, c.maxSize+documentFormOverhead)
+	if err = r.ParseMultipartForm(documentFormMemory); err != nil {
+		respond.WithError(w, r, cerrors.ErrCouldNotDecodeBody(err))
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	requestDto := &dto.DocumentUploadDto{
+		Type:       r.FormValue("type"),
+		IssueDate:  r.FormValue("issueDate"),
+		ExpiryDate: r.FormValue("expiryDate"),
+	}
+	if number := r.FormValue("number"); number != "" {
+		requestDto.Number = &number
+	}
+
+	if err = cvalidator.ValidateStruct(requestDto); err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		respond.WithError(w, r, cerrors.ErrBadRequestVar(err, "file"))
+		return
+	}
+	defer file.Close()
+
+	document := dto.ConvertDocumentUploadDtoToEntity(contractorId, sanitizeFileName(header.Filename), requestDto)
+	user := middleware.GetUserInfo(r.Context())
+
+	if err = c.s.UploadDocument(r.Context(), *user, document, file); err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	respond.With(w, r, dto.ConvertDocument(*document))
+}
+
+func (c *DocumentController) DownloadDocument(w http.ResponseWriter, r *http.Request) {
+	contractorId, documentId, err := parseDocumentPath(r)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	document, content, err := c.s.OpenDocument(r.Context(), contractorId, documentId)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+	defer content.Close()
+
+	respond.WithAttachment(w, r, document.ContentType, document.FileName)
+	w.Header().Set("Content-Length", strconv.FormatInt(document.Size, 10))
+	w.Header().Set("X-Content-Sha256", document.Sha256)
+	w.WriteHeader(http.StatusOK)
+
+	if _, err = io.Copy(w, content); err != nil {
+		log.Warnf("could not send document %d: %v", documentId, err)
+	}
+}
+
+func (c *DocumentController) DeleteDocument(w http.ResponseWriter, r *http.Request) {
+	contractorId, documentId, err := parseDocumentPath(r)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	if err = c.s.DeleteDocument(r.Context(), contractorId, documentId); err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	respond.With(w, r, true)
+}
+
+func parseDocumentPath(r *http.Request) (int64, int64, error) {
+	contractorId, err := parsePathId(r, "id")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	documentId, err := parsePathId(r, "documentId")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return contractorId, documentId, nil
+}
+
+// sanitizeFileName оставляет только имя файла без пути и символов, недопустимых в Content-Disposition
+func sanitizeFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, `\`, "/"))
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == '"' || r == '/' {
+			return -1
+		}
+		return r
+	}, name)
+
+	if name == "" || name == "." {
+		return "document"
+	}
+
+	return name
+}
@@ -1,7 +1,29 @@
 package controller
 
-import "github.com/gorilla/mux"
+import (
+	"github.com/gorilla/mux"
+	"net/http"
+	"service_admin_contractor/application/cerrors"
+	"service_admin_contractor/application/cvalidator"
+	"strconv"
+)
 
 type RouteHandler interface {
 	HandleRoutes(router *mux.Router)
 }
+
+// parsePathId разбирает числовой идентификатор из параметра пути name
+func parsePathId(r *http.Request, name string) (int64, error) {
+	rid := mux.Vars(r)[name]
+	err := cvalidator.Validate.Var(rid, "required,numeric")
+	if err != nil {
+		return 0, cerrors.ErrBadRequestVar(err, name)
+	}
+
+	id, err := strconv.ParseInt(rid, 10, 64)
+	if err != nil {
+		return 0, cerrors.ErrBadRequestVar(err, name)
+	}
+
+	return id, nil
+}
@@ -0,0 +1,136 @@
+package controller
+
+import (
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"net/http"
+	"service_admin_contractor/application/cerrors"
+	"service_admin_contractor/application/cvalidator"
+	"service_admin_contractor/application/dto"
+	"service_admin_contractor/application/middleware"
+	"service_admin_contractor/application/respond"
+	"service_admin_contractor/application/service"
+	"service_admin_contractor/domain/model"
+)
+
+type SavedSearchController struct {
+	s service.SavedSearchService
+}
+
+func NewSavedSearchController(s service.SavedSearchService) *SavedSearchController {
+	return &SavedSearchController{s}
+}
+
+func (c *SavedSearchController) HandleRoutes(r *mux.Router) {
+	r.HandleFunc("/saved-searches", c.GetAllSavedSearches).Methods(http.MethodOptions, http.MethodGet)
+	r.HandleFunc("/saved-searches", c.CreateSavedSearch).Methods(http.MethodOptions, http.MethodPost)
+	r.HandleFunc("/saved-searches/{id}", c.GetSavedSearch).Methods(http.MethodOptions, http.MethodGet)
+	r.HandleFunc("/saved-searches/{id}", c.UpdateSavedSearch).Methods(http.MethodOptions, http.MethodPut)
+	r.HandleFunc("/saved-searches/{id}", c.DeleteSavedSearch).Methods(http.MethodOptions, http.MethodDelete)
+}
+
+func (c *SavedSearchController) GetAllSavedSearches(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserInfo(r.Context())
+
+	res, err := c.s.FindSavedSearches(r.Context(), *user)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	respond.With(w, r, dto.ConvertSavedSearches(res))
+}
+
+func (c *SavedSearchController) GetSavedSearch(w http.ResponseWriter, r *http.Request) {
+	id, err := parsePathId(r, "id")
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	user := middleware.GetUserInfo(r.Context())
+
+	data, err := c.s.GetSavedSearch(r.Context(), *user, id)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	respond.With(w, r, dto.ConvertSavedSearch(data))
+}
+
+func (c *SavedSearchController) CreateSavedSearch(w http.ResponseWriter, r *http.Request) {
+	search, err := decodeSavedSearch(r)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	user := middleware.GetUserInfo(r.Context())
+
+	err = c.s.CreateSavedSearch(r.Context(), *user, search)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	respond.With(w, r, dto.ConvertSavedSearch(*search))
+}
+
+func (c *SavedSearchController) UpdateSavedSearch(w http.ResponseWriter, r *http.Request) {
+	id, err := parsePathId(r, "id")
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	search, err := decodeSavedSearch(r)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	user := middleware.GetUserInfo(r.Context())
+
+	err = c.s.UpdateSavedSearch(r.Context(), *user, id, search)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	respond.With(w, r, dto.ConvertSavedSearch(*search))
+}
+
+func (c *SavedSearchController) DeleteSavedSearch(w http.ResponseWriter, r *http.Request) {
+	id, err := parsePathId(r, "id")
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	user := middleware.GetUserInfo(r.Context())
+
+	err = c.s.DeleteSavedSearch(r.Context(), *user, id)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	respond.With(w, r, true)
+}
+
+func decodeSavedSearch(r *http.Request) (*model.SavedSearch, error) {
+	requestDto := &dto.SavedSearchDto{}
+	defer r.Body.Close()
+	err := json.NewDecoder(r.Body).Decode(&requestDto)
+	if err != nil {
+		return nil, cerrors.ErrCouldNotDecodeBody(err)
+	}
+
+	err = cvalidator.Validate.Struct(requestDto)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ConvertSavedSearchDtoToEntity(requestDto)
+}
@@ -0,0 +1,196 @@
+package controller
+
+import (
+	"github.com/gorilla/mux"
+	"net/http"
+	"service_admin_contractor/application/dto"
+	"service_admin_contractor/application/middleware"
+	"service_admin_contractor/application/respond"
+	"service_admin_contractor/application/service"
+)
+
+type TagController struct {
+	s service.TagService
+}
+
+func NewTagController(s service.TagService) *TagController {
+	return &TagController{s}
+}
+
+func (c *TagController) HandleRoutes(r *mux.Router) {
+	r.HandleFunc("/tags", c.GetTags).Methods(http.MethodOptions, http.MethodGet)
+	r.HandleFunc("/tags", c.CreateTag).Methods(http.MethodOptions, http.MethodPost)
+	r.HandleFunc("/tags/{id}", c.GetTag).Methods(http.MethodOptions, http.MethodGet)
+	r.HandleFunc("/tags/{id}", c.UpdateTag).Methods(http.MethodOptions, http.MethodPut)
+	r.HandleFunc("/tags/{id}", c.DeleteTag).Methods(http.MethodOptions, http.MethodDelete)
+
+	r.HandleFunc("/contractors/{id}/tags", c.GetContractorTags).Methods(http.MethodOptions, http.MethodGet)
+	r.HandleFunc("/contractors/{id}/tags", c.ReplaceContractorTags).Methods(http.MethodOptions, http.MethodPut)
+	r.HandleFunc("/contractors/{id}/tags/{tagId}", c.AddContractorTag).Methods(http.MethodOptions, http.MethodPut)
+	r.HandleFunc("/contractors/{id}/tags/{tagId}", c.RemoveContractorTag).Methods(http.MethodOptions, http.MethodDelete)
+}
+
+// GetTags возвращает справочник тегов с количеством использований, параметр `category` отбирает теги категории
+func (c *TagController) GetTags(w http.ResponseWriter, r *http.Request) {
+	res, err := c.s.FindTags(r.Context(), dto.ParseStringFilter(r.URL.Query(), "category"))
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	respond.With(w, r, dto.ConvertTags(res, true))
+}
+
+func (c *TagController) GetTag(w http.ResponseWriter, r *http.Request) {
+	id, err := parsePathId(r, "id")
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	data, err := c.s.GetTag(r.Context(), id)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	respond.With(w, r, dto.ConvertTag(data))
+}
+
+func (c *TagController) CreateTag(w http.ResponseWriter, r *http.Request) {
+	requestDto := &dto.TagDto{}
+	if err := decodeAndValidate(r, requestDto); err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	tag := dto.ConvertTagDtoToEntity(requestDto)
+	if err := c.s.CreateTag(r.Context(), tag); err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	respond.With(w, r, dto.ConvertTag(*tag))
+}
+
+func (c *TagController) UpdateTag(w http.ResponseWriter, r *http.Request) {
+	id, err := parsePathId(r, "id")
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	requestDto := &dto.TagDto{}
+	if err = decodeAndValidate(r, requestDto); err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	requestDto.Id = id
+	tag := dto.ConvertTagDtoToEntity(requestDto)
+	if err = c.s.UpdateTag(r.Context(), tag); err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	respond.With(w, r, dto.ConvertTag(*tag))
+}
+
+func (c *TagController) DeleteTag(w http.ResponseWriter, r *http.Request) {
+	id, err := parsePathId(r, "id")
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	if err = c.s.DeleteTag(r.Context(), *middleware.GetUserInfo(r.Context()), id); err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	respond.With(w, r, true)
+}
+
+func (c *TagController) GetContractorTags(w http.ResponseWriter, r *http.Request) {
+	contractorId, err := parsePathId(r, "id")
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	res, err := c.s.FindContractorTags(r.Context(), contractorId)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	respond.With(w, r, dto.ConvertTags(res, false))
+}
+
+func (c *TagController) ReplaceContractorTags(w http.ResponseWriter, r *http.Request) {
+	contractorId, err := parsePathId(r, "id")
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	requestDto := &dto.ContractorTagsDto{}
+	if err = decodeAndValidate(r, requestDto); err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	res, err := c.s.ReplaceContractorTags(r.Context(), *middleware.GetUserInfo(r.Context()), contractorId,
+		requestDto.UniqueTagIds())
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	respond.With(w, r, dto.ConvertTags(res, false))
+}
+
+func (c *TagController) AddContractorTag(w http.ResponseWriter, r *http.Request) {
+	contractorId, tagId, err := parseContractorTagPath(r)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	if err = c.s.AddContractorTag(r.Context(), *middleware.GetUserInfo(r.Context()), contractorId, tagId); err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	respond.With(w, r, true)
+}
+
+func (c *TagController) RemoveContractorTag(w http.ResponseWriter, r *http.Request) {
+	contractorId, tagId, err := parseContractorTagPath(r)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	if err = c.s.RemoveContractorTag(r.Context(), *middleware.GetUserInfo(r.Context()), contractorId,
+		tagId); err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	respond.With(w, r, true)
+}
+
+func parseContractorTagPath(r *http.Request) (int64, int64, error) {
+	contractorId, err := parsePathId(r, "id")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	tagId, err := parsePathId(r, "tagId")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return contractorId, tagId, nil
+}
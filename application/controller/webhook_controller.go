@@ -0,0 +1,175 @@
+package controller
+
+import (
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"net/http"
+	"service_admin_contractor/application/cerrors"
+	"service_admin_contractor/application/cvalidator"
+	"service_admin_contractor/application/dto"
+	"service_admin_contractor/application/middleware"
+	"service_admin_contractor/application/respond"
+	"service_admin_contractor/application/service"
+	"service_admin_contractor/domain/model"
+)
+
+type WebhookController struct {
+	s service.WebhookService
+}
+
+func NewWebhookController(s service.WebhookService) *WebhookController {
+	return &WebhookController{s}
+}
+
+func (c *WebhookController) HandleRoutes(r *mux.Router) {
+	r.HandleFunc("/webhooks", c.GetAllWebhookSubscriptions).Methods(http.MethodOptions, http.MethodGet)
+	r.HandleFunc("/webhooks", c.CreateWebhookSubscription).Methods(http.MethodOptions, http.MethodPost)
+	r.HandleFunc("/webhooks/{id}", c.GetWebhookSubscription).Methods(http.MethodOptions, http.MethodGet)
+	r.HandleFunc("/webhooks/{id}", c.UpdateWebhookSubscription).Methods(http.MethodOptions, http.MethodPut)
+	r.HandleFunc("/webhooks/{id}", c.DeleteWebhookSubscription).Methods(http.MethodOptions, http.MethodDelete)
+	r.HandleFunc("/webhooks/{id}/deliveries", c.GetWebhookDeliveries).Methods(http.MethodOptions, http.MethodGet)
+	r.HandleFunc("/webhooks/{id}/deliveries/{deliveryId}/redeliver", c.RedeliverWebhookDelivery).
+		Methods(http.MethodOptions, http.MethodPost)
+}
+
+func (c *WebhookController) GetAllWebhookSubscriptions(w http.ResponseWriter, r *http.Request) {
+	res, err := c.s.FindWebhookSubscriptions(r.Context())
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	respond.With(w, r, dto.ConvertWebhookSubscriptions(res))
+}
+
+func (c *WebhookController) GetWebhookSubscription(w http.ResponseWriter, r *http.Request) {
+	id, err := parsePathId(r, "id")
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	data, err := c.s.GetWebhookSubscription(r.Context(), id)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	respond.With(w, r, dto.ConvertWebhookSubscription(data, false))
+}
+
+func (c *WebhookController) CreateWebhookSubscription(w http.ResponseWriter, r *http.Request) {
+	subscription, err := decodeWebhookSubscription(r)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	user := middleware.GetUserInfo(r.Context())
+
+	err = c.s.CreateWebhookSubscription(r.Context(), *user, subscription)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	respond.With(w, r, dto.ConvertWebhookSubscription(*subscription, true))
+}
+
+func (c *WebhookController) UpdateWebhookSubscription(w http.ResponseWriter, r *http.Request) {
+	id, err := parsePathId(r, "id")
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	subscription, err := decodeWebhookSubscription(r)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	err = c.s.UpdateWebhookSubscription(r.Context(), id, subscription)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	respond.With(w, r, dto.ConvertWebhookSubscription(*subscription, false))
+}
+
+func (c *WebhookController) DeleteWebhookSubscription(w http.ResponseWriter, r *http.Request) {
+	id, err := parsePathId(r, "id")
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	err = c.s.DeleteWebhookSubscription(r.Context(), id)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	respond.With(w, r, true)
+}
+
+func (c *WebhookController) GetWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	id, err := parsePathId(r, "id")
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	pagination, err := dto.ParsePagination(r.URL.Query())
+	if err != nil {
+		respond.WithError(w, r, cerrors.ErrBadRequestVar(err, "page"))
+		return
+	}
+
+	res, total, err := c.s.FindWebhookDeliveries(r.Context(), id, *pagination)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	respond.WithPagination(w, r, dto.ConvertWebhookDeliveries(res), total)
+}
+
+func (c *WebhookController) RedeliverWebhookDelivery(w http.ResponseWriter, r *http.Request) {
+	id, err := parsePathId(r, "id")
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	deliveryId, err := parsePathId(r, "deliveryId")
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	data, err := c.s.RedeliverWebhookDelivery(r.Context(), id, deliveryId)
+	if err != nil {
+		respond.WithError(w, r, err)
+		return
+	}
+
+	respond.With(w, r, dto.ConvertWebhookDelivery(data))
+}
+
+func decodeWebhookSubscription(r *http.Request) (*model.WebhookSubscription, error) {
+	requestDto := &dto.WebhookSubscriptionDto{}
+	defer r.Body.Close()
+	err := json.NewDecoder(r.Body).Decode(&requestDto)
+	if err != nil {
+		return nil, cerrors.ErrCouldNotDecodeBody(err)
+	}
+
+	err = cvalidator.Validate.Struct(requestDto)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ConvertWebhookSubscriptionDtoToEntity(requestDto)
+}
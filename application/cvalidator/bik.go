@@ -0,0 +1,27 @@
+package cvalidator
+
+import (
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"regexp"
+)
+
+// TagBik является тегом проверки БИК банка второго уровня Казахстана. БИК совпадает с SWIFT кодом банка:
+// 4 буквы кода банка, код страны KZ, 2 символа кода местонахождения и необязательные 3 символа кода филиала.
+const TagBik = "bik"
+
+var bikPattern = regexp.MustCompile(`^[A-Z]{4}KZ[A-Z0-9]{2}([A-Z0-9]{3})?$`)
+
+func isBik(fl validator.FieldLevel) bool {
+	return bikProblem(fl.Field().String(), fl.Param()) == ""
+}
+
+// bikProblem возвращает описание ошибки БИК или пустую строку, если значение корректно
+func bikProblem(value string, _ string) string {
+	if bikPattern.MatchString(value) {
+		return ""
+	}
+
+	return fmt.Sprintf("БИК '%s' должен состоять из 8 или 11 латинских букв и цифр с кодом страны KZ, "+
+		"например KCJBKZKX", value)
+}
@@ -0,0 +1,112 @@
+package cvalidator
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"time"
+)
+
+// TagBin является тегом проверки казахстанского БИН/ИИН. Параметр тега ограничивает тип субъекта:
+// `bin` - любой, `bin=legal` - только юридическое лицо (БИН), `bin=individual` - только физическое лицо (ИИН).
+const TagBin = "bin"
+
+// BinKind является типом субъекта, определяемым по БИН/ИИН
+type BinKind string
+
+const (
+	BinLegalEntity BinKind = "legal"
+	BinIndividual  BinKind = "individual"
+)
+
+const binLength = 12
+
+var (
+	binFirstWeights  = [binLength - 1]int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}
+	binSecondWeights = [binLength - 1]int{3, 4, 5, 6, 7, 8, 9, 10, 11, 1, 2}
+)
+
+// ParseBin проверяет структуру и контрольный разряд БИН/ИИН и возвращает тип субъекта.
+//
+// Для юридических лиц (БИН) разряды 1-4 содержат год и месяц регистрации, 5-й разряд - тип
+// (4 - резидент, 5 - нерезидент, 6 - ИП(С)), 6-й - признак (0 - головное подразделение, 1 - филиал,
+// 2 - представительство, 3 - крестьянское хозяйство). Для физических лиц (ИИН) разряды 1-6 содержат
+// дату рождения, 7-й - век и пол. 12-й разряд является контрольным.
+func ParseBin(value string) (BinKind, error) {
+	if len(value) != binLength {
+		return "", fmt.Errorf("должен состоять из %d цифр", binLength)
+	}
+
+	var digits [binLength]int
+	for i, r := range value {
+		if r < '0' || r > '9' {
+			return "", errors.New("должен содержать только цифры")
+		}
+		digits[i] = int(r - '0')
+	}
+
+	kind := BinIndividual
+	if digits[4] >= 4 && digits[4] <= 6 {
+		kind = BinLegalEntity
+	}
+
+	if kind == BinLegalEntity {
+		if month := digits[2]*10 + digits[3]; month < 1 || month > 12 {
+			return "", fmt.Errorf("содержит неверный месяц регистрации '%02d'", month)
+		}
+		if digits[5] > 3 {
+			return "", fmt.Errorf("содержит неверный признак подразделения '%d'", digits[5])
+		}
+	} else {
+		if digits[6] > 6 {
+			return "", fmt.Errorf("содержит неверный признак века и пола '%d'", digits[6])
+		}
+		if _, err := time.Parse("060102", value[:6]); err != nil {
+			return "", fmt.Errorf("содержит неверную дату рождения '%s'", value[:6])
+		}
+	}
+
+	control := binControlDigit(digits, binFirstWeights)
+	if control == 10 {
+		control = binControlDigit(digits, binSecondWeights)
+	}
+	if control == 10 || control != digits[binLength-1] {
+		return "", errors.New("не проходит проверку контрольного разряда")
+	}
+
+	return kind, nil
+}
+
+func binControlDigit(digits [binLength]int, weights [binLength - 1]int) int {
+	sum := 0
+	for i, weight := range weights {
+		sum += digits[i] * weight
+	}
+
+	return sum % 11
+}
+
+func isBin(fl validator.FieldLevel) bool {
+	return binProblem(fl.Field().String(), fl.Param()) == ""
+}
+
+// binProblem возвращает описание ошибки БИН/ИИН или пустую строку, если значение корректно
+func binProblem(value string, param string) string {
+	kind, err := ParseBin(value)
+	if err != nil {
+		return fmt.Sprintf("БИН/ИИН '%s' %s", value, err)
+	}
+
+	switch BinKind(param) {
+	case BinLegalEntity:
+		if kind != BinLegalEntity {
+			return fmt.Sprintf("'%s' является ИИН физического лица, ожидается БИН юридического лица", value)
+		}
+	case BinIndividual:
+		if kind != BinIndividual {
+			return fmt.Sprintf("'%s' является БИН юридического лица, ожидается ИИН физического лица", value)
+		}
+	}
+
+	return ""
+}
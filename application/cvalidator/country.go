@@ -0,0 +1,23 @@
+package cvalidator
+
+import (
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"service_admin_contractor/domain/model"
+)
+
+// TagCountry является тегом проверки кода страны по справочнику ISO 3166-1 alpha-2
+const TagCountry = "country"
+
+func isCountry(fl validator.FieldLevel) bool {
+	return countryProblem(fl.Field().String(), fl.Param()) == ""
+}
+
+// countryProblem возвращает описание ошибки кода страны или пустую строку, если код есть в справочнике
+func countryProblem(value string, _ string) string {
+	if _, ok := model.FindCountry(value); ok {
+		return ""
+	}
+
+	return fmt.Sprintf("страна '%s' должна быть задана кодом ISO 3166-1 alpha-2, например KZ", value)
+}
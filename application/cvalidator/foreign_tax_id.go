@@ -0,0 +1,119 @@
+package cvalidator
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// TagForeignTaxId является тегом ошибки налогового номера нерезидента. Формат номера зависит от страны,
+// поэтому проверка выполняется на уровне структуры: ошибка сообщается с кодом страны в качестве параметра.
+const TagForeignTaxId = "foreign_tax_id"
+
+// foreignTaxIdRule описывает формат налогового номера страны. Check выполняет проверку, которую нельзя
+// выразить шаблоном, например контрольных разрядов.
+type foreignTaxIdRule struct {
+	Name    string
+	Pattern *regexp.Regexp
+	Check   func(value string) error
+}
+
+// foreignTaxIdRules содержит правила для стран, с которыми работают контрагенты. Номера остальных стран
+// проверяются по genericForeignTaxId.
+var foreignTaxIdRules = map[string]foreignTaxIdRule{
+	"AE": {Name: "TRN", Pattern: regexp.MustCompile(`^\d{15}$`)},
+	"AM": {Name: "ИНН", Pattern: regexp.MustCompile(`^\d{8}$`)},
+	"AZ": {Name: "VÖEN", Pattern: regexp.MustCompile(`^\d{10}$`)},
+	"BY": {Name: "УНП", Pattern: regexp.MustCompile(`^\d{9}$`)},
+	"CN": {Name: "USCC", Pattern: regexp.MustCompile(`^[0-9A-HJ-NPQRTUWXY]{2}\d{6}[0-9A-HJ-NPQRTUWXY]{10}$`)},
+	"DE": {Name: "USt-IdNr", Pattern: regexp.MustCompile(`^DE\d{9}$`)},
+	"GB": {Name: "VAT", Pattern: regexp.MustCompile(`^GB(\d{9}|\d{12}|GD\d{3}|HA\d{3})$`)},
+	"GE": {Name: "идентификационный код", Pattern: regexp.MustCompile(`^(\d{9}|\d{11})$`)},
+	"KG": {Name: "ИНН", Pattern: regexp.MustCompile(`^\d{14}$`)},
+	"RU": {Name: "ИНН", Pattern: regexp.MustCompile(`^(\d{10}|\d{12})$`), Check: checkRussianInn},
+	"TJ": {Name: "ИНН", Pattern: regexp.MustCompile(`^\d{9}$`)},
+	"TR": {Name: "VKN", Pattern: regexp.MustCompile(`^\d{10}$`)},
+	"US": {Name: "EIN", Pattern: regexp.MustCompile(`^\d{2}-?\d{7}$`)},
+	"UZ": {Name: "ИНН", Pattern: regexp.MustCompile(`^\d{9}$`)},
+}
+
+// genericForeignTaxId допускает от 3 до 30 латинских букв в верхнем регистре, цифр и разделителей `.`, `/`, `-`
+var genericForeignTaxId = foreignTaxIdRule{
+	Name:    "налоговый номер",
+	Pattern: regexp.MustCompile(`^[0-9A-Z][0-9A-Z./-]{1,28}[0-9A-Z]$`),
+}
+
+var (
+	innWeights10 = []int{2, 4, 10, 3, 5, 9, 4, 6, 8}
+	innWeights11 = []int{7, 2, 4, 10, 3, 5, 9, 4, 6, 8}
+	innWeights12 = []int{3, 7, 2, 4, 10, 3, 5, 9, 4, 6, 8}
+)
+
+// ParseForeignTaxId проверяет налоговый номер нерезидента по правилам страны country (ISO 3166-1 alpha-2)
+func ParseForeignTaxId(country string, value string) error {
+	rule, ok := foreignTaxIdRules[country]
+	if !ok {
+		rule = genericForeignTaxId
+	}
+
+	if !rule.Pattern.MatchString(value) {
+		return fmt.Errorf("%s не соответствует формату страны %s", rule.Name, country)
+	}
+
+	if rule.Check != nil {
+		if err := rule.Check(value); err != nil {
+			return fmt.Errorf("%s %s", rule.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// ForeignTaxIdName возвращает название налогового номера страны country
+func ForeignTaxIdName(country string) string {
+	if rule, ok := foreignTaxIdRules[country]; ok {
+		return rule.Name
+	}
+
+	return genericForeignTaxId.Name
+}
+
+// checkRussianInn проверяет контрольные разряды ИНН: один для организаций (10 цифр), два для физических лиц (12 цифр)
+func checkRussianInn(value string) error {
+	digits := make([]int, len(value))
+	for i, r := range value {
+		digits[i] = int(r - '0')
+	}
+
+	if len(digits) == 10 {
+		if innControlDigit(digits, innWeights10) != digits[9] {
+			return errors.New("не проходит проверку контрольного разряда")
+		}
+		return nil
+	}
+
+	if innControlDigit(digits, innWeights11) != digits[10] || innControlDigit(digits, innWeights12) != digits[11] {
+		return errors.New("не проходит проверку контрольных разрядов")
+	}
+
+	return nil
+}
+
+func innControlDigit(digits []int, weights []int) int {
+	sum := 0
+	for i, w := range weights {
+		sum += digits[i] * w
+	}
+
+	return sum % 11 % 10
+}
+
+// foreignTaxIdProblem возвращает описание ошибки налогового номера страны country или пустую строку,
+// если значение корректно
+func foreignTaxIdProblem(value string, country string) string {
+	if err := ParseForeignTaxId(country, value); err != nil {
+		return fmt.Sprintf("налоговый номер '%s': %s", value, err)
+	}
+
+	return ""
+}
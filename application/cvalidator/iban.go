@@ -0,0 +1,96 @@
+package cvalidator
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"strings"
+)
+
+// TagIban является тегом проверки IBAN по структуре и контрольной сумме mod-97 (ISO 13616).
+// Значение допускается в печатном формате с пробелами между группами.
+const TagIban = "iban"
+
+const (
+	ibanMinLength = 15
+	ibanMaxLength = 34
+)
+
+// ibanLengths содержит длину IBAN для стран, с которыми работают контрагенты. Для прочих стран
+// проверяются только общие ограничения длины.
+var ibanLengths = map[string]int{
+	"KZ": 20,
+	"AE": 23,
+	"AZ": 28,
+	"BY": 28,
+	"DE": 22,
+	"FR": 27,
+	"GB": 22,
+	"GE": 22,
+	"NL": 18,
+	"TR": 26,
+	"UA": 29,
+}
+
+// NormalizeIban приводит IBAN к электронному формату: без пробелов, в верхнем регистре
+func NormalizeIban(value string) string {
+	return strings.ToUpper(strings.ReplaceAll(value, " ", ""))
+}
+
+// ParseIban проверяет IBAN и возвращает код страны
+func ParseIban(value string) (string, error) {
+	iban := NormalizeIban(value)
+	if len(iban) < ibanMinLength || len(iban) > ibanMaxLength {
+		return "", fmt.Errorf("должен содержать от %d до %d символов", ibanMinLength, ibanMaxLength)
+	}
+
+	for i, r := range iban {
+		switch {
+		case i < 2 && (r < 'A' || r > 'Z'):
+			return "", errors.New("должен начинаться с кода страны из двух букв")
+		case i >= 2 && i < 4 && (r < '0' || r > '9'):
+			return "", errors.New("должен содержать две контрольные цифры после кода страны")
+		case (r < '0' || r > '9') && (r < 'A' || r > 'Z'):
+			return "", errors.New("должен содержать только латинские буквы и цифры")
+		}
+	}
+
+	country := iban[:2]
+	if length, ok := ibanLengths[country]; ok && len(iban) != length {
+		return "", fmt.Errorf("для страны %s должен содержать %d символов", country, length)
+	}
+
+	if ibanMod97(iban) != 1 {
+		return "", errors.New("не проходит проверку контрольной суммы")
+	}
+
+	return country, nil
+}
+
+// ibanMod97 вычисляет остаток от деления на 97 числа, полученного переносом первых четырех символов в конец
+// и заменой букв числами 10-35. Вычисление ведется по разрядам, чтобы не переполнить int.
+func ibanMod97(iban string) int {
+	remainder := 0
+	for _, r := range iban[4:] + iban[:4] {
+		if r >= 'A' && r <= 'Z' {
+			remainder = (remainder*100 + int(r-'A') + 10) % 97
+		} else {
+			remainder = (remainder*10 + int(r-'0')) % 97
+		}
+	}
+
+	return remainder
+}
+
+func isIban(fl validator.FieldLevel) bool {
+	return ibanProblem(fl.Field().String(), fl.Param()) == ""
+}
+
+// ibanProblem возвращает описание ошибки IBAN или пустую строку, если значение корректно
+func ibanProblem(value string, _ string) string {
+	if _, err := ParseIban(value); err != nil {
+		return fmt.Sprintf("IBAN '%s' %s", value, err)
+	}
+
+	return ""
+}
@@ -1,13 +1,38 @@
 package cvalidator
 
 import (
+	"fmt"
 	"github.com/go-playground/validator/v10"
+	"reflect"
 )
 
 var Validate *validator.Validate
 
+// messages содержит описания ошибок пользовательских тегов. Функция получает значение поля и параметр тега.
+var messages = map[string]func(value string, param string) string{
+	TagBin:          binProblem,
+	TagIban:         ibanProblem,
+	TagBik:          bikProblem,
+	TagCountry:      countryProblem,
+	TagForeignTaxId: foreignTaxIdProblem,
+	"e164": func(value string, _ string) string {
+		return fmt.Sprintf("телефон '%s' должен быть в формате E.164, например +77011234567", value)
+	},
+}
+
 func ConfigureValidator() {
-	Validate = validator.New()
+	Validate = newValidator()
+}
+
+// newValidator создает валидатор с зарегистрированными пользовательскими тегами
+func newValidator() *validator.Validate {
+	result := validator.New()
+	_ = result.RegisterValidation(TagBin, isBin)
+	_ = result.RegisterValidation(TagIban, isIban)
+	_ = result.RegisterValidation(TagBik, isBik)
+	_ = result.RegisterValidation(TagCountry, isCountry)
+
+	return result
 }
 
 type Validatable interface {
@@ -15,14 +40,14 @@ type Validatable interface {
 }
 
 func ValidateStruct(validatable Validatable) error {
-	result := validator.New()
+	result := newValidator()
 
 	err := result.Struct(validatable)
 	if err != nil {
 		return err
 	}
 
-	result = validator.New()
+	result = newValidator()
 
 	result.RegisterStructValidation(validatable.StructLevelValidation, validatable)
 
@@ -33,3 +58,23 @@ func ValidateStruct(validatable Validatable) error {
 
 	return nil
 }
+
+// ErrorMessage возвращает понятное описание ошибки валидации для пользовательских тегов,
+// для остальных - стандартное сообщение валидатора
+func ErrorMessage(err validator.FieldError) string {
+	message, ok := messages[err.Tag()]
+	if !ok {
+		return err.Error()
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(err.Value()))
+	if !value.IsValid() {
+		return err.Error()
+	}
+
+	if problem := message(fmt.Sprint(value.Interface()), err.Param()); problem != "" {
+		return problem
+	}
+
+	return err.Error()
+}
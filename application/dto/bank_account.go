@@ -0,0 +1,62 @@
+package dto
+
+import (
+	"service_admin_contractor/application/cvalidator"
+	"service_admin_contractor/domain/model"
+	"time"
+)
+
+type BankAccountDto struct {
+	Id       int64  `json:"id"`
+	Iban     string `json:"iban" validate:"required,iban"`
+	Bik      string `json:"bik" validate:"required,bik"`
+	BankName string `json:"bankName" validate:"required,max=255"`
+	Currency string `json:"currency" validate:"required,len=3,alpha,uppercase"`
+	Primary  bool   `json:"primary"`
+	// Masked равен true, если номер счета в Iban скрыт из-за отсутствия у пользователя финансовой роли
+	Masked    bool       `json:"masked,omitempty"`
+	CreatedAt *time.Time `json:"createdAt,omitempty"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+}
+
+// ConvertBankAccounts преобразует счета; mask скрывает номера счетов
+func ConvertBankAccounts(list []model.BankAccount, mask bool) []BankAccountDto {
+	result := make([]BankAccountDto, len(list))
+	for i := range list {
+		result[i] = ConvertBankAccount(list[i], mask)
+	}
+
+	return result
+}
+
+func ConvertBankAccount(a model.BankAccount, mask bool) BankAccountDto {
+	iban := a.Iban
+	if mask {
+		iban = a.MaskedIban()
+	}
+
+	return BankAccountDto{
+		Id:        a.Id,
+		Iban:      iban,
+		Bik:       a.Bik,
+		BankName:  a.BankName,
+		Currency:  a.Currency,
+		Primary:   a.Primary,
+		Masked:    mask,
+		CreatedAt: optionalTime(a.CreatedAt),
+		UpdatedAt: optionalTime(a.UpdatedAt),
+	}
+}
+
+// ConvertBankAccountDtoToEntity создает счет, приводя IBAN к электронному формату
+func ConvertBankAccountDtoToEntity(contractorId int64, dto *BankAccountDto) *model.BankAccount {
+	return &model.BankAccount{
+		Id:           dto.Id,
+		ContractorId: contractorId,
+		Iban:         cvalidator.NormalizeIban(dto.Iban),
+		Bik:          dto.Bik,
+		BankName:     dto.BankName,
+		Currency:     dto.Currency,
+		Primary:      dto.Primary,
+	}
+}
@@ -0,0 +1,86 @@
+package dto
+
+import (
+	"fmt"
+	"net/url"
+	"service_admin_contractor/application/cerrors"
+	"service_admin_contractor/domain/model"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultChangeFeedLimit = 100
+	maxChangeFeedLimit     = 1000
+)
+
+type ChangeDto struct {
+	Entity       string         `json:"entity"`
+	EntityId     int64          `json:"entityId"`
+	ContractorId int64          `json:"contractorId"`
+	Operation    string         `json:"operation"`
+	ChangedAt    time.Time      `json:"changedAt"`
+	Contractor   *ContractorDto `json:"contractor,omitempty"`
+	Employee     *EmployeeDto   `json:"employee,omitempty"`
+}
+
+type ChangeFeedDto struct {
+	Changes []ChangeDto `json:"changes"`
+	Next    string      `json:"next"`
+	HasMore bool        `json:"hasMore"`
+}
+
+// ParseChangeFeedParameters разбирает токен продолжения `since` и размер страницы `limit` журнала изменений
+func ParseChangeFeedParameters(values url.Values) (model.ChangeToken, int, error) {
+	since, err := model.ParseChangeToken(values.Get("since"))
+	if err != nil {
+		return model.ChangeToken{}, 0, cerrors.ErrBadRequestVar(err, "since")
+	}
+
+	limit := defaultChangeFeedLimit
+	if value := values.Get("limit"); value != "" {
+		limit, err = strconv.Atoi(value)
+		if err != nil {
+			return model.ChangeToken{}, 0, cerrors.ErrBadRequestVar(err, "limit")
+		}
+		if limit < 1 || limit > maxChangeFeedLimit {
+			return model.ChangeToken{}, 0, cerrors.ErrBadRequestVar(
+				fmt.Errorf("значение должно быть от 1 до %d", maxChangeFeedLimit), "limit")
+		}
+	}
+
+	return since, limit, nil
+}
+
+func ConvertChangeFeed(feed model.ChangeFeed) ChangeFeedDto {
+	changes := make([]ChangeDto, len(feed.Changes))
+	for i, change := range feed.Changes {
+		changes[i] = ConvertChange(change)
+	}
+
+	return ChangeFeedDto{
+		Changes: changes,
+		Next:    feed.Next.String(),
+		HasMore: feed.HasMore,
+	}
+}
+
+func ConvertChange(change model.Change) ChangeDto {
+	result := ChangeDto{
+		Entity:       string(change.Entity),
+		EntityId:     change.EntityId,
+		ContractorId: change.ContractorId,
+		Operation:    string(change.Operation),
+		ChangedAt:    change.ChangedAt,
+	}
+	if change.Contractor != nil {
+		contractor := ConvertContractor(*change.Contractor)
+		result.Contractor = &contractor
+	}
+	if change.Employee != nil {
+		employee := ConvertContractorEmployee(*change.Employee)
+		result.Employee = &employee
+	}
+
+	return result
+}
@@ -0,0 +1,160 @@
+package dto
+
+import (
+	"service_admin_contractor/domain/model"
+	"time"
+)
+
+type AddressDto struct {
+	Id         int64      `json:"id"`
+	Type       string     `json:"type" validate:"required,oneof=LEGAL ACTUAL POSTAL"`
+	Country    *string    `json:"country"`
+	Region     *string    `json:"region"`
+	City       string     `json:"city" validate:"required"`
+	Street     string     `json:"street" validate:"required"`
+	PostalCode *string    `json:"postalCode"`
+	CreatedAt  *time.Time `json:"createdAt,omitempty"`
+	UpdatedAt  *time.Time `json:"updatedAt,omitempty"`
+}
+
+type PhoneDto struct {
+	Id        int64   `json:"id"`
+	Type      string  `json:"type" validate:"required,oneof=MOBILE WORK HOME FAX OTHER"`
+	Number    string  `json:"number" validate:"required,e164"`
+	Extension *string `json:"extension" validate:"omitempty,numeric,max=10"`
+}
+
+type ContactDto struct {
+	Id        int64      `json:"id"`
+	FullName  string     `json:"fullName" validate:"required"`
+	Position  *string    `json:"position"`
+	Email     *string    `json:"email" validate:"omitempty,email"`
+	Comment   *string    `json:"comment"`
+	Phones    []PhoneDto `json:"phones" validate:"dive"`
+	CreatedAt *time.Time `json:"createdAt,omitempty"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+}
+
+func ConvertAddresses(list []model.Address) []AddressDto {
+	result := make([]AddressDto, len(list))
+	for i := range list {
+		result[i] = ConvertAddress(list[i])
+	}
+
+	return result
+}
+
+func ConvertAddress(a model.Address) AddressDto {
+	return AddressDto{
+		Id:         a.Id,
+		Type:       string(a.Type),
+		Country:    a.Country,
+		Region:     a.Region,
+		City:       a.City,
+		Street:     a.Street,
+		PostalCode: a.PostalCode,
+		CreatedAt:  optionalTime(a.CreatedAt),
+		UpdatedAt:  optionalTime(a.UpdatedAt),
+	}
+}
+
+func ConvertAddressDtoToEntity(contractorId int64, dto *AddressDto) *model.Address {
+	return &model.Address{
+		Id:           dto.Id,
+		ContractorId: contractorId,
+		Type:         model.AddressType(dto.Type),
+		Country:      dto.Country,
+		Region:       dto.Region,
+		City:         dto.City,
+		Street:       dto.Street,
+		PostalCode:   dto.PostalCode,
+	}
+}
+
+func ConvertPhones(list []model.Phone) []PhoneDto {
+	result := make([]PhoneDto, len(list))
+	for i, p := range list {
+		result[i] = PhoneDto{
+			Id:        p.Id,
+			Type:      string(p.Type),
+			Number:    p.Number,
+			Extension: p.Extension,
+		}
+	}
+
+	return result
+}
+
+// ConvertPhoneDtosToEntities сохраняет различие между nil (телефоны не переданы) и пустым списком
+func ConvertPhoneDtosToEntities(list []PhoneDto) []model.Phone {
+	if list == nil {
+		return nil
+	}
+
+	result := make([]model.Phone, len(list))
+	for i, p := range list {
+		result[i] = model.Phone{
+			Type:      model.PhoneType(p.Type),
+			Number:    p.Number,
+			Extension: p.Extension,
+		}
+	}
+
+	return result
+}
+
+func ConvertContacts(list []model.Contact) []ContactDto {
+	result := make([]ContactDto, len(list))
+	for i := range list {
+		result[i] = ConvertContact(list[i])
+	}
+
+	return result
+}
+
+func ConvertContact(c model.Contact) ContactDto {
+	return ContactDto{
+		Id:        c.Id,
+		FullName:  c.FullName,
+		Position:  c.Position,
+		Email:     c.Email,
+		Comment:   c.Comment,
+		Phones:    ConvertPhones(c.Phones),
+		CreatedAt: optionalTime(c.CreatedAt),
+		UpdatedAt: optionalTime(c.UpdatedAt),
+	}
+}
+
+func ConvertContactDtoToEntity(contractorId int64, dto *ContactDto) *model.Contact {
+	return &model.Contact{
+		Id:           dto.Id,
+		ContractorId: contractorId,
+		FullName:     dto.FullName,
+		Position:     dto.Position,
+		Email:        dto.Email,
+		Comment:      dto.Comment,
+		Phones:       ConvertPhoneDtosToEntities(dto.Phones),
+	}
+}
+
+// convertContractorContactsDto возвращает nil, если при создании или изменении контрагента
+// не переданы ни адреса, ни телефоны, ни контактные лица
+func convertContractorContactsDto(dto *ContractorDto) *model.ContractorContacts {
+	if dto.Addresses == nil && dto.Phones == nil && dto.Contacts == nil {
+		return nil
+	}
+
+	result := &model.ContractorContacts{
+		Addresses: make([]model.Address, len(dto.Addresses)),
+		Phones:    ConvertPhoneDtosToEntities(dto.Phones),
+		Contacts:  make([]model.Contact, len(dto.Contacts)),
+	}
+	for i := range dto.Addresses {
+		result.Addresses[i] = *ConvertAddressDtoToEntity(0, &dto.Addresses[i])
+	}
+	for i := range dto.Contacts {
+		result.Contacts[i] = *ConvertContactDtoToEntity(0, &dto.Contacts[i])
+	}
+
+	return result
+}
@@ -0,0 +1,76 @@
+package dto
+
+import (
+	"github.com/go-playground/validator/v10"
+	"service_admin_contractor/domain/model"
+	"strings"
+	"time"
+)
+
+type ContractDto struct {
+	Id             int64      `json:"id"`
+	Number         string     `json:"number" validate:"required"`
+	StartDate      string     `json:"startDate" validate:"required,datetime=2006-01-02"`
+	EndDate        *string    `json:"endDate" validate:"omitempty,datetime=2006-01-02"`
+	Amount         string     `json:"amount" validate:"required,numeric"`
+	Currency       string     `json:"currency" validate:"required,len=3,alpha,uppercase"`
+	Status         string     `json:"status" validate:"required,oneof=DRAFT ACTIVE SUSPENDED TERMINATED"`
+	ExpiryWarnedAt *time.Time `json:"expiryWarnedAt,omitempty"`
+	CreatedAt      *time.Time `json:"createdAt,omitempty"`
+	UpdatedAt      *time.Time `json:"updatedAt,omitempty"`
+}
+
+func (dto ContractDto) StructLevelValidation(sl validator.StructLevel) {
+	if strings.HasPrefix(dto.Amount, "-") {
+		sl.ReportError(dto.Amount, "amount", "Amount", "gte", "0")
+	}
+
+	if dto.EndDate != nil && *dto.EndDate != "" && *dto.EndDate < dto.StartDate {
+		sl.ReportError(dto.EndDate, "endDate", "EndDate", "gtefield", "StartDate")
+	}
+}
+
+func ConvertContracts(list []model.Contract) []ContractDto {
+	result := make([]ContractDto, len(list))
+	for i := range list {
+		result[i] = ConvertContract(list[i])
+	}
+
+	return result
+}
+
+func ConvertContract(c model.Contract) ContractDto {
+	return ContractDto{
+		Id:             c.Id,
+		Number:         c.Number,
+		StartDate:      c.StartDate.Format(dateLayout),
+		EndDate:        formatOptionalDate(c.EndDate),
+		Amount:         c.Amount,
+		Currency:       c.Currency,
+		Status:         string(c.Status),
+		ExpiryWarnedAt: c.ExpiryWarnedAt,
+		CreatedAt:      optionalTime(c.CreatedAt),
+		UpdatedAt:      optionalTime(c.UpdatedAt),
+	}
+}
+
+// ConvertContractDtoToEntity создает договор. Даты должны быть проверены валидатором.
+func ConvertContractDtoToEntity(contractorId int64, dto *ContractDto) *model.Contract {
+	startDate, _ := time.Parse(dateLayout, dto.StartDate)
+
+	var endDate *time.Time
+	if dto.EndDate != nil {
+		endDate = parseOptionalDate(*dto.EndDate)
+	}
+
+	return &model.Contract{
+		Id:           dto.Id,
+		ContractorId: contractorId,
+		Number:       dto.Number,
+		StartDate:    startDate,
+		EndDate:      endDate,
+		Amount:       dto.Amount,
+		Currency:     dto.Currency,
+		Status:       model.ContractStatus(dto.Status),
+	}
+}
@@ -1,16 +1,22 @@
 package dto
 
 import (
+	"fmt"
 	"github.com/go-playground/validator/v10"
 	"net/url"
+	"service_admin_contractor/application/cerrors"
+	"service_admin_contractor/application/cvalidator"
 	"service_admin_contractor/domain/model"
+	"strconv"
+	"strings"
 	"time"
 )
 
 type ContractorDto struct {
 	Id            int64         `json:"id"`
+	ParentId      *int64        `json:"parentId" validate:"omitempty,min=1"`
 	Resident      bool          `json:"resident"`
-	Bin           *string       `json:"bin"`
+	Bin           *string       `json:"bin" validate:"omitempty,bin"`
 	Name          *string       `json:"name" validate:"required"`
 	Email         string        `json:"email" validate:"required"`
 	AgentName     *string       `json:"agentName" validate:"required"`
@@ -19,22 +25,122 @@ type ContractorDto struct {
 	BlockDate     *time.Time    `json:"blockDate"`
 	Status        string        `json:"status"`
 	ClientCode    string        `json:"client_code"`
+	CreatedAt     *time.Time    `json:"createdAt,omitempty"`
+	UpdatedAt     *time.Time    `json:"updatedAt,omitempty"`
 	Employees     []EmployeeDto `json:"employees"`
+	Addresses     []AddressDto  `json:"addresses,omitempty" validate:"dive"`
+	Phones        []PhoneDto    `json:"phones,omitempty" validate:"dive"`
+	Contacts      []ContactDto  `json:"contacts,omitempty" validate:"dive"`
+	Tags          []TagDto      `json:"tags,omitempty"` // только в ответе, теги назначаются через /contractors/{id}/tags
+	// Country, ForeignTaxId и RegistrationAddress обязательны для нерезидентов
+	Country             *string `json:"country" validate:"omitempty,country"`
+	ForeignTaxId        *string `json:"foreignTaxId" validate:"omitempty,max=30"`
+	RegistrationAddress *string `json:"registrationAddress" validate:"omitempty,max=500"`
 }
 
+// StructLevelValidation требует у резидента БИН, а у нерезидента - страну, отличную от Казахстана, налоговый номер
+// в формате этой страны и адрес регистрации. Налоговый номер и адрес регистрации резидента не заполняются.
 func (dto ContractorDto) StructLevelValidation(sl validator.StructLevel) {
-	if dto.Resident && (dto.Bin == nil || *dto.Bin == "") {
-		sl.ReportError(dto.Bin, "bin", "Bin", "required", "")
+	contractorResidencyDto{
+		Resident:            dto.Resident,
+		Bin:                 dto.Bin,
+		Country:             dto.Country,
+		ForeignTaxId:        dto.ForeignTaxId,
+		RegistrationAddress: dto.RegistrationAddress,
+		nonResidentRequired: true,
+	}.StructLevelValidation(sl)
+}
+
+// ValidateUpdate проверяет поля резидентства изменяемого контрагента по тем же правилам, что и при создании,
+// но не требует у нерезидента страну, налоговый номер и адрес регистрации: ранее созданные нерезиденты
+// изменяются без их дополнения. Переданный налоговый номер проверяется только вместе со страной.
+// Заменяющие телефоны контрагента проверяются так же, как при создании.
+func (dto ContractorDto) ValidateUpdate() error {
+	err := cvalidator.ValidateStruct(contractorResidencyDto{
+		Resident:            dto.Resident,
+		Bin:                 dto.Bin,
+		Country:             dto.Country,
+		ForeignTaxId:        dto.ForeignTaxId,
+		RegistrationAddress: dto.RegistrationAddress,
+	})
+	if err != nil {
+		return err
 	}
+
+	return cvalidator.Validate.Struct(contractorPhonesDto{Phones: dto.Phones})
+}
+
+// contractorPhonesDto содержит телефоны, которыми заменяются телефоны контрагента при его изменении
+type contractorPhonesDto struct {
+	Phones []PhoneDto `json:"phones" validate:"dive"`
+}
+
+// contractorResidencyDto содержит поля контрагента, зависящие от резидентства.
+// nonResidentRequired требует у нерезидента заполнить все поля.
+type contractorResidencyDto struct {
+	Resident            bool    `json:"resident"`
+	Bin                 *string `json:"bin" validate:"omitempty,bin"`
+	Country             *string `json:"country" validate:"omitempty,country"`
+	ForeignTaxId        *string `json:"foreignTaxId" validate:"omitempty,max=30"`
+	RegistrationAddress *string `json:"registrationAddress" validate:"omitempty,max=500"`
+
+	nonResidentRequired bool
+}
+
+func (dto contractorResidencyDto) StructLevelValidation(sl validator.StructLevel) {
+	if dto.Resident {
+		if isEmpty(dto.Bin) {
+			sl.ReportError(dto.Bin, "bin", "Bin", "required", "")
+		}
+		if !isEmpty(dto.Country) && *dto.Country != model.CountryKazakhstan {
+			sl.ReportError(dto.Country, "country", "Country", "eq", model.CountryKazakhstan)
+		}
+		if !isEmpty(dto.ForeignTaxId) {
+			sl.ReportError(dto.ForeignTaxId, "foreignTaxId", "ForeignTaxId", "excluded_with", "Resident")
+		}
+		if !isEmpty(dto.RegistrationAddress) {
+			sl.ReportError(dto.RegistrationAddress, "registrationAddress", "RegistrationAddress",
+				"excluded_with", "Resident")
+		}
+		return
+	}
+
+	if isEmpty(dto.Country) {
+		if dto.nonResidentRequired || !isEmpty(dto.ForeignTaxId) {
+			sl.ReportError(dto.Country, "country", "Country", "required", "")
+		}
+	} else if *dto.Country == model.CountryKazakhstan {
+		sl.ReportError(dto.Country, "country", "Country", "ne", model.CountryKazakhstan)
+	}
+
+	if isEmpty(dto.ForeignTaxId) {
+		if dto.nonResidentRequired {
+			sl.ReportError(dto.ForeignTaxId, "foreignTaxId", "ForeignTaxId", "required", "")
+		}
+	} else if !isEmpty(dto.Country) {
+		if err := cvalidator.ParseForeignTaxId(*dto.Country, *dto.ForeignTaxId); err != nil {
+			sl.ReportError(dto.ForeignTaxId, "foreignTaxId", "ForeignTaxId", cvalidator.TagForeignTaxId, *dto.Country)
+		}
+	}
+
+	if isEmpty(dto.RegistrationAddress) && dto.nonResidentRequired {
+		sl.ReportError(dto.RegistrationAddress, "registrationAddress", "RegistrationAddress", "required", "")
+	}
+}
+
+func isEmpty(value *string) bool {
+	return value == nil || *value == ""
 }
 
 type EmployeeDto struct {
 	Id        int64      `json:"id"`
-	Email     string     `json:"email" validate:"required"`
+	Email     string     `json:"email" validate:"required,email"`
 	FullName  string     `json:"fullName"`
 	Position  string     `json:"position" validate:"required"`
 	BlockDate *time.Time `json:"blockDate"`
 	Status    string     `json:"status"`
+	CreatedAt *time.Time `json:"createdAt,omitempty"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
 }
 
 func ConvertContractors(list []model.Contractor) []interface{} {
@@ -54,8 +160,9 @@ func ConvertContractor(c model.Contractor) ContractorDto {
 		employees = append(employees, ConvertContractorEmployee(e))
 	}
 
-	return ContractorDto{
+	result := ContractorDto{
 		Id:            c.Id,
+		ParentId:      c.ParentId,
 		Resident:      c.Resident,
 		Bin:           c.Bin,
 		Name:          c.Name,
@@ -66,7 +173,77 @@ func ConvertContractor(c model.Contractor) ContractorDto {
 		AgentName:     c.AgentName,
 		AgentPassword: c.AgentPassword,
 		AgentPosition: c.AgentPosition,
+		CreatedAt:     optionalTime(c.CreatedAt),
+		UpdatedAt:     optionalTime(c.UpdatedAt),
+
+		Country:             c.Country,
+		ForeignTaxId:        c.ForeignTaxId,
+		RegistrationAddress: c.RegistrationAddress,
+	}
+
+	result.Tags = ConvertTags(c.Tags, false)
+
+	if c.Contacts != nil {
+		result.Addresses = ConvertAddresses(c.Contacts.Addresses)
+		result.Phones = ConvertPhones(c.Contacts.Phones)
+		result.Contacts = ConvertContacts(c.Contacts.Contacts)
+	}
+
+	return result
+}
+
+// ContractorTreeNodeDto является узлом иерархии контрагентов
+type ContractorTreeNodeDto struct {
+	Id       int64                   `json:"id"`
+	ParentId *int64                  `json:"parentId"`
+	Bin      *string                 `json:"bin"`
+	Name     *string                 `json:"name"`
+	Status   string                  `json:"status"`
+	Depth    int                     `json:"depth"`
+	Children []ContractorTreeNodeDto `json:"children"`
+}
+
+// ConvertContractorTree собирает дерево из узлов, упорядоченных по глубине, и возвращает его корень
+func ConvertContractorTree(nodes []model.ContractorTreeNode) *ContractorTreeNodeDto {
+	if len(nodes) == 0 {
+		return nil
 	}
+
+	children := make(map[int64][]model.ContractorTreeNode)
+	for _, node := range nodes[1:] {
+		if node.Contractor.ParentId != nil {
+			children[*node.Contractor.ParentId] = append(children[*node.Contractor.ParentId], node)
+		}
+	}
+
+	root := convertContractorTreeNode(nodes[0], children, map[int64]bool{})
+	return &root
+}
+
+// convertContractorTreeNode преобразует узел вместе с потомками. Уже преобразованные узлы visited пропускаются,
+// поэтому цикл в иерархии не приводит к бесконечной рекурсии.
+func convertContractorTreeNode(node model.ContractorTreeNode, children map[int64][]model.ContractorTreeNode,
+	visited map[int64]bool) ContractorTreeNodeDto {
+	c := node.Contractor
+	result := ContractorTreeNodeDto{
+		Id:       c.Id,
+		ParentId: c.ParentId,
+		Bin:      c.Bin,
+		Name:     c.Name,
+		Status:   string(c.Status),
+		Depth:    node.Depth,
+		Children: make([]ContractorTreeNodeDto, 0, len(children[c.Id])),
+	}
+
+	visited[c.Id] = true
+	for _, child := range children[c.Id] {
+		if visited[child.Contractor.Id] {
+			continue
+		}
+		result.Children = append(result.Children, convertContractorTreeNode(child, children, visited))
+	}
+
+	return result
 }
 
 func ConvertContractorEmployee(e model.Employee) EmployeeDto {
@@ -77,7 +254,18 @@ func ConvertContractorEmployee(e model.Employee) EmployeeDto {
 		Position:  e.Position,
 		BlockDate: e.BlockDate,
 		Status:    string(e.Status),
+		CreatedAt: optionalTime(e.CreatedAt),
+		UpdatedAt: optionalTime(e.UpdatedAt),
+	}
+}
+
+// optionalTime возвращает nil для незаполненного времени, чтобы не отдавать клиенту нулевую дату
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
 	}
+
+	return &t
 }
 
 func ParseContractorSearchParameters(values url.Values) (*model.ContractorSearchParameters, error) {
@@ -88,15 +276,83 @@ func ParseContractorSearchParameters(values url.Values) (*model.ContractorSearch
 
 	statusFilter := parseContractorStatusFilter(values)
 
+	facets, err := parseContractorFacets(values)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := ParseRsqlFilter(values, "filter", model.ContractorRsqlFields)
+	if err != nil {
+		return nil, err
+	}
+
+	withEmployees, err := ParseBoolFilter(values, "withEmployees", true)
+	if err != nil {
+		return nil, err
+	}
+
+	tagIds, err := parseIdListFilter(values, "tags")
+	if err != nil {
+		return nil, err
+	}
+
+	countries, err := parseCountryListFilter(values, "country")
+	if err != nil {
+		return nil, err
+	}
+
 	return &model.ContractorSearchParameters{
-		Pagination: *pagination,
-		Bin:        ParseStringFilter(values, "bin"),
-		Name:       ParseStringFilter(values, "name"),
-		Email:      ParseStringFilter(values, "email"),
-		Status:     statusFilter,
+		Pagination:    *pagination,
+		Facets:        facets,
+		Filter:        filter,
+		WithEmployees: withEmployees,
+		Bin:           ParseStringFilter(values, "bin"),
+		Name:          ParseStringFilter(values, "name"),
+		Email:         ParseStringFilter(values, "email"),
+		Status:        statusFilter,
+		TagIds:        tagIds,
+		Countries:     countries,
 	}, nil
 }
 
+// parseCountryListFilter разбирает список кодов стран параметра key
+func parseCountryListFilter(values url.Values, key string) ([]string, error) {
+	items := ParseListFilter(values, key)
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		code := strings.ToUpper(item)
+		if _, ok := model.FindCountry(code); !ok {
+			return nil, cerrors.ErrBadRequestVar(fmt.Errorf("неизвестная страна '%s'", item), key)
+		}
+		result = append(result, code)
+	}
+
+	return result, nil
+}
+
+// parseIdListFilter разбирает список ИД параметра key
+func parseIdListFilter(values url.Values, key string) ([]int64, error) {
+	items := ParseListFilter(values, key)
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	result := make([]int64, len(items))
+	for i, item := range items {
+		id, err := strconv.ParseInt(item, 10, 64)
+		if err != nil || id <= 0 {
+			return nil, cerrors.ErrBadRequestVar(fmt.Errorf("некорректный ИД '%s'", item), key)
+		}
+		result[i] = id
+	}
+
+	return uniqueIds(result), nil
+}
+
 func parseContractorStatusFilter(values url.Values) *model.ContractorStatus {
 	filter := ParseStringFilter(values, "status")
 	if filter == nil {
@@ -114,8 +370,64 @@ func parseContractorStatusFilter(values url.Values) *model.ContractorStatus {
 	}
 }
 
+func parseContractorFacets(values url.Values) ([]model.ContractorFacet, error) {
+	names := ParseListFilter(values, "facets")
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	result := make([]model.ContractorFacet, 0, len(names))
+	for _, name := range names {
+		facet := model.ContractorFacet(name)
+		if !containsContractorFacet(model.ContractorFacets, facet) {
+			return nil, cerrors.ErrBadRequestVar(fmt.Errorf("неизвестный агрегат '%s'", name), "facets")
+		}
+		if !containsContractorFacet(result, facet) {
+			result = append(result, facet)
+		}
+	}
+
+	return result, nil
+}
+
+func containsContractorFacet(list []model.ContractorFacet, facet model.ContractorFacet) bool {
+	for _, f := range list {
+		if f == facet {
+			return true
+		}
+	}
+
+	return false
+}
+
+func ConvertContractorFacets(facets map[model.ContractorFacet][]model.FacetValue) map[string][]FacetValueDto {
+	if len(facets) == 0 {
+		return nil
+	}
+
+	result := make(map[string][]FacetValueDto, len(facets))
+	for facet, values := range facets {
+		converted := make([]FacetValueDto, len(values))
+		for i, v := range values {
+			converted[i] = FacetValueDto{Value: v.Value, Count: v.Count}
+		}
+		result[string(facet)] = converted
+	}
+
+	return result
+}
+
+// ConvertContractorDtoToEntity создает контрагента. Резиденту всегда назначается страна Казахстан,
+// а налоговый номер и адрес регистрации не сохраняются.
 func ConvertContractorDtoToEntity(dto *ContractorDto) *model.Contractor {
+	country, foreignTaxId, registrationAddress := dto.Country, dto.ForeignTaxId, dto.RegistrationAddress
+	if dto.Resident {
+		kz := model.CountryKazakhstan
+		country, foreignTaxId, registrationAddress = &kz, nil, nil
+	}
+
 	return &model.Contractor{
+		ParentId:      dto.ParentId,
 		Resident:      dto.Resident,
 		Bin:           dto.Bin,
 		Name:          dto.Name,
@@ -124,6 +436,11 @@ func ConvertContractorDtoToEntity(dto *ContractorDto) *model.Contractor {
 		AgentName:     dto.AgentName,
 		AgentPosition: dto.AgentPosition,
 		AgentPassword: dto.AgentPassword,
+		Contacts:      convertContractorContactsDto(dto),
+
+		Country:             country,
+		ForeignTaxId:        foreignTaxId,
+		RegistrationAddress: registrationAddress,
 	}
 }
 
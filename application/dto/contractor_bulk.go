@@ -0,0 +1,79 @@
+package dto
+
+import (
+	"errors"
+	"service_admin_contractor/application/cerrors"
+	"service_admin_contractor/domain/model"
+)
+
+type ContractorBulkActionDto struct {
+	Action string  `json:"action" validate:"required,oneof=block unblock delete restore"`
+	Ids    []int64 `json:"ids" validate:"omitempty,max=1000,dive,min=1"`
+	Filter *string `json:"filter"`
+	Atomic *bool   `json:"atomic"`
+	// Propagate распространяет разблокировку на дочерних контрагентов, блокировка распространяется всегда
+	Propagate bool `json:"propagate"`
+}
+
+type ContractorBulkResultDto struct {
+	Action    string                        `json:"action"`
+	Atomic    bool                          `json:"atomic"`
+	Succeeded int                           `json:"succeeded"`
+	Failed    int                           `json:"failed"`
+	Items     []ContractorBulkItemResultDto `json:"items"`
+}
+
+type ContractorBulkItemResultDto struct {
+	Id      int64  `json:"id"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+// ConvertContractorBulkActionDtoToEntity проверяет, что задан ровно один из параметров `ids` и `filter`,
+// и разбирает RSQL выражение фильтра. По-умолчанию действие выполняется атомарно.
+func ConvertContractorBulkActionDtoToEntity(dto *ContractorBulkActionDto) (*model.ContractorBulkRequest, error) {
+	hasFilter := dto.Filter != nil && *dto.Filter != ""
+	if hasFilter == (len(dto.Ids) > 0) {
+		return nil, cerrors.ErrBadRequestVar(errors.New("необходимо указать либо ids, либо filter"), "ids")
+	}
+
+	request := &model.ContractorBulkRequest{
+		Action:    model.ContractorBulkAction(dto.Action),
+		Ids:       dto.Ids,
+		Atomic:    dto.Atomic == nil || *dto.Atomic,
+		Propagate: dto.Propagate,
+	}
+
+	if hasFilter {
+		node, err := model.ParseRsql(*dto.Filter)
+		if err != nil {
+			return nil, cerrors.ErrBadRequestVar(err, "filter")
+		}
+
+		if err = model.ContractorRsqlFields.Validate(node); err != nil {
+			return nil, cerrors.ErrBadRequestVar(err, "filter")
+		}
+
+		request.Filter = &model.ContractorSearchParameters{Filter: node}
+	}
+
+	return request, nil
+}
+
+func ConvertContractorBulkResult(result model.ContractorBulkResult) ContractorBulkResultDto {
+	items := make([]ContractorBulkItemResultDto, len(result.Items))
+	for i, item := range result.Items {
+		items[i] = ContractorBulkItemResultDto{Id: item.Id, Success: item.Error == nil}
+		if item.Error != nil {
+			items[i].Error = item.Error.Error()
+		}
+	}
+
+	return ContractorBulkResultDto{
+		Action:    string(result.Action),
+		Atomic:    result.Atomic,
+		Succeeded: result.Succeeded,
+		Failed:    result.Failed,
+		Items:     items,
+	}
+}
@@ -0,0 +1,234 @@
+package dto
+
+import (
+	"fmt"
+	"net/url"
+	"service_admin_contractor/application/cerrors"
+	"service_admin_contractor/application/export"
+	"service_admin_contractor/domain/model"
+	"strconv"
+	"time"
+)
+
+const exportDateLayout = "02.01.2006 15:04:05"
+
+// ExportColumn описывает колонку выгрузки контрагентов
+type ExportColumn struct {
+	Key    string
+	Header string
+	Value  func(row model.ContractorEmployeeRow) string
+}
+
+// ContractorExportColumns содержит колонки контрагента, доступные для выгрузки, в порядке по-умолчанию
+var ContractorExportColumns = []ExportColumn{
+	{"id", "ИД", func(r model.ContractorEmployeeRow) string {
+		return strconv.FormatInt(r.Contractor.Id, 10)
+	}},
+	{"resident", "Резидент", func(r model.ContractorEmployeeRow) string {
+		return formatExportBool(r.Contractor.Resident)
+	}},
+	{"bin", "БИН", func(r model.ContractorEmployeeRow) string {
+		return formatExportString(r.Contractor.Bin)
+	}},
+	{"country", "Страна", func(r model.ContractorEmployeeRow) string {
+		return formatExportString(r.Contractor.Country)
+	}},
+	{"foreignTaxId", "Налоговый номер", func(r model.ContractorEmployeeRow) string {
+		return formatExportString(r.Contractor.ForeignTaxId)
+	}},
+	{"registrationAddress", "Адрес регистрации", func(r model.ContractorEmployeeRow) string {
+		return formatExportString(r.Contractor.RegistrationAddress)
+	}},
+	{"name", "Наименование", func(r model.ContractorEmployeeRow) string {
+		return formatExportString(r.Contractor.Name)
+	}},
+	{"email", "Email", func(r model.ContractorEmployeeRow) string {
+		return r.Contractor.Email
+	}},
+	{"agentName", "ФИО представителя", func(r model.ContractorEmployeeRow) string {
+		return formatExportString(r.Contractor.AgentName)
+	}},
+	{"agentPosition", "Должность представителя", func(r model.ContractorEmployeeRow) string {
+		return formatExportString(r.Contractor.AgentPosition)
+	}},
+	{"status", "Статус", func(r model.ContractorEmployeeRow) string {
+		return string(r.Contractor.Status)
+	}},
+	{"blockDate", "Дата блокировки", func(r model.ContractorEmployeeRow) string {
+		return formatExportTime(r.Contractor.BlockDate)
+	}},
+}
+
+// EmployeeExportColumns содержит колонки сотрудника, добавляемые при развернутой выгрузке
+var EmployeeExportColumns = []ExportColumn{
+	{"employee.id", "ИД сотрудника", func(r model.ContractorEmployeeRow) string {
+		if r.Employee == nil {
+			return ""
+		}
+		return strconv.FormatInt(r.Employee.Id, 10)
+	}},
+	{"employee.email", "Email сотрудника", func(r model.ContractorEmployeeRow) string {
+		if r.Employee == nil {
+			return ""
+		}
+		return r.Employee.Email
+	}},
+	{"employee.fullName", "ФИО сотрудника", func(r model.ContractorEmployeeRow) string {
+		if r.Employee == nil {
+			return ""
+		}
+		return r.Employee.FullName
+	}},
+	{"employee.position", "Должность сотрудника", func(r model.ContractorEmployeeRow) string {
+		if r.Employee == nil {
+			return ""
+		}
+		return r.Employee.Position
+	}},
+	{"employee.status", "Статус сотрудника", func(r model.ContractorEmployeeRow) string {
+		if r.Employee == nil {
+			return ""
+		}
+		return string(r.Employee.Status)
+	}},
+	{"employee.blockDate", "Дата блокировки сотрудника", func(r model.ContractorEmployeeRow) string {
+		if r.Employee == nil {
+			return ""
+		}
+		return formatExportTime(r.Employee.BlockDate)
+	}},
+}
+
+// ContractorExportParameters содержит параметры выгрузки контрагентов
+type ContractorExportParameters struct {
+	Search        model.ContractorSearchParameters
+	Columns       []ExportColumn
+	WithEmployees bool
+	Bom           bool
+	Delimiter     rune
+}
+
+// ParseContractorExportParameters разбирает фильтры поиска контрагентов и параметры выгрузки:
+// `columns` - список колонок, `employees` - развернуть по сотрудникам, `bom` - добавить UTF-8 BOM,
+// `delimiter` - разделитель полей
+func ParseContractorExportParameters(values url.Values) (*ContractorExportParameters, error) {
+	search, err := ParseContractorSearchParameters(values)
+	if err != nil {
+		return nil, err
+	}
+	search.Pagination = *model.NewMaxPagination()
+	search.Facets = nil
+
+	withEmployees, err := ParseBoolFilter(values, "employees", false)
+	if err != nil {
+		return nil, err
+	}
+
+	bom, err := ParseBoolFilter(values, "bom", true)
+	if err != nil {
+		return nil, err
+	}
+
+	available := ContractorExportColumns
+	if withEmployees {
+		available = append(append([]ExportColumn{}, ContractorExportColumns...), EmployeeExportColumns...)
+	}
+
+	columns, err := parseExportColumns(values, available)
+	if err != nil {
+		return nil, err
+	}
+
+	delimiter := ','
+	if value := values.Get("delimiter"); value != "" {
+		runes := []rune(value)
+		if value == `\t` || value == "tab" {
+			runes = []rune{'\t'}
+		}
+		if len(runes) != 1 || runes[0] == '"' || runes[0] == '\r' || runes[0] == '\n' {
+			return nil, cerrors.ErrBadRequestVar(fmt.Errorf("недопустимый разделитель '%s'", value), "delimiter")
+		}
+		delimiter = runes[0]
+	}
+
+	return &ContractorExportParameters{
+		Search:        *search,
+		Columns:       columns,
+		WithEmployees: withEmployees,
+		Bom:           bom,
+		Delimiter:     delimiter,
+	}, nil
+}
+
+func parseExportColumns(values url.Values, available []ExportColumn) ([]ExportColumn, error) {
+	keys := ParseListFilter(values, "columns")
+	if len(keys) == 0 {
+		return available, nil
+	}
+
+	result := make([]ExportColumn, 0, len(keys))
+	for _, key := range keys {
+		column, ok := findExportColumn(available, key)
+		if !ok {
+			return nil, cerrors.ErrBadRequestVar(fmt.Errorf("неизвестная колонка '%s'", key), "columns")
+		}
+		result = append(result, column)
+	}
+
+	return result, nil
+}
+
+func findExportColumn(columns []ExportColumn, key string) (ExportColumn, bool) {
+	for _, column := range columns {
+		if column.Key == key {
+			return column, true
+		}
+	}
+
+	return ExportColumn{}, false
+}
+
+// ExportHeaders возвращает заголовки колонок выгрузки
+func ExportHeaders(columns []ExportColumn) []string {
+	result := make([]string, len(columns))
+	for i, column := range columns {
+		result[i] = column.Header
+	}
+
+	return result
+}
+
+// ExportRecord возвращает значения колонок выгрузки для строки row. Значения, начинающиеся с символа формулы,
+// экранируются, чтобы не вычисляться при открытии выгрузки в табличном редакторе
+func ExportRecord(columns []ExportColumn, row model.ContractorEmployeeRow) []string {
+	result := make([]string, len(columns))
+	for i, column := range columns {
+		result[i] = export.EscapeFormula(column.Value(row))
+	}
+
+	return result
+}
+
+func formatExportString(value *string) string {
+	if value == nil {
+		return ""
+	}
+
+	return *value
+}
+
+func formatExportBool(value bool) string {
+	if value {
+		return "Да"
+	}
+
+	return "Нет"
+}
+
+func formatExportTime(value *time.Time) string {
+	if value == nil {
+		return ""
+	}
+
+	return value.Format(exportDateLayout)
+}
@@ -0,0 +1,62 @@
+package dto
+
+import "service_admin_contractor/domain/model"
+
+type ContractorImportReportDto struct {
+	DryRun  bool                     `json:"dryRun"`
+	Total   int                      `json:"total"`
+	Created int                      `json:"created"`
+	Failed  int                      `json:"failed"`
+	Rows    []ContractorImportRowDto `json:"rows"`
+}
+
+type ContractorImportRowDto struct {
+	Row           int                      `json:"row"`
+	Id            int64                    `json:"id,omitempty"`
+	Email         string                   `json:"email"`
+	Name          *string                  `json:"name"`
+	AgentPassword string                   `json:"agentPassword,omitempty"`
+	Errors        []map[string]interface{} `json:"errors,omitempty"`
+}
+
+func ConvertContractorImportReport(report model.ContractorImportReport) ContractorImportReportDto {
+	rows := make([]ContractorImportRowDto, len(report.Rows))
+	for i, row := range report.Rows {
+		rows[i] = ContractorImportRowDto{
+			Row:    row.Row,
+			Errors: ConvertValidationErrors(row.Errors),
+		}
+
+		if row.Contractor != nil {
+			rows[i].Id = row.Contractor.Id
+			rows[i].Email = row.Contractor.Email
+			rows[i].Name = row.Contractor.Name
+			rows[i].AgentPassword = row.Contractor.AgentPassword
+		}
+	}
+
+	return ContractorImportReportDto{
+		DryRun:  report.DryRun,
+		Total:   len(report.Rows),
+		Created: report.Created,
+		Failed:  report.Failed,
+		Rows:    rows,
+	}
+}
+
+// ConvertValidationErrors приводит ошибки строк к формату `data` ошибок валидации
+func ConvertValidationErrors(errs []model.ValidationError) []map[string]interface{} {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	result := make([]map[string]interface{}, len(errs))
+	for i, err := range errs {
+		result[i] = map[string]interface{}{
+			"problem_param":   err.Field,
+			"problem_message": err.Message,
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,30 @@
+package dto
+
+import (
+	"service_admin_contractor/application/cvalidator"
+	"service_admin_contractor/domain/model"
+)
+
+type CountryDto struct {
+	Code    string `json:"code"`
+	Alpha3  string `json:"alpha3"`
+	Numeric string `json:"numeric"`
+	Name    string `json:"name"`
+	// ForeignTaxIdName является названием налогового номера нерезидента этой страны
+	ForeignTaxIdName string `json:"foreignTaxIdName"`
+}
+
+func ConvertCountries(list []model.Country) []CountryDto {
+	result := make([]CountryDto, len(list))
+	for i, c := range list {
+		result[i] = CountryDto{
+			Code:             c.Code,
+			Alpha3:           c.Alpha3,
+			Numeric:          c.Numeric,
+			Name:             c.Name,
+			ForeignTaxIdName: cvalidator.ForeignTaxIdName(c.Code),
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,97 @@
+package dto
+
+import (
+	"github.com/go-playground/validator/v10"
+	"service_admin_contractor/domain/model"
+	"time"
+)
+
+// dateLayout является форматом дат без времени в запросах и ответах
+const dateLayout = "2006-01-02"
+
+// DocumentUploadDto содержит метаданные загружаемого документа из полей multipart формы
+type DocumentUploadDto struct {
+	Type       string  `json:"type" validate:"required,oneof=CHARTER REGISTRATION_CERTIFICATE NDA OTHER"`
+	Number     *string `json:"number"`
+	IssueDate  string  `json:"issueDate" validate:"omitempty,datetime=2006-01-02"`
+	ExpiryDate string  `json:"expiryDate" validate:"omitempty,datetime=2006-01-02"`
+}
+
+func (dto DocumentUploadDto) StructLevelValidation(sl validator.StructLevel) {
+	if dto.IssueDate != "" && dto.ExpiryDate != "" && dto.ExpiryDate < dto.IssueDate {
+		sl.ReportError(dto.ExpiryDate, "expiryDate", "ExpiryDate", "gtefield", "IssueDate")
+	}
+}
+
+type DocumentDto struct {
+	Id          int64     `json:"id"`
+	Type        string    `json:"type"`
+	Number      *string   `json:"number"`
+	IssueDate   *string   `json:"issueDate"`
+	ExpiryDate  *string   `json:"expiryDate"`
+	FileName    string    `json:"fileName"`
+	ContentType string    `json:"contentType"`
+	Size        int64     `json:"size"`
+	Sha256      string    `json:"sha256"`
+	UploadedBy  *string   `json:"uploadedBy"`
+	UploadedAt  time.Time `json:"uploadedAt"`
+}
+
+func ConvertDocuments(list []model.Document) []DocumentDto {
+	result := make([]DocumentDto, len(list))
+	for i := range list {
+		result[i] = ConvertDocument(list[i])
+	}
+
+	return result
+}
+
+func ConvertDocument(d model.Document) DocumentDto {
+	return DocumentDto{
+		Id:          d.Id,
+		Type:        string(d.Type),
+		Number:      d.Number,
+		IssueDate:   formatOptionalDate(d.IssueDate),
+		ExpiryDate:  formatOptionalDate(d.ExpiryDate),
+		FileName:    d.FileName,
+		ContentType: d.ContentType,
+		Size:        d.Size,
+		Sha256:      d.Sha256,
+		UploadedBy:  d.UploadedBy,
+		UploadedAt:  d.UploadedAt,
+	}
+}
+
+// ConvertDocumentUploadDtoToEntity создает метаданные документа. Даты должны быть проверены валидатором.
+func ConvertDocumentUploadDtoToEntity(contractorId int64, fileName string, dto *DocumentUploadDto) *model.Document {
+	return &model.Document{
+		ContractorId: contractorId,
+		Type:         model.DocumentType(dto.Type),
+		Number:       dto.Number,
+		IssueDate:    parseOptionalDate(dto.IssueDate),
+		ExpiryDate:   parseOptionalDate(dto.ExpiryDate),
+		FileName:     fileName,
+	}
+}
+
+func formatOptionalDate(value *time.Time) *string {
+	if value == nil {
+		return nil
+	}
+
+	result := value.Format(dateLayout)
+	return &result
+}
+
+func parseOptionalDate(value string) *time.Time {
+	if value == "" {
+		return nil
+	}
+
+	result, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return nil
+	}
+
+	return &result
+}
@@ -0,0 +1,41 @@
+package dto
+
+import "service_admin_contractor/domain/model"
+
+type EmployeeImportReportDto struct {
+	Created int                    `json:"created"`
+	Updated int                    `json:"updated"`
+	Failed  int                    `json:"failed"`
+	Rows    []EmployeeImportRowDto `json:"rows"`
+}
+
+type EmployeeImportRowDto struct {
+	Row    int                      `json:"row"`
+	Id     int64                    `json:"id,omitempty"`
+	Email  string                   `json:"email"`
+	Result string                   `json:"result"`
+	Errors []map[string]interface{} `json:"errors,omitempty"`
+}
+
+func ConvertEmployeeImportReport(report model.EmployeeImportReport) EmployeeImportReportDto {
+	rows := make([]EmployeeImportRowDto, len(report.Rows))
+	for i, row := range report.Rows {
+		rows[i] = EmployeeImportRowDto{
+			Row:    row.Row,
+			Result: string(row.Result),
+			Errors: ConvertValidationErrors(row.Errors),
+		}
+
+		if row.Employee != nil {
+			rows[i].Id = row.Employee.Id
+			rows[i].Email = row.Employee.Email
+		}
+	}
+
+	return EmployeeImportReportDto{
+		Created: report.Created,
+		Updated: report.Updated,
+		Failed:  report.Failed,
+		Rows:    rows,
+	}
+}
@@ -9,3 +9,13 @@ type PaginationMetaDto struct {
 	Count int64 `json:"count"`
 	Total int64 `json:"total"`
 }
+
+type FacetValueDto struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+type ContractorSearchMetaDto struct {
+	PaginationMetaDto
+	Facets map[string][]FacetValueDto `json:"facets,omitempty"`
+}
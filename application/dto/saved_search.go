@@ -0,0 +1,107 @@
+package dto
+
+import (
+	"net/url"
+	"service_admin_contractor/domain/model"
+	"time"
+)
+
+// savedSearchIgnoredParameters не сохраняются в поиске и не подменяются при его применении
+var savedSearchIgnoredParameters = []string{"page", "size", "count", "savedSearch"}
+
+type SavedSearchDto struct {
+	Id         int64               `json:"id"`
+	Name       string              `json:"name" validate:"required"`
+	SharedRole *string             `json:"sharedRole"`
+	Parameters map[string][]string `json:"parameters"`
+	OwnerLogin string              `json:"ownerLogin"`
+	CreatedAt  *time.Time          `json:"createdAt"`
+}
+
+func ConvertSavedSearches(list []model.SavedSearch) []interface{} {
+	result := make([]interface{}, len(list))
+
+	for i := range list {
+		result[i] = ConvertSavedSearch(list[i])
+	}
+
+	return result
+}
+
+func ConvertSavedSearch(s model.SavedSearch) SavedSearchDto {
+	parameters, err := url.ParseQuery(s.Query)
+	if err != nil {
+		parameters = url.Values{}
+	}
+
+	var sharedRole *string
+	if s.SharedRole != nil {
+		role := string(*s.SharedRole)
+		sharedRole = &role
+	}
+
+	createdAt := s.CreatedAt
+
+	return SavedSearchDto{
+		Id:         s.Id,
+		Name:       s.Name,
+		SharedRole: sharedRole,
+		Parameters: parameters,
+		OwnerLogin: s.OwnerLogin,
+		CreatedAt:  &createdAt,
+	}
+}
+
+// ConvertSavedSearchDtoToEntity проверяет параметры поиска теми же правилами, что и GET /contractors,
+// и сохраняет их в виде строки запроса
+func ConvertSavedSearchDtoToEntity(dto *SavedSearchDto) (*model.SavedSearch, error) {
+	parameters := url.Values{}
+	for key, values := range dto.Parameters {
+		if !containsString(savedSearchIgnoredParameters, key) {
+			parameters[key] = values
+		}
+	}
+
+	if _, err := ParseContractorSearchParameters(parameters); err != nil {
+		return nil, err
+	}
+
+	var sharedRole *model.RoleCode
+	if dto.SharedRole != nil && *dto.SharedRole != "" {
+		role := model.RoleCode(*dto.SharedRole)
+		sharedRole = &role
+	}
+
+	return &model.SavedSearch{
+		Name:       dto.Name,
+		SharedRole: sharedRole,
+		Query:      parameters.Encode(),
+	}, nil
+}
+
+// ApplySavedSearch дополняет параметры запроса параметрами сохраненного поиска.
+// Явно переданные в запросе параметры имеют приоритет над сохраненными.
+func ApplySavedSearch(search model.SavedSearch, values url.Values) (url.Values, error) {
+	result, err := url.ParseQuery(search.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, value := range values {
+		if key != "savedSearch" {
+			result[key] = value
+		}
+	}
+
+	return result, nil
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -3,8 +3,10 @@ package dto
 import (
 	"errors"
 	"net/url"
+	"service_admin_contractor/application/cerrors"
 	"service_admin_contractor/domain/model"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -84,3 +86,52 @@ func ParseStringFilter(values url.Values, key string) *string {
 
 	return &statusFilterStr
 }
+
+// ParseListFilter разбирает параметр, заданный списком через запятую или повторением ключа
+func ParseListFilter(values url.Values, key string) []string {
+	result := make([]string, 0)
+	for _, value := range values[key] {
+		for _, item := range strings.Split(value, ",") {
+			item = strings.TrimSpace(item)
+			if item != "" {
+				result = append(result, item)
+			}
+		}
+	}
+
+	return result
+}
+
+// ParseRsqlFilter разбирает RSQL выражение параметра key и проверяет его по белому списку полей fields
+func ParseRsqlFilter(values url.Values, key string, fields model.RsqlFields) (model.RsqlNode, error) {
+	expression := values.Get(key)
+	if expression == "" {
+		return nil, nil
+	}
+
+	node, err := model.ParseRsql(expression)
+	if err != nil {
+		return nil, cerrors.ErrBadRequestVar(err, key)
+	}
+
+	if err = fields.Validate(node); err != nil {
+		return nil, cerrors.ErrBadRequestVar(err, key)
+	}
+
+	return node, nil
+}
+
+// ParseBoolFilter разбирает логический параметр key, при его отсутствии возвращает defaultValue
+func ParseBoolFilter(values url.Values, key string, defaultValue bool) (bool, error) {
+	value := values.Get(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	result, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, cerrors.ErrBadRequestVar(err, key)
+	}
+
+	return result, nil
+}
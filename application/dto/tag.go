@@ -0,0 +1,73 @@
+package dto
+
+import (
+	"service_admin_contractor/domain/model"
+	"time"
+)
+
+type TagDto struct {
+	Id          int64      `json:"id"`
+	Category    string     `json:"category" validate:"required,max=100"`
+	Name        string     `json:"name" validate:"required,max=100"`
+	Description *string    `json:"description"`
+	UsageCount  *int64     `json:"usageCount,omitempty"`
+	CreatedAt   *time.Time `json:"createdAt,omitempty"`
+	UpdatedAt   *time.Time `json:"updatedAt,omitempty"`
+}
+
+// ContractorTagsDto является набором тегов, заменяющим теги контрагента
+type ContractorTagsDto struct {
+	TagIds []int64 `json:"tagIds" validate:"dive,min=1"`
+}
+
+// ConvertTags преобразует теги; withUsage добавляет количество использований
+func ConvertTags(list []model.Tag, withUsage bool) []TagDto {
+	result := make([]TagDto, len(list))
+	for i := range list {
+		result[i] = ConvertTag(list[i])
+		if withUsage {
+			usageCount := list[i].UsageCount
+			result[i].UsageCount = &usageCount
+		}
+	}
+
+	return result
+}
+
+func ConvertTag(t model.Tag) TagDto {
+	return TagDto{
+		Id:          t.Id,
+		Category:    t.Category,
+		Name:        t.Name,
+		Description: t.Description,
+		CreatedAt:   optionalTime(t.CreatedAt),
+		UpdatedAt:   optionalTime(t.UpdatedAt),
+	}
+}
+
+func ConvertTagDtoToEntity(dto *TagDto) *model.Tag {
+	return &model.Tag{
+		Id:          dto.Id,
+		Category:    dto.Category,
+		Name:        dto.Name,
+		Description: dto.Description,
+	}
+}
+
+// uniqueIds возвращает ИД без повторов в исходном порядке
+func uniqueIds(ids []int64) []int64 {
+	result := make([]int64, 0, len(ids))
+	seen := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+
+	return result
+}
+
+func (dto ContractorTagsDto) UniqueTagIds() []int64 {
+	return uniqueIds(dto.TagIds)
+}
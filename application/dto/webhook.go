@@ -0,0 +1,146 @@
+package dto
+
+import (
+	"fmt"
+	"service_admin_contractor/application/cerrors"
+	"service_admin_contractor/domain/model"
+	"time"
+)
+
+type WebhookSubscriptionDto struct {
+	Id         int64      `json:"id"`
+	Url        string     `json:"url" validate:"required,url"`
+	EventTypes []string   `json:"eventTypes" validate:"required,min=1"`
+	Secret     string     `json:"secret,omitempty" validate:"omitempty,min=16"`
+	Active     *bool      `json:"active"`
+	CreatedBy  string     `json:"createdBy"`
+	CreatedAt  *time.Time `json:"createdAt"`
+}
+
+type WebhookDeliveryDto struct {
+	Id             int64                       `json:"id"`
+	EventId        string                      `json:"eventId"`
+	EventType      string                      `json:"eventType"`
+	Status         string                      `json:"status"`
+	Attempts       int                         `json:"attempts"`
+	NextAttemptAt  *time.Time                  `json:"nextAttemptAt"`
+	LastStatusCode *int                        `json:"lastStatusCode"`
+	LastError      *string                     `json:"lastError"`
+	CreatedAt      time.Time                   `json:"createdAt"`
+	DeliveredAt    *time.Time                  `json:"deliveredAt"`
+	AttemptLog     []WebhookDeliveryAttemptDto `json:"attemptLog"`
+}
+
+type WebhookDeliveryAttemptDto struct {
+	AttemptedAt time.Time `json:"attemptedAt"`
+	StatusCode  *int      `json:"statusCode"`
+	Error       *string   `json:"error"`
+	DurationMs  int64     `json:"durationMs"`
+}
+
+// ConvertWebhookSubscriptionDtoToEntity проверяет типы событий подписки. По-умолчанию подписка активна.
+func ConvertWebhookSubscriptionDtoToEntity(dto *WebhookSubscriptionDto) (*model.WebhookSubscription, error) {
+	eventTypes := make([]model.EventType, 0, len(dto.EventTypes))
+	for _, name := range dto.EventTypes {
+		eventType := model.EventType(name)
+		if !containsEventType(model.EventTypes, eventType) {
+			return nil, cerrors.ErrBadRequestVar(fmt.Errorf("неизвестный тип события '%s'", name), "eventTypes")
+		}
+		if !containsEventType(eventTypes, eventType) {
+			eventTypes = append(eventTypes, eventType)
+		}
+	}
+
+	return &model.WebhookSubscription{
+		Url:        dto.Url,
+		EventTypes: eventTypes,
+		Secret:     dto.Secret,
+		Active:     dto.Active == nil || *dto.Active,
+	}, nil
+}
+
+func containsEventType(list []model.EventType, eventType model.EventType) bool {
+	for _, item := range list {
+		if item == eventType {
+			return true
+		}
+	}
+
+	return false
+}
+
+func ConvertWebhookSubscriptions(list []model.WebhookSubscription) []interface{} {
+	result := make([]interface{}, len(list))
+
+	for i := range list {
+		result[i] = ConvertWebhookSubscription(list[i], false)
+	}
+
+	return result
+}
+
+// ConvertWebhookSubscription конвертирует подписку. Секрет отдается только при withSecret,
+// чтобы он был показан один раз при создании.
+func ConvertWebhookSubscription(s model.WebhookSubscription, withSecret bool) WebhookSubscriptionDto {
+	eventTypes := make([]string, len(s.EventTypes))
+	for i, eventType := range s.EventTypes {
+		eventTypes[i] = string(eventType)
+	}
+
+	active := s.Active
+	createdAt := s.CreatedAt
+	result := WebhookSubscriptionDto{
+		Id:         s.Id,
+		Url:        s.Url,
+		EventTypes: eventTypes,
+		Active:     &active,
+		CreatedBy:  s.CreatedBy,
+		CreatedAt:  &createdAt,
+	}
+	if withSecret {
+		result.Secret = s.Secret
+	}
+
+	return result
+}
+
+func ConvertWebhookDeliveries(list []model.WebhookDelivery) []interface{} {
+	result := make([]interface{}, len(list))
+
+	for i := range list {
+		result[i] = ConvertWebhookDelivery(list[i])
+	}
+
+	return result
+}
+
+func ConvertWebhookDelivery(d model.WebhookDelivery) WebhookDeliveryDto {
+	attempts := make([]WebhookDeliveryAttemptDto, len(d.AttemptLog))
+	for i, a := range d.AttemptLog {
+		attempts[i] = WebhookDeliveryAttemptDto{
+			AttemptedAt: a.AttemptedAt,
+			StatusCode:  a.StatusCode,
+			Error:       a.Error,
+			DurationMs:  a.Duration.Milliseconds(),
+		}
+	}
+
+	var nextAttemptAt *time.Time
+	if d.Status == model.WebhookDeliveryPending {
+		nextAttemptAt = &d.NextAttemptAt
+	}
+
+	return WebhookDeliveryDto{
+		Id:             d.Id,
+		EventId:        d.EventId,
+		EventType:      string(d.EventType),
+		Status:         string(d.Status),
+		Attempts:       d.Attempts,
+		NextAttemptAt:  nextAttemptAt,
+		LastStatusCode: d.LastStatusCode,
+		LastError:      d.LastError,
+		CreatedAt:      d.CreatedAt,
+		DeliveredAt:    d.DeliveredAt,
+		AttemptLog:     attempts,
+	}
+}
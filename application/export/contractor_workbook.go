@@ -0,0 +1,246 @@
+package export
+
+import (
+	"github.com/xuri/excelize/v2"
+	"service_admin_contractor/domain/model"
+	"time"
+)
+
+const (
+	ContentTypeXlsx = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+	contractorsSheet = "Контрагенты"
+	employeesSheet   = "Сотрудники"
+
+	dateNumFmt = "dd.mm.yyyy hh:mm"
+)
+
+var (
+	contractorHeaders = []interface{}{
+		"ИД", "Резидент", "БИН", "Страна", "Налоговый номер", "Адрес регистрации", "Наименование", "Email",
+		"ФИО представителя", "Должность представителя", "Статус", "Дата блокировки", "Количество сотрудников",
+	}
+	contractorWidths = []float64{10, 10, 16, 10, 20, 50, 40, 30, 30, 30, 12, 20, 14}
+
+	employeeHeaders = []interface{}{
+		"ИД", "ИД контрагента", "Контрагент", "Email", "ФИО", "Должность", "Статус", "Дата блокировки",
+	}
+	employeeWidths = []float64{10, 14, 40, 30, 30, 30, 12, 20}
+)
+
+// workbookStyles содержит идентификаторы стилей книги
+type workbookStyles struct {
+	header int
+	date   int
+	status map[string]int
+}
+
+// ContractorWorkbook строит книгу Excel с листами контрагентов и их сотрудников.
+// Строка заголовков закреплена, ячейки статусов окрашены в зависимости от значения.
+func ContractorWorkbook(contractors []model.Contractor) (*excelize.File, error) {
+	f := excelize.NewFile()
+
+	styles, err := newWorkbookStyles(f)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = f.SetSheetName(f.GetSheetName(0), contractorsSheet); err != nil {
+		return nil, err
+	}
+	if _, err = f.NewSheet(employeesSheet); err != nil {
+		return nil, err
+	}
+
+	if err = writeContractorsSheet(f, styles, contractors); err != nil {
+		return nil, err
+	}
+	if err = writeEmployeesSheet(f, styles, contractors); err != nil {
+		return nil, err
+	}
+
+	f.SetActiveSheet(0)
+
+	return f, nil
+}
+
+func newWorkbookStyles(f *excelize.File) (*workbookStyles, error) {
+	header, err := f.NewStyle(&excelize.Style{
+		Font:      &excelize.Font{Bold: true},
+		Fill:      excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"#D9D9D9"}},
+		Alignment: &excelize.Alignment{Vertical: "center", WrapText: true},
+		Border: []excelize.Border{
+			{Type: "bottom", Color: "#808080", Style: 1},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	numFmt := dateNumFmt
+	date, err := f.NewStyle(&excelize.Style{CustomNumFmt: &numFmt})
+	if err != nil {
+		return nil, err
+	}
+
+	status := make(map[string]int)
+	for value, color := range map[string]string{
+		string(model.ContractorStatusActive): "#C6EFCE",
+		string(model.ContractorStatusBlock):  "#FFC7CE",
+	} {
+		id, err := f.NewStyle(&excelize.Style{
+			Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{color}},
+		})
+		if err != nil {
+			return nil, err
+		}
+		status[value] = id
+	}
+
+	return &workbookStyles{header: header, date: date, status: status}, nil
+}
+
+func writeContractorsSheet(f *excelize.File, styles *workbookStyles, contractors []model.Contractor) error {
+	sw, err := newSheetWriter(f, contractorsSheet, contractorWidths)
+	if err != nil {
+		return err
+	}
+
+	if err = sw.SetRow("A1", styled(contractorHeaders, styles.header)); err != nil {
+		return err
+	}
+
+	for i, c := range contractors {
+		row := []interface{}{
+			c.Id,
+			formatBool(c.Resident),
+			stringValue(c.Bin),
+			stringValue(c.Country),
+			stringValue(c.ForeignTaxId),
+			stringValue(c.RegistrationAddress),
+			stringValue(c.Name),
+			textValue(c.Email),
+			stringValue(c.AgentName),
+			stringValue(c.AgentPosition),
+			statusCell(styles, string(c.Status)),
+			dateCell(styles, c.BlockDate),
+			len(c.Employees),
+		}
+
+		if err = sw.SetRow(rowCell(i+2), row); err != nil {
+			return err
+		}
+	}
+
+	return sw.Flush()
+}
+
+func writeEmployeesSheet(f *excelize.File, styles *workbookStyles, contractors []model.Contractor) error {
+	sw, err := newSheetWriter(f, employeesSheet, employeeWidths)
+	if err != nil {
+		return err
+	}
+
+	if err = sw.SetRow("A1", styled(employeeHeaders, styles.header)); err != nil {
+		return err
+	}
+
+	rowNum := 2
+	for _, c := range contractors {
+		for _, e := range c.Employees {
+			row := []interface{}{
+				e.Id,
+				c.Id,
+				stringValue(c.Name),
+				textValue(e.Email),
+				textValue(e.FullName),
+				textValue(e.Position),
+				statusCell(styles, string(e.Status)),
+				dateCell(styles, e.BlockDate),
+			}
+
+			if err = sw.SetRow(rowCell(rowNum), row); err != nil {
+				return err
+			}
+			rowNum++
+		}
+	}
+
+	return sw.Flush()
+}
+
+// newSheetWriter создает потоковую запись листа с закрепленной строкой заголовков
+func newSheetWriter(f *excelize.File, sheet string, widths []float64) (*excelize.StreamWriter, error) {
+	sw, err := f.NewStreamWriter(sheet)
+	if err != nil {
+		return nil, err
+	}
+
+	err = sw.SetPanes(&excelize.Panes{
+		Freeze:      true,
+		YSplit:      1,
+		TopLeftCell: "A2",
+		ActivePane:  "bottomLeft",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, width := range widths {
+		if err = sw.SetColWidth(i+1, i+1, width); err != nil {
+			return nil, err
+		}
+	}
+
+	return sw, nil
+}
+
+func rowCell(row int) string {
+	cell, _ := excelize.CoordinatesToCellName(1, row)
+	return cell
+}
+
+func styled(values []interface{}, styleId int) []interface{} {
+	result := make([]interface{}, len(values))
+	for i, value := range values {
+		result[i] = excelize.Cell{StyleID: styleId, Value: value}
+	}
+
+	return result
+}
+
+func statusCell(styles *workbookStyles, status string) interface{} {
+	if styleId, ok := styles.status[status]; ok {
+		return excelize.Cell{StyleID: styleId, Value: status}
+	}
+
+	return status
+}
+
+func dateCell(styles *workbookStyles, value *time.Time) interface{} {
+	if value == nil {
+		return nil
+	}
+
+	return excelize.Cell{StyleID: styles.date, Value: value.Local()}
+}
+
+func stringValue(value *string) interface{} {
+	if value == nil {
+		return nil
+	}
+
+	return EscapeFormula(*value)
+}
+
+func textValue(value string) string {
+	return EscapeFormula(value)
+}
+
+func formatBool(value bool) string {
+	if value {
+		return "Да"
+	}
+
+	return "Нет"
+}
@@ -0,0 +1,16 @@
+package export
+
+import "strings"
+
+// formulaPrefixes содержит символы, с которых табличные редакторы начинают формулу
+const formulaPrefixes = "=+-@"
+
+// EscapeFormula экранирует значение ячейки, начинающееся с символа формулы, добавляя апостроф в начало,
+// чтобы при открытии выгрузки значение отображалось как текст и не вычислялось
+func EscapeFormula(value string) string {
+	if value != "" && strings.ContainsRune(formulaPrefixes, rune(value[0])) {
+		return "'" + value
+	}
+
+	return value
+}
@@ -0,0 +1,233 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"github.com/xuri/excelize/v2"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"service_admin_contractor/application/cvalidator"
+	"service_admin_contractor/application/dto"
+	"service_admin_contractor/domain/model"
+	"strconv"
+	"strings"
+)
+
+type Format string
+
+const (
+	FormatCsv  Format = "csv"
+	FormatXlsx Format = "xlsx"
+
+	contentTypeXlsx = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+var utf8Bom = []byte("\xEF\xBB\xBF")
+
+// contractorImportHeaders сопоставляет заголовки колонок файла полям dto.ContractorDto.
+// Поддерживаются как ключи полей, так и заголовки CSV/XLSX выгрузки.
+var contractorImportHeaders = map[string]string{
+	"resident":            "resident",
+	"резидент":            "resident",
+	"bin":                 "bin",
+	"бин":                 "bin",
+	"country":             "country",
+	"страна":              "country",
+	"foreigntaxid":        "foreignTaxId",
+	"налоговый номер":     "foreignTaxId",
+	"registrationaddress": "registrationAddress",
+	"адрес регистрации":   "registrationAddress",
+	"name":                "name",
+	"наименование":        "name",
+	"email":               "email",
+	"agentname":           "agentName",
+	"фио представителя":   "agentName",
+	"agentposition":       "agentPosition",
+	"должность представителя": "agentPosition",
+}
+
+// DetectFormat определяет формат файла по расширению имени или по типу содержимого
+func DetectFormat(filename string, contentType string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return FormatCsv, nil
+	case ".xlsx":
+		return FormatXlsx, nil
+	}
+
+	switch {
+	case strings.HasPrefix(contentType, contentTypeXlsx):
+		return FormatXlsx, nil
+	case strings.HasPrefix(contentType, "text/csv"), strings.HasPrefix(contentType, "text/plain"):
+		return FormatCsv, nil
+	}
+
+	return "", fmt.Errorf("неподдерживаемый формат файла '%s', ожидается CSV или XLSX", filename)
+}
+
+// ReadRecords читает таблицу из первого листа XLSX или из CSV файла.
+// Разделитель CSV (',', ';' или табуляция) определяется по строке заголовков.
+func ReadRecords(r io.Reader, format Format) ([][]string, error) {
+	if format == FormatXlsx {
+		f, err := excelize.OpenReader(r)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		return f.GetRows(f.GetSheetName(0))
+	}
+
+	content, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	content = bytes.TrimPrefix(content, utf8Bom)
+
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.Comma = detectCsvDelimiter(content)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	return reader.ReadAll()
+}
+
+func detectCsvDelimiter(content []byte) rune {
+	header := content
+	if i := bytes.IndexByte(content, '\n'); i >= 0 {
+		header = content[:i]
+	}
+
+	result, max := ',', bytes.Count(header, []byte(","))
+	for _, delimiter := range []rune{';', '\t'} {
+		if count := bytes.Count(header, []byte(string(delimiter))); count > max {
+			result, max = delimiter, count
+		}
+	}
+
+	return result
+}
+
+// ReadContractorImportRows читает файл импорта контрагентов и проверяет каждую строку
+// по тем же правилам, что и тело запроса POST /contractors
+func ReadContractorImportRows(r io.Reader, format Format) ([]model.ContractorImportRow, error) {
+	records, err := ReadRecords(r, format)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		return nil, errors.New("файл не содержит строки заголовков")
+	}
+
+	columns := make(map[string]int)
+	for i, header := range records[0] {
+		if field, ok := contractorImportHeaders[strings.ToLower(strings.TrimSpace(header))]; ok {
+			columns[field] = i
+		}
+	}
+
+	if _, ok := columns["email"]; !ok {
+		return nil, errors.New("в файле отсутствует колонка email")
+	}
+
+	result := make([]model.ContractorImportRow, 0, len(records)-1)
+	for i, record := range records[1:] {
+		if isEmptyRecord(record) {
+			continue
+		}
+
+		result = append(result, readContractorImportRow(i+2, record, columns))
+	}
+
+	return result, nil
+}
+
+func readContractorImportRow(rowNum int, record []string, columns map[string]int) model.ContractorImportRow {
+	row := model.ContractorImportRow{Row: rowNum}
+	value := func(field string) string {
+		if i, ok := columns[field]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	requestDto := &dto.ContractorDto{
+		Bin:           optionalString(value("bin")),
+		Name:          optionalString(value("name")),
+		Email:         value("email"),
+		AgentName:     optionalString(value("agentName")),
+		AgentPosition: optionalString(value("agentPosition")),
+
+		Country:             optionalString(strings.ToUpper(value("country"))),
+		ForeignTaxId:        optionalString(value("foreignTaxId")),
+		RegistrationAddress: optionalString(value("registrationAddress")),
+	}
+
+	resident, err := parseImportBool(value("resident"))
+	if err != nil {
+		row.Errors = append(row.Errors, *model.NewValidationError("resident", err.Error()))
+	}
+	requestDto.Resident = resident
+
+	if err = cvalidator.ValidateStruct(requestDto); err != nil {
+		row.Errors = append(row.Errors, convertValidationErrors(err)...)
+	}
+
+	row.Contractor = dto.ConvertContractorDtoToEntity(requestDto)
+
+	return row
+}
+
+func convertValidationErrors(err error) []model.ValidationError {
+	ve, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return []model.ValidationError{*model.NewValidationError("", err.Error())}
+	}
+
+	result := make([]model.ValidationError, len(ve))
+	for i, fe := range ve {
+		result[i] = *model.NewValidationError(fe.Field(),
+			fmt.Sprintf("значение не соответсвует тегу `%s`", fe.Tag()))
+	}
+
+	return result
+}
+
+func parseImportBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "", "нет":
+		return false, nil
+	case "да":
+		return true, nil
+	}
+
+	result, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("значение '%s' не является логическим", value)
+	}
+
+	return result, nil
+}
+
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+
+	return &value
+}
+
+func isEmptyRecord(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,85 @@
+package importer
+
+import (
+	"errors"
+	"io"
+	"service_admin_contractor/application/cvalidator"
+	"service_admin_contractor/application/dto"
+	"service_admin_contractor/domain/model"
+	"strings"
+)
+
+// employeeImportHeaders сопоставляет заголовки колонок файла полям dto.EmployeeDto
+var employeeImportHeaders = map[string]string{
+	"email":            "email",
+	"email сотрудника": "email",
+	"fullname":         "fullName",
+	"фио":              "fullName",
+	"фио сотрудника":   "fullName",
+	"position":         "position",
+	"должность":        "position",
+	"должность сотрудника": "position",
+	"status":            "status",
+	"статус":            "status",
+	"статус сотрудника": "status",
+}
+
+// ReadEmployeeImportRows читает CSV/XLSX файл сотрудников и проверяет каждую строку
+// по тем же правилам, что и тело запроса POST /contractors/{id}/employee
+func ReadEmployeeImportRows(r io.Reader, format Format) ([]model.EmployeeImportRow, error) {
+	records, err := ReadRecords(r, format)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		return nil, errors.New("файл не содержит строки заголовков")
+	}
+
+	columns := make(map[string]int)
+	for i, header := range records[0] {
+		if field, ok := employeeImportHeaders[strings.ToLower(strings.TrimSpace(header))]; ok {
+			columns[field] = i
+		}
+	}
+
+	if _, ok := columns["email"]; !ok {
+		return nil, errors.New("в файле отсутствует колонка email")
+	}
+
+	result := make([]model.EmployeeImportRow, 0, len(records)-1)
+	for i, record := range records[1:] {
+		if isEmptyRecord(record) {
+			continue
+		}
+
+		value := func(field string) string {
+			if j, ok := columns[field]; ok && j < len(record) {
+				return strings.TrimSpace(record[j])
+			}
+			return ""
+		}
+
+		result = append(result, ReadEmployeeImportRow(i+2, &dto.EmployeeDto{
+			Email:    value("email"),
+			FullName: value("fullName"),
+			Position: value("position"),
+			Status:   strings.ToUpper(value("status")),
+		}))
+	}
+
+	return result, nil
+}
+
+// ReadEmployeeImportRow проверяет сотрудника и преобразует его в строку загрузки с номером rowNum
+func ReadEmployeeImportRow(rowNum int, requestDto *dto.EmployeeDto) model.EmployeeImportRow {
+	row := model.EmployeeImportRow{Row: rowNum}
+
+	if err := cvalidator.Validate.Struct(requestDto); err != nil {
+		row.Errors = convertValidationErrors(err)
+	}
+
+	row.Employee = dto.ConvertEmployeeDtoToEntity(requestDto)
+
+	return row
+}
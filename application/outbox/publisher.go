@@ -0,0 +1,66 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"service_admin_contractor/domain/model"
+)
+
+// Publisher доставляет событие получателям. Возвращенная ошибка приводит к повторной попытке доставки.
+type Publisher interface {
+	Publish(ctx context.Context, event model.OutboxEvent) error
+}
+
+// PublisherFunc позволяет использовать функцию в качестве Publisher
+type PublisherFunc func(ctx context.Context, event model.OutboxEvent) error
+
+func (f PublisherFunc) Publish(ctx context.Context, event model.OutboxEvent) error {
+	return f(ctx, event)
+}
+
+// LogPublisher записывает события в лог. Используется, пока не настроен внешний брокер.
+type LogPublisher struct{}
+
+func (LogPublisher) Publish(_ context.Context, event model.OutboxEvent) error {
+	log.WithFields(log.Fields{
+		"event_id":     event.EventId,
+		"event_type":   event.Type,
+		"version":      event.Version,
+		"aggregate_id": event.AggregateId,
+	}).Info(string(event.Payload))
+
+	return nil
+}
+
+// MultiPublisher передает событие всем Publisher-ам по очереди. Ошибка любого из них приводит
+// к повторной доставке события всем, поэтому Publisher-ы должны быть идемпотентны.
+type MultiPublisher []Publisher
+
+func (m MultiPublisher) Publish(ctx context.Context, event model.OutboxEvent) error {
+	for _, publisher := range m {
+		if err := publisher.Publish(ctx, event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// NewPublisher выбирает из available Publisher-ы с именами names из настройки OUTBOX_PUBLISHER
+func NewPublisher(names []string, available map[string]Publisher) (Publisher, error) {
+	result := make(MultiPublisher, 0, len(names))
+	for _, name := range names {
+		publisher, ok := available[name]
+		if !ok {
+			return nil, fmt.Errorf("неизвестный тип publisher-а outbox '%s'", name)
+		}
+		result = append(result, publisher)
+	}
+
+	if len(result) == 1 {
+		return result[0], nil
+	}
+
+	return result, nil
+}
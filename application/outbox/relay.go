@@ -0,0 +1,145 @@
+package outbox
+
+import (
+	"context"
+	log "github.com/sirupsen/logrus"
+	"service_admin_contractor/domain/model"
+	"service_admin_contractor/domain/repository"
+	"time"
+)
+
+// Relay периодически читает недоставленные события outbox и передает их Publisher-у.
+// Неудачные попытки повторяются с экспоненциальной задержкой, после MaxAttempts событие помечается FAILED.
+// Захваченное событие не выдается другим экземплярам в течение Lease, поэтому Lease должен превышать
+// время доставки порции событий.
+type Relay struct {
+	or        repository.OutboxRepository
+	publisher Publisher
+
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	MaxBackoff   time.Duration
+	Lease        time.Duration
+}
+
+func NewRelay(or repository.OutboxRepository, publisher Publisher) *Relay {
+	return &Relay{
+		or:           or,
+		publisher:    publisher,
+		PollInterval: 5 * time.Second,
+		BatchSize:    100,
+		MaxAttempts:  10,
+		MaxBackoff:   time.Hour,
+		Lease:        5 * time.Minute,
+	}
+}
+
+// Run доставляет события до отмены ctx
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			count, err := r.RelayBatch(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Error("outbox relay: ", err)
+				}
+				break
+			}
+			if count < r.BatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayBatch захватывает порцию событий, доставляет их вне транзакции и записывает результат каждой доставки
+// отдельной короткой транзакцией. Возвращает количество обработанных событий.
+func (r *Relay) RelayBatch(ctx context.Context) (int, error) {
+	events, err := r.or.ClaimOutboxEvents(ctx, r.BatchSize, r.Lease)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, event := range events {
+		publishErr := r.publisher.Publish(ctx, event)
+		if publishErr != nil {
+			log.WithField("event_id", event.EventId).Warn("outbox relay: ", publishErr)
+		}
+
+		if err = r.recordResult(ctx, event, publishErr); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(events), nil
+}
+
+// recordResult фиксирует результат доставки события. Если запись не удалась, событие будет доставлено
+// повторно после окончания захвата. Переход события в FAILED записывается в лог как ошибка.
+func (r *Relay) recordResult(ctx context.Context, event model.OutboxEvent, publishErr error) error {
+	tx, err := r.or.WithTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	var nextAttemptAt *time.Time
+	if publishErr != nil {
+		nextAttemptAt = r.nextAttemptAt(event.Attempts + 1)
+		err = r.or.MarkOutboxEventFailed(ctx, tx, event.Id, publishErr.Error(), nextAttemptAt)
+	} else {
+		err = r.or.MarkOutboxEventDelivered(ctx, tx, event.Id)
+	}
+	if err != nil {
+		r.or.RollbackQuietly(tx, ctx)
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	// Событие FAILED задерживает следующие события контрагента, пока его не вернут в очередь
+	if publishErr != nil && nextAttemptAt == nil {
+		log.WithFields(log.Fields{
+			"event_id":      event.EventId,
+			"contractor_id": event.AggregateId,
+		}).Error("outbox relay: delivery attempts are exhausted, later events of the contractor are held " +
+			"until the event is requeued with the outbox-requeue command")
+	}
+
+	return nil
+}
+
+// nextAttemptAt возвращает время следующей попытки после attempts неудачных попыток
+// или nil, если попытки исчерпаны
+func (r *Relay) nextAttemptAt(attempts int) *time.Time {
+	if attempts >= r.MaxAttempts {
+		return nil
+	}
+
+	next := time.Now().Add(Backoff(r.PollInterval, r.MaxBackoff, attempts))
+	return &next
+}
+
+// Backoff возвращает задержку base * 2^(attempts-1), ограниченную max
+func Backoff(base, max time.Duration, attempts int) time.Duration {
+	delay := base
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= max {
+			return max
+		}
+	}
+
+	return delay
+}
@@ -2,18 +2,22 @@ package respond
 
 import (
 	"encoding/json"
+	"fmt"
 	"github.com/go-playground/validator/v10"
 	"github.com/sirupsen/logrus"
 	"net/http"
 	"service_admin_contractor/application/cerrors"
+	"service_admin_contractor/application/cvalidator"
 	"service_admin_contractor/application/dto"
 	"service_admin_contractor/infrastructure/logging"
+	"strings"
 )
 
 const (
-	contentTypeHeaderKey       = "Content-Type"
-	contentTypeApplicationJson = "application/json"
-	correlationIdHeaderKey     = "Correlation-ID"
+	contentTypeHeaderKey        = "Content-Type"
+	contentTypeApplicationJson  = "application/json"
+	contentDispositionHeaderKey = "Content-Disposition"
+	correlationIdHeaderKey      = "Correlation-ID"
 
 	correlationIdCtxKey = "CorrelationId"
 )
@@ -65,7 +69,9 @@ func WithError(w http.ResponseWriter, r *http.Request, err error) {
 	w.Header().Set(contentTypeHeaderKey, contentTypeApplicationJson)
 
 	var edto *dto.ErrorDto
-	if ae, ok := err.(*cerrors.AppError); ok {
+	if de := cerrors.ErrFromDomainError(err); de != nil {
+		edto = handleAppError(w, r, de)
+	} else if ae, ok := err.(*cerrors.AppError); ok {
 		edto = handleAppError(w, r, ae)
 	} else if ve, ok := err.(validator.ValidationErrors); ok {
 		edto = handleValidationError(w, r, ve)
@@ -94,6 +100,53 @@ func WithDto(w http.ResponseWriter, r *http.Request, statusCode int, dto interfa
 	}
 }
 
+// WithAttachment выставляет заголовки ответа для передачи файла filename с типом contentType.
+// Тело ответа записывается вызывающей стороной.
+func WithAttachment(w http.ResponseWriter, r *http.Request, contentType string, filename string) {
+	cId := r.Context().Value(correlationIdCtxKey)
+	if cId != nil {
+		w.Header().Set(correlationIdHeaderKey, cId.(string))
+	}
+	w.Header().Set(contentTypeHeaderKey, contentType)
+	w.Header().Set(contentDispositionHeaderKey, attachmentDisposition(filename))
+}
+
+// attachmentDisposition формирует Content-Disposition с ASCII-именем файла для старых клиентов
+// и именем в UTF-8 по RFC 5987, чтобы не-ASCII символы имени не искажались
+func attachmentDisposition(filename string) string {
+	fallback := strings.Map(func(r rune) rune {
+		if r < 0x20 || r >= 0x7f || r == '"' || r == '\\' {
+			return '_'
+		}
+		return r
+	}, filename)
+
+	return fmt.Sprintf(`attachment; filename="%s"; filename*=UTF-8''%s`, fallback, encodeExtValue(filename))
+}
+
+// encodeExtValue кодирует значение по RFC 5987, оставляя без изменений только символы attr-char
+func encodeExtValue(value string) string {
+	var sb strings.Builder
+	for _, b := range []byte(value) {
+		if isAttrChar(b) {
+			sb.WriteByte(b)
+		} else {
+			fmt.Fprintf(&sb, "%%%02X", b)
+		}
+	}
+
+	return sb.String()
+}
+
+func isAttrChar(b byte) bool {
+	switch {
+	case 'a' <= b && b <= 'z', 'A' <= b && b <= 'Z', '0' <= b && b <= '9':
+		return true
+	}
+
+	return strings.IndexByte("!#$&+-.^_`|~", b) >= 0
+}
+
 func setCorrelationHeader(w http.ResponseWriter, r *http.Request) {
 	cId := r.Context().Value(correlationIdCtxKey)
 	if cId != nil {
@@ -124,7 +177,7 @@ func handleValidationError(w http.ResponseWriter, r *http.Request, errs validato
 		}
 		s := map[string]interface{}{
 			"problem_param":   f,
-			"problem_message": err.Error(),
+			"problem_message": cvalidator.ErrorMessage(err),
 		}
 
 		data = append(data, s)
@@ -2,11 +2,19 @@ package application
 
 import (
 	"context"
+	"github.com/jackc/pgx/v4/pgxpool"
 	log "github.com/sirupsen/logrus"
 	"net/http"
 	"os"
 	"os/signal"
 	"service_admin_contractor/application/config"
+	"service_admin_contractor/application/contract"
+	"service_admin_contractor/application/outbox"
+	"service_admin_contractor/application/service"
+	"service_admin_contractor/application/stream"
+	"service_admin_contractor/application/webhook"
+	"service_admin_contractor/infrastructure/logging"
+	"service_admin_contractor/infrastructure/persistence/postgres"
 	"strings"
 
 	"github.com/spf13/viper"
@@ -15,6 +23,12 @@ import (
 // Server provides an http.Server.
 type Server struct {
 	*http.Server
+	workers []worker
+}
+
+// worker является фоновым обработчиком, работающим до отмены контекста
+type worker interface {
+	Run(ctx context.Context)
 }
 
 // NewServer creates and configures an APIServer serving all application routes.
@@ -23,10 +37,22 @@ func NewServer() (*Server, error) {
 	if err != nil {
 		return nil, err
 	}
-	api, err := NewApi()
+	logging.ConfigureLogger()
+
+	pc := postgres.DBConn()
+
+	broker := stream.NewBroker(postgres.NewChangeRepository(pc))
+
+	api, err := NewApi(pc, broker)
+	if err != nil {
+		return nil, err
+	}
+
+	workers, err := newWorkers(pc)
 	if err != nil {
 		return nil, err
 	}
+	workers = append(workers, broker)
 
 	var addr string
 	port := viper.GetString(config.Port)
@@ -42,12 +68,63 @@ func NewServer() (*Server, error) {
 		Handler: api,
 	}
 
-	return &Server{&srv}, nil
+	return &Server{&srv, workers}, nil
+}
+
+// newWorkers создает включенные в настройках фоновые обработчики: доставку событий outbox, отправку webhook-ов
+// и проверку сроков договоров
+func newWorkers(pc *pgxpool.Pool) ([]worker, error) {
+	webhookRepo := postgres.NewWebhookRepository(pc)
+	workers := make([]worker, 0)
+
+	if viper.GetBool(config.OutboxEnabled) {
+		publisher, err := outbox.NewPublisher(viper.GetStringSlice(config.OutboxPublisher), map[string]outbox.Publisher{
+			"log":     outbox.LogPublisher{},
+			"webhook": webhook.NewPublisher(webhookRepo),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		relay := outbox.NewRelay(postgres.NewOutboxRepository(pc), publisher)
+		relay.PollInterval = viper.GetDuration(config.OutboxPollInterval)
+		relay.BatchSize = viper.GetInt(config.OutboxBatchSize)
+		relay.MaxAttempts = viper.GetInt(config.OutboxMaxAttempts)
+		workers = append(workers, relay)
+	}
+
+	if viper.GetBool(config.WebhookEnabled) {
+		dispatcher := webhook.NewDispatcher(webhookRepo, viper.GetDuration(config.HttpRequestTimeout))
+		dispatcher.PollInterval = viper.GetDuration(config.WebhookPollInterval)
+		dispatcher.BatchSize = viper.GetInt(config.WebhookBatchSize)
+		dispatcher.MaxAttempts = viper.GetInt(config.WebhookMaxAttempts)
+		workers = append(workers, dispatcher)
+	}
+
+	if viper.GetBool(config.ContractMonitorEnabled) {
+		contractSrvc := service.NewContractService(postgres.NewContractorRepository(pc),
+			postgres.NewContractRepository(pc), postgres.NewAuditRepository(pc), postgres.NewOutboxRepository(pc))
+		monitor := contract.NewExpiryMonitor(contractSrvc)
+		monitor.PollInterval = viper.GetDuration(config.ContractMonitorInterval)
+		monitor.BatchSize = viper.GetInt(config.ContractMonitorBatchSize)
+		monitor.WarningDays = viper.GetInt(config.ContractExpiryWarningDays)
+		monitor.BlockWithoutContracts = viper.GetBool(config.ContractRequired)
+		workers = append(workers, monitor)
+	}
+
+	return workers, nil
 }
 
 // Start производит запуск сервиса на указанном порту
 func (srv *Server) Start() {
 	log.Info("starting server...")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for _, w := range srv.workers {
+		go w.Run(ctx)
+	}
+
 	go func() {
 		if err := srv.ListenAndServe(); err != http.ErrServerClosed {
 			panic(err)
@@ -55,11 +132,12 @@ func (srv *Server) Start() {
 	}()
 	log.Infof("Listening on %s", srv.Addr)
 
-	quit := make(chan os.Signal)
+	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt)
 	sig := <-quit
 	log.Info("Shutting down server... Reason:", sig)
 	// teardown logic...
+	cancel()
 
 	if err := srv.Shutdown(context.Background()); err != nil {
 		panic(err)
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"github.com/jackc/pgx/v4"
+	"service_admin_contractor/domain/model"
+	"service_admin_contractor/domain/repository"
+)
+
+type BankAccountService interface {
+	FindBankAccounts(ctx context.Context, contractorId int64) ([]model.BankAccount, error)
+	GetBankAccount(ctx context.Context, contractorId int64, id int64) (model.BankAccount, error)
+	// CreateBankAccount создает счет; основной счет снимает признак основного с прежнего счета контрагента
+	CreateBankAccount(ctx context.Context, user model.UserInfo, account *model.BankAccount) error
+	UpdateBankAccount(ctx context.Context, user model.UserInfo, account *model.BankAccount) error
+	DeleteBankAccount(ctx context.Context, user model.UserInfo, contractorId int64, id int64) error
+}
+
+type bankAccountService struct {
+	cr repository.ContractorRepository
+	br repository.BankAccountRepository
+	ar repository.AuditRepository
+}
+
+func NewBankAccountService(cr repository.ContractorRepository, br repository.BankAccountRepository,
+	ar repository.AuditRepository) BankAccountService {
+	return &bankAccountService{cr, br, ar}
+}
+
+func (bs *bankAccountService) FindBankAccounts(ctx context.Context, contractorId int64) ([]model.BankAccount, error) {
+	if _, err := bs.cr.GetContractor(ctx, contractorId); err != nil {
+		return nil, err
+	}
+
+	return bs.br.FindBankAccounts(ctx, contractorId)
+}
+
+func (bs *bankAccountService) GetBankAccount(ctx context.Context, contractorId int64,
+	id int64) (model.BankAccount, error) {
+	return bs.br.GetBankAccount(ctx, contractorId, id)
+}
+
+func (bs *bankAccountService) CreateBankAccount(ctx context.Context, user model.UserInfo,
+	account *model.BankAccount) error {
+	if _, err := bs.cr.GetContractor(ctx, account.ContractorId); err != nil {
+		return err
+	}
+
+	tx, err := bs.br.WithTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = bs.resetPrimary(ctx, tx, account); err != nil {
+		bs.br.RollbackQuietly(tx, ctx)
+		return err
+	}
+
+	if err = bs.br.CreateBankAccount(ctx, tx, account); err != nil {
+		bs.br.RollbackQuietly(tx, ctx)
+		return err
+	}
+
+	if err = bs.writeAudit(ctx, tx, user, model.AuditActionCreate, account.Id, account.ContractorId,
+		bankAccountDetails(*account)); err != nil {
+		bs.br.RollbackQuietly(tx, ctx)
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (bs *bankAccountService) UpdateBankAccount(ctx context.Context, user model.UserInfo,
+	account *model.BankAccount) error {
+	tx, err := bs.br.WithTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = bs.resetPrimary(ctx, tx, account); err != nil {
+		bs.br.RollbackQuietly(tx, ctx)
+		return err
+	}
+
+	if err = bs.br.UpdateBankAccount(ctx, tx, account); err != nil {
+		bs.br.RollbackQuietly(tx, ctx)
+		return err
+	}
+
+	if err = bs.writeAudit(ctx, tx, user, model.AuditActionUpdate, account.Id, account.ContractorId,
+		bankAccountDetails(*account)); err != nil {
+		bs.br.RollbackQuietly(tx, ctx)
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (bs *bankAccountService) DeleteBankAccount(ctx context.Context, user model.UserInfo, contractorId int64,
+	id int64) error {
+	tx, err := bs.br.WithTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = bs.br.DeleteBankAccount(ctx, tx, contractorId, id); err != nil {
+		bs.br.RollbackQuietly(tx, ctx)
+		return err
+	}
+
+	if err = bs.writeAudit(ctx, tx, user, model.AuditActionDelete, id, contractorId, nil); err != nil {
+		bs.br.RollbackQuietly(tx, ctx)
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// resetPrimary снимает признак основного с остальных счетов контрагента, если account становится основным.
+// Для нового счета Id равен 0 и признак снимается со всех счетов.
+func (bs *bankAccountService) resetPrimary(ctx context.Context, tx pgx.Tx, account *model.BankAccount) error {
+	if !account.Primary {
+		return nil
+	}
+
+	return bs.br.ResetPrimaryBankAccount(ctx, tx, account.ContractorId, account.Id)
+}
+
+// writeAudit записывает в журнал действий изменение счета id контрагента contractorId
+func (bs *bankAccountService) writeAudit(ctx context.Context, tx pgx.Tx, user model.UserInfo, action string,
+	id int64, contractorId int64, details map[string]interface{}) error {
+	if details == nil {
+		details = map[string]interface{}{}
+	}
+	details["contractorId"] = contractorId
+
+	return bs.ar.CreateAuditEntry(ctx, tx, &model.AuditEntry{
+		Entity:     model.AuditEntityBankAccount,
+		EntityId:   id,
+		Action:     action,
+		ActorLogin: user.Login(),
+		Details:    details,
+	})
+}
+
+// bankAccountDetails возвращает данные счета для журнала действий. Полный номер счета в журнал не попадает.
+func bankAccountDetails(account model.BankAccount) map[string]interface{} {
+	return map[string]interface{}{
+		"iban":     account.MaskedIban(),
+		"bik":      account.Bik,
+		"currency": account.Currency,
+		"primary":  account.Primary,
+	}
+}
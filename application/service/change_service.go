@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"service_admin_contractor/domain/model"
+	"service_admin_contractor/domain/repository"
+)
+
+type ChangeService interface {
+	FindChanges(ctx context.Context, since model.ChangeToken, limit int) (model.ChangeFeed, error)
+	GetLatestChangeToken(ctx context.Context) (model.ChangeToken, error)
+}
+
+type changeService struct {
+	cr repository.ChangeRepository
+}
+
+func NewChangeService(cr repository.ChangeRepository) ChangeService {
+	return &changeService{cr}
+}
+
+// FindChanges возвращает страницу журнала изменений, следующую за токеном since,
+// дополняя вставки и изменения текущим состоянием контрагентов и сотрудников
+func (cs *changeService) FindChanges(ctx context.Context, since model.ChangeToken,
+	limit int) (model.ChangeFeed, error) {
+	changes, err := cs.cr.FindChanges(ctx, since, limit+1)
+	if err != nil {
+		return model.ChangeFeed{}, err
+	}
+
+	feed := model.ChangeFeed{Next: since}
+	if len(changes) > limit {
+		feed.HasMore = true
+		changes = changes[:limit]
+	}
+
+	if err = cs.loadChangedRecords(ctx, changes); err != nil {
+		return model.ChangeFeed{}, err
+	}
+
+	if len(changes) > 0 {
+		feed.Next = changes[len(changes)-1].Token
+	}
+	feed.Changes = changes
+
+	return feed, nil
+}
+
+// GetLatestChangeToken возвращает токен, начиная с которого FindChanges вернет только новые изменения
+func (cs *changeService) GetLatestChangeToken(ctx context.Context) (model.ChangeToken, error) {
+	return cs.cr.FindLatestChangeToken(ctx)
+}
+
+func (cs *changeService) loadChangedRecords(ctx context.Context, changes []model.Change) error {
+	contractorIds := make([]int64, 0)
+	employeeIds := make([]int64, 0)
+	for _, change := range changes {
+		if change.Operation == model.ChangeOperationDeleted {
+			continue
+		}
+
+		switch change.Entity {
+		case model.ChangeEntityContractor:
+			contractorIds = append(contractorIds, change.EntityId)
+		case model.ChangeEntityEmployee:
+			employeeIds = append(employeeIds, change.EntityId)
+		}
+	}
+
+	contractors, err := cs.cr.FindContractorsByIds(ctx, contractorIds)
+	if err != nil {
+		return err
+	}
+
+	employees, err := cs.cr.FindEmployeesByIds(ctx, employeeIds)
+	if err != nil {
+		return err
+	}
+
+	contractorsById := make(map[int64]*model.Contractor, len(contractors))
+	for i := range contractors {
+		contractorsById[contractors[i].Id] = &contractors[i]
+	}
+
+	employeesById := make(map[int64]*model.Employee, len(employees))
+	for i := range employees {
+		employeesById[employees[i].Id] = &employees[i]
+	}
+
+	for i := range changes {
+		if changes[i].Operation == model.ChangeOperationDeleted {
+			continue
+		}
+
+		switch changes[i].Entity {
+		case model.ChangeEntityContractor:
+			changes[i].Contractor = contractorsById[changes[i].EntityId]
+		case model.ChangeEntityEmployee:
+			changes[i].Employee = employeesById[changes[i].EntityId]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"github.com/jackc/pgx/v4"
+	"service_admin_contractor/domain/model"
+	"service_admin_contractor/domain/repository"
+)
+
+type ContactService interface {
+	FindAddresses(ctx context.Context, contractorId int64) ([]model.Address, error)
+	CreateAddress(ctx context.Context, user model.UserInfo, address *model.Address) error
+	UpdateAddress(ctx context.Context, user model.UserInfo, address *model.Address) error
+	DeleteAddress(ctx context.Context, user model.UserInfo, contractorId int64, id int64) error
+
+	FindContacts(ctx context.Context, contractorId int64) ([]model.Contact, error)
+	GetContact(ctx context.Context, contractorId int64, id int64) (model.Contact, error)
+	CreateContact(ctx context.Context, user model.UserInfo, contact *model.Contact) error
+	UpdateContact(ctx context.Context, user model.UserInfo, contact *model.Contact) error
+	DeleteContact(ctx context.Context, user model.UserInfo, contractorId int64, id int64) error
+}
+
+type contactService struct {
+	cr repository.ContractorRepository
+	kr repository.ContactRepository
+	ar repository.AuditRepository
+	or repository.OutboxRepository
+}
+
+func NewContactService(cr repository.ContractorRepository, kr repository.ContactRepository,
+	ar repository.AuditRepository, or repository.OutboxRepository) ContactService {
+	return &contactService{cr, kr, ar, or}
+}
+
+func (ks *contactService) FindAddresses(ctx context.Context, contractorId int64) ([]model.Address, error) {
+	if _, err := ks.cr.GetContractor(ctx, contractorId); err != nil {
+		return nil, err
+	}
+
+	return ks.kr.FindAddresses(ctx, contractorId)
+}
+
+func (ks *contactService) CreateAddress(ctx context.Context, user model.UserInfo, address *model.Address) error {
+	if _, err := ks.cr.GetContractor(ctx, address.ContractorId); err != nil {
+		return err
+	}
+
+	tx, err := ks.kr.WithTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = ks.kr.CreateAddress(ctx, tx, address); err != nil {
+		ks.kr.RollbackQuietly(tx, ctx)
+		return err
+	}
+
+	err = ks.writeChange(ctx, tx, user, model.AuditEntityAddress, address.Id, address.ContractorId,
+		model.AuditActionCreate, model.EventAddressAdded, model.NewAddressEventData(*address))
+	if err != nil {
+		ks.kr.RollbackQuietly(tx, ctx)
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (ks *contactService) UpdateAddress(ctx context.Context, user model.UserInfo, address *model.Address) error {
+	if _, err := ks.cr.GetContractor(ctx, address.ContractorId); err != nil {
+		return err
+	}
+
+	tx, err := ks.kr.WithTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = ks.kr.UpdateAddress(ctx, tx, address); err != nil {
+		ks.kr.RollbackQuietly(tx, ctx)
+		return err
+	}
+
+	err = ks.writeChange(ctx, tx, user, model.AuditEntityAddress, address.Id, address.ContractorId,
+		model.AuditActionUpdate, model.EventAddressUpdated, model.NewAddressEventData(*address))
+	if err != nil {
+		ks.kr.RollbackQuietly(tx, ctx)
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (ks *contactService) DeleteAddress(ctx context.Context, user model.UserInfo, contractorId int64, id int64) error {
+	if _, err := ks.cr.GetContractor(ctx, contractorId); err != nil {
+		return err
+	}
+
+	tx, err := ks.kr.WithTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = ks.kr.DeleteAddress(ctx, tx, contractorId, id); err != nil {
+		ks.kr.RollbackQuietly(tx, ctx)
+		return err
+	}
+
+	err = ks.writeChange(ctx, tx, user, model.AuditEntityAddress, id, contractorId,
+		model.AuditActionDelete, model.EventAddressDeleted,
+		model.ContractorItemRefEventData{Id: id, ContractorId: contractorId})
+	if err != nil {
+		ks.kr.RollbackQuietly(tx, ctx)
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (ks *contactService) FindContacts(ctx context.Context, contractorId int64) ([]model.Contact, error) {
+	if _, err := ks.cr.GetContractor(ctx, contractorId); err != nil {
+		return nil, err
+	}
+
+	return ks.kr.FindContacts(ctx, contractorId)
+}
+
+func (ks *contactService) GetContact(ctx context.Context, contractorId int64, id int64) (model.Contact, error) {
+	return ks.kr.GetContact(ctx, contractorId, id)
+}
+
+// CreateContact создает контактное лицо вместе с его телефонами
+func (ks *contactService) CreateContact(ctx context.Context, user model.UserInfo, contact *model.Contact) error {
+	if _, err := ks.cr.GetContractor(ctx, contact.ContractorId); err != nil {
+		return err
+	}
+
+	tx, err := ks.kr.WithTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = ks.kr.CreateContact(ctx, tx, contact); err != nil {
+		ks.kr.RollbackQuietly(tx, ctx)
+		return err
+	}
+
+	if err = ks.kr.ReplacePhones(ctx, tx, contact.ContractorId, &contact.Id, contact.Phones); err != nil {
+		ks.kr.RollbackQuietly(tx, ctx)
+		return err
+	}
+
+	err = ks.writeChange(ctx, tx, user, model.AuditEntityContact, contact.Id, contact.ContractorId,
+		model.AuditActionCreate, model.EventContactAdded, model.NewContactEventData(*contact))
+	if err != nil {
+		ks.kr.RollbackQuietly(tx, ctx)
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// UpdateContact изменяет контактное лицо и заменяет его телефоны переданными
+func (ks *contactService) UpdateContact(ctx context.Context, user model.UserInfo, contact *model.Contact) error {
+	if _, err := ks.cr.GetContractor(ctx, contact.ContractorId); err != nil {
+		return err
+	}
+
+	tx, err := ks.kr.WithTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = ks.kr.UpdateContact(ctx, tx, contact); err != nil {
+		ks.kr.RollbackQuietly(tx, ctx)
+		return err
+	}
+
+	if err = ks.kr.ReplacePhones(ctx, tx, contact.ContractorId, &contact.Id, contact.Phones); err != nil {
+		ks.kr.RollbackQuietly(tx, ctx)
+		return err
+	}
+
+	err = ks.writeChange(ctx, tx, user, model.AuditEntityContact, contact.Id, contact.ContractorId,
+		model.AuditActionUpdate, model.EventContactUpdated, model.NewContactEventData(*contact))
+	if err != nil {
+		ks.kr.RollbackQuietly(tx, ctx)
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (ks *contactService) DeleteContact(ctx context.Context, user model.UserInfo, contractorId int64, id int64) error {
+	if _, err := ks.cr.GetContractor(ctx, contractorId); err != nil {
+		return err
+	}
+
+	tx, err := ks.kr.WithTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = ks.kr.DeleteContact(ctx, tx, contractorId, id); err != nil {
+		ks.kr.RollbackQuietly(tx, ctx)
+		return err
+	}
+
+	err = ks.writeChange(ctx, tx, user, model.AuditEntityContact, id, contractorId,
+		model.AuditActionDelete, model.EventContactDeleted,
+		model.ContractorItemRefEventData{Id: id, ContractorId: contractorId})
+	if err != nil {
+		ks.kr.RollbackQuietly(tx, ctx)
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// writeChange записывает событие outbox и запись журнала действий об изменении адреса или контактного лица
+func (ks *contactService) writeChange(ctx context.Context, tx pgx.Tx, user model.UserInfo, entity string,
+	entityId int64, contractorId int64, action string, eventType model.EventType, data interface{}) error {
+	return writeItemChange(ctx, ks.ar, ks.or, tx, eventType, contractorId, data, &model.AuditEntry{
+		Entity:     entity,
+		EntityId:   entityId,
+		Action:     action,
+		ActorLogin: user.Login(),
+	})
+}
@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"github.com/jackc/pgx/v4"
+	log "github.com/sirupsen/logrus"
+	"service_admin_contractor/domain/model"
+	"service_admin_contractor/domain/repository"
+	"time"
+)
+
+type ContractService interface {
+	FindContracts(ctx context.Context, contractorId int64) ([]model.Contract, error)
+	GetContract(ctx context.Context, contractorId int64, id int64) (model.Contract, error)
+	CreateContract(ctx context.Context, user model.UserInfo, contract *model.Contract) error
+	UpdateContract(ctx context.Context, user model.UserInfo, contract *model.Contract) error
+	DeleteContract(ctx context.Context, user model.UserInfo, contractorId int64, id int64) error
+
+	// WarnExpiringContracts записывает событие предупреждения для не более limit последних действующих договоров,
+	// заканчивающихся в течение warningDays дней, и возвращает количество обработанных договоров
+	WarnExpiringContracts(ctx context.Context, today time.Time, warningDays int, limit int) (int, error)
+	// BlockContractorsWithoutContract блокирует не более limit контрагентов без действующего договора
+	// и возвращает количество заблокированных. Контрагенты, у которых договоров никогда не было,
+	// блокируются только при withoutContracts.
+	BlockContractorsWithoutContract(ctx context.Context, today time.Time, withoutContracts bool,
+		limit int) (int, error)
+}
+
+type contractService struct {
+	cr repository.ContractorRepository
+	nr repository.ContractRepository
+	ar repository.AuditRepository
+	or repository.OutboxRepository
+}
+
+func NewContractService(cr repository.ContractorRepository, nr repository.ContractRepository,
+	ar repository.AuditRepository, or repository.OutboxRepository) ContractService {
+	return &contractService{cr, nr, ar, or}
+}
+
+func (ns *contractService) FindContracts(ctx context.Context, contractorId int64) ([]model.Contract, error) {
+	if _, err := ns.cr.GetContractor(ctx, contractorId); err != nil {
+		return nil, err
+	}
+
+	return ns.nr.FindContracts(ctx, contractorId)
+}
+
+func (ns *contractService) GetContract(ctx context.Context, contractorId int64, id int64) (model.Contract, error) {
+	return ns.nr.GetContract(ctx, contractorId, id)
+}
+
+func (ns *contractService) CreateContract(ctx context.Context, user model.UserInfo, contract *model.Contract) error {
+	if _, err := ns.cr.GetContractor(ctx, contract.ContractorId); err != nil {
+		return err
+	}
+
+	tx, err := ns.nr.WithTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = ns.nr.CreateContract(ctx, tx, contract); err != nil {
+		ns.nr.RollbackQuietly(tx, ctx)
+		return err
+	}
+
+	err = ns.writeChange(ctx, tx, user, contract.Id, contract.ContractorId, model.AuditActionCreate,
+		model.EventContractAdded, model.NewContractEventData(*contract))
+	if err != nil {
+		ns.nr.RollbackQuietly(tx, ctx)
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (ns *contractService) UpdateContract(ctx context.Context, user model.UserInfo, contract *model.Contract) error {
+	if _, err := ns.cr.GetContractor(ctx, contract.ContractorId); err != nil {
+		return err
+	}
+
+	tx, err := ns.nr.WithTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = ns.nr.UpdateContract(ctx, tx, contract); err != nil {
+		ns.nr.RollbackQuietly(tx, ctx)
+		return err
+	}
+
+	err = ns.writeChange(ctx, tx, user, contract.Id, contract.ContractorId, model.AuditActionUpdate,
+		model.EventContractUpdated, model.NewContractEventData(*contract))
+	if err != nil {
+		ns.nr.RollbackQuietly(tx, ctx)
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (ns *contractService) DeleteContract(ctx context.Context, user model.UserInfo, contractorId int64,
+	id int64) error {
+	if _, err := ns.cr.GetContractor(ctx, contractorId); err != nil {
+		return err
+	}
+
+	tx, err := ns.nr.WithTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = ns.nr.DeleteContract(ctx, tx, contractorId, id); err != nil {
+		ns.nr.RollbackQuietly(tx, ctx)
+		return err
+	}
+
+	err = ns.writeChange(ctx, tx, user, id, contractorId, model.AuditActionDelete,
+		model.EventContractDeleted, model.ContractorItemRefEventData{Id: id, ContractorId: contractorId})
+	if err != nil {
+		ns.nr.RollbackQuietly(tx, ctx)
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// writeChange записывает событие outbox и запись журнала действий об изменении договора
+func (ns *contractService) writeChange(ctx context.Context, tx pgx.Tx, user model.UserInfo, id int64,
+	contractorId int64, action string, eventType model.EventType, data interface{}) error {
+	return writeItemChange(ctx, ns.ar, ns.or, tx, eventType, contractorId, data, &model.AuditEntry{
+		Entity:     model.AuditEntityContract,
+		EntityId:   id,
+		Action:     action,
+		ActorLogin: user.Login(),
+	})
+}
+
+func (ns *contractService) WarnExpiringContracts(ctx context.Context, today time.Time, warningDays int,
+	limit int) (int, error) {
+	tx, err := ns.nr.WithTransaction(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	until := today.AddDate(0, 0, warningDays)
+	contracts, err := ns.nr.LockExpiringContracts(ctx, tx, today, until, limit)
+	if err != nil {
+		ns.nr.RollbackQuietly(tx, ctx)
+		return 0, err
+	}
+
+	for _, contract := range contracts {
+		if err = ns.warnExpiringContract(ctx, tx, contract); err != nil {
+			ns.nr.RollbackQuietly(tx, ctx)
+			return 0, err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		ns.nr.RollbackQuietly(tx, ctx)
+		return 0, err
+	}
+
+	return len(contracts), nil
+}
+
+func (ns *contractService) warnExpiringContract(ctx context.Context, tx pgx.Tx, contract model.Contract) error {
+	log.WithField("contractor_id", contract.ContractorId).
+		Infof("contract %s expires on %s", contract.Number, contract.EndDate.Format("2006-01-02"))
+
+	err := writeOutboxEvent(ctx, ns.or, tx, model.EventContractExpiring, contract.ContractorId,
+		model.ContractExpiringEventData{
+			ContractorId: contract.ContractorId,
+			ContractId:   contract.Id,
+			Number:       contract.Number,
+			EndDate:      contract.EndDate.Format("2006-01-02"),
+		})
+	if err != nil {
+		return err
+	}
+
+	return ns.nr.MarkContractExpiryWarned(ctx, tx, contract.Id)
+}
+
+// BlockContractorsWithoutContract блокирует контрагентов вместе с их потомками так же, как при изменении статуса
+// через UpdateContractor, и записывает блокировку в журнал действий от имени model.AuditActorSystem
+func (ns *contractService) BlockContractorsWithoutContract(ctx context.Context, today time.Time,
+	withoutContracts bool, limit int) (int, error) {
+	tx, err := ns.nr.WithTransaction(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	ids, err := ns.nr.LockContractorsWithoutActiveContract(ctx, tx, today, withoutContracts, limit)
+	if err != nil {
+		ns.nr.RollbackQuietly(tx, ctx)
+		return 0, err
+	}
+
+	blocked := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		// Контрагент уже заблокирован вместе с родителем из этой же порции
+		if blocked[id] {
+			continue
+		}
+
+		descendantIds, err := ns.blockContractor(ctx, tx, id)
+		if err != nil {
+			ns.nr.RollbackQuietly(tx, ctx)
+			return 0, err
+		}
+
+		for _, descendantId := range descendantIds {
+			blocked[descendantId] = true
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		ns.nr.RollbackQuietly(tx, ctx)
+		return 0, err
+	}
+
+	return len(ids), nil
+}
+
+// blockContractor блокирует контрагента id вместе со всеми его потомками и возвращает ИД заблокированных потомков
+func (ns *contractService) blockContractor(ctx context.Context, tx pgx.Tx, id int64) ([]int64, error) {
+	log.WithField("contractor_id", id).Info("blocking contractor without active contract")
+
+	status, blockDate := contractorStatusChange(model.ContractorStatusBlock)
+	if err := ns.cr.UpdateContractorStatus(ctx, tx, id, status, blockDate); err != nil {
+		return nil, err
+	}
+
+	if err := writeStatusEvent(ctx, ns.or, tx, id, status, blockDate); err != nil {
+		return nil, err
+	}
+
+	err := ns.ar.CreateAuditEntry(ctx, tx, &model.AuditEntry{
+		Entity:     model.AuditEntityContractor,
+		EntityId:   id,
+		Action:     model.AuditActionAutoBlock,
+		ActorLogin: model.AuditActorSystem,
+		Details:    map[string]interface{}{"reason": "no_active_contract"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	changed, err := propagateContractorStatus(ctx, ns.cr, ns.or, tx, id, status)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, descendantId := range changed {
+		err = ns.ar.CreateAuditEntry(ctx, tx, &model.AuditEntry{
+			Entity:     model.AuditEntityContractor,
+			EntityId:   descendantId,
+			Action:     model.AuditActionAutoBlock,
+			ActorLogin: model.AuditActorSystem,
+			Details:    map[string]interface{}{"reason": "no_active_contract", "propagatedFrom": id},
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return changed, nil
+}
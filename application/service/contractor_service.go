@@ -14,24 +14,43 @@ import (
 
 type ContractorService interface {
 	FindContractors(ctx context.Context, params model.ContractorSearchParameters) ([]model.Contractor, int64, error)
+	FindContractorFacets(ctx context.Context,
+		params model.ContractorSearchParameters) (map[model.ContractorFacet][]model.FacetValue, error)
+	ExportContractors(ctx context.Context, params model.ContractorSearchParameters,
+		fn func(row model.ContractorEmployeeRow) error) error
+	ExportContractorEmployees(ctx context.Context, params model.ContractorSearchParameters,
+		fn func(row model.ContractorEmployeeRow) error) error
 	GetContractor(ctx context.Context, id int64) (model.Contractor, error)
 	CreateContractor(ctx context.Context, contractor *model.Contractor) error
-	UpdateContractor(ctx context.Context, id int64, contractor *model.Contractor) error
-	DeleteContractor(id int64) error
+	UpdateContractor(ctx context.Context, id int64, contractor *model.Contractor, propagate bool) error
+	DeleteContractor(ctx context.Context, id int64) error
+	GetContractorTree(ctx context.Context, id int64) ([]model.ContractorTreeNode, error)
+	BulkContractorAction(ctx context.Context, user model.UserInfo,
+		request model.ContractorBulkRequest) (*model.ContractorBulkResult, error)
+	ImportContractors(ctx context.Context, rows []model.ContractorImportRow,
+		dryRun bool) (*model.ContractorImportReport, error)
 
 	CreateContractorEmployee(ctx context.Context, contractorId int64, employee *model.Employee) error
 	UpdateContractorEmployee(ctx context.Context, id int64, employee *model.Employee) error
-	DeleteContractorEmployee(id int64) error
+	UpsertContractorEmployees(ctx context.Context, contractorId int64,
+		rows []model.EmployeeImportRow) (*model.EmployeeImportReport, error)
+	DeleteContractorEmployee(ctx context.Context, id int64) error
 
 	GeneratePassword() (string, error)
 }
 
 type contractorService struct {
-	cr repository.ContractorRepository
+	cr         repository.ContractorRepository
+	ar         repository.AuditRepository
+	or         repository.OutboxRepository
+	kr         repository.ContactRepository
+	emailScope model.EmployeeEmailScope
 }
 
-func NewContractorService(cr repository.ContractorRepository) ContractorService {
-	return &contractorService{cr}
+func NewContractorService(cr repository.ContractorRepository, ar repository.AuditRepository,
+	or repository.OutboxRepository, kr repository.ContactRepository,
+	emailScope model.EmployeeEmailScope) ContractorService {
+	return &contractorService{cr, ar, or, kr, emailScope}
 }
 
 func (cs *contractorService) FindContractors(ctx context.Context,
@@ -44,38 +63,66 @@ func (cs *contractorService) FindContractors(ctx context.Context,
 	return result, total, nil
 }
 
+func (cs *contractorService) FindContractorFacets(ctx context.Context,
+	params model.ContractorSearchParameters) (map[model.ContractorFacet][]model.FacetValue, error) {
+	if len(params.Facets) == 0 {
+		return nil, nil
+	}
+
+	return cs.cr.FindContractorFacets(ctx, params)
+}
+
+// ExportContractors передает в fn найденных контрагентов по одному, без сотрудников
+func (cs *contractorService) ExportContractors(ctx context.Context, params model.ContractorSearchParameters,
+	fn func(row model.ContractorEmployeeRow) error) error {
+	return cs.cr.StreamContractors(ctx, params, func(contractor model.Contractor) error {
+		return fn(model.ContractorEmployeeRow{Contractor: contractor})
+	})
+}
+
+// ExportContractorEmployees передает в fn найденных контрагентов, развернутых по сотрудникам
+func (cs *contractorService) ExportContractorEmployees(ctx context.Context, params model.ContractorSearchParameters,
+	fn func(row model.ContractorEmployeeRow) error) error {
+	return cs.cr.StreamContractorEmployees(ctx, params, fn)
+}
+
 func (cs *contractorService) GetContractor(ctx context.Context, id int64) (model.Contractor, error) {
 	res, err := cs.cr.GetContractor(ctx, id)
 	if err != nil {
 		return model.Contractor{}, cerrors.ErrCouldNotGetContractorById(err, id)
 	}
 
+	contacts, err := cs.kr.FindContractorContacts(ctx, id)
+	if err != nil {
+		return model.Contractor{}, cerrors.ErrCouldNotGetContractorById(err, id)
+	}
+	res.Contacts = &contacts
+
 	return res, nil
 }
 
 func (cs *contractorService) CreateContractor(ctx context.Context, contractor *model.Contractor) error {
 	if len(contractor.AgentPassword) < 12 {
-		return errors.New(fmt.Sprintf("не указан пароль или не соответсвует длина пароля"))
+		return model.NewValidationError("agentPassword", "не указан пароль или не соответсвует длина пароля")
 	}
 
-	existingContractors, _, err := cs.FindContractors(ctx, model.ContractorSearchParameters{
-		Pagination: *model.NewMaxPagination(),
-		Email:      &contractor.Email})
+	tx, err := cs.cr.WithTransaction(ctx)
 	if err != nil {
-		return cerrors.ErrCouldNotCreateContractor(err, " - поиск по базе возвратил ошибку")
+		return err
 	}
 
-	if len(existingContractors) > 0 {
-		errText := fmt.Sprintf("В базе уже есть email %s", contractor.Email)
-		return cerrors.ErrCouldNotCreateContractor(errors.New(errText), errText)
+	if err = cs.checkContractorParent(ctx, tx, 0, contractor.ParentId); err != nil {
+		cs.cr.RollbackQuietly(tx, ctx)
+		return err
 	}
 
-	tx, err := cs.cr.WithTransaction(ctx)
-	if err != nil {
+	if err = cs.checkContractorEmail(ctx, tx, contractor.Email); err != nil {
+		cs.cr.RollbackQuietly(tx, ctx)
 		return err
 	}
 
 	// Create Contractor
+	contractor.Status = model.ContractorStatusActive
 	if err = cs.cr.CreateContractor(ctx, tx, contractor); err != nil {
 		cs.cr.RollbackQuietly(tx, ctx)
 		return cerrors.ErrCouldNotCreateContractor(err, " - основные данные не записались в базу")
@@ -87,6 +134,17 @@ func (cs *contractorService) CreateContractor(ctx context.Context, contractor *m
 		return cerrors.ErrCouldNotCreateContractor(err, " - учетные данные не записались в базу")
 	}
 
+	if err = cs.createContractorContacts(ctx, tx, contractor); err != nil {
+		cs.cr.RollbackQuietly(tx, ctx)
+		return cerrors.ErrCouldNotCreateContractor(err, " - контактные данные не записались в базу")
+	}
+
+	err = cs.writeEvent(ctx, tx, model.EventContractorCreated, contractor.Id, model.NewContractorEventData(*contractor))
+	if err != nil {
+		cs.cr.RollbackQuietly(tx, ctx)
+		return cerrors.ErrCouldNotCreateContractor(err, " - событие не записалось в базу")
+	}
+
 	err = tx.Commit(ctx)
 	if err != nil {
 		cs.cr.RollbackQuietly(tx, ctx)
@@ -96,6 +154,72 @@ func (cs *contractorService) CreateContractor(ctx context.Context, contractor *m
 	return nil
 }
 
+// createContractorContacts записывает адреса, телефоны и контактные лица, переданные при создании контрагента
+func (cs *contractorService) createContractorContacts(ctx context.Context, tx pgx.Tx,
+	contractor *model.Contractor) error {
+	if contractor.Contacts == nil {
+		return nil
+	}
+
+	for i := range contractor.Contacts.Addresses {
+		address := &contractor.Contacts.Addresses[i]
+		address.ContractorId = contractor.Id
+		if err := cs.kr.CreateAddress(ctx, tx, address); err != nil {
+			return err
+		}
+	}
+
+	if err := cs.kr.ReplacePhones(ctx, tx, contractor.Id, nil, contractor.Contacts.Phones); err != nil {
+		return err
+	}
+
+	for i := range contractor.Contacts.Contacts {
+		contact := &contractor.Contacts.Contacts[i]
+		contact.ContractorId = contractor.Id
+		if err := cs.kr.CreateContact(ctx, tx, contact); err != nil {
+			return err
+		}
+		if err := cs.kr.ReplacePhones(ctx, tx, contractor.Id, &contact.Id, contact.Phones); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeEvent записывает событие контрагента aggregateId в outbox в транзакции изменения
+func (cs *contractorService) writeEvent(ctx context.Context, tx pgx.Tx, eventType model.EventType,
+	aggregateId int64, data interface{}) error {
+	return writeOutboxEvent(ctx, cs.or, tx, eventType, aggregateId, data)
+}
+
+func writeOutboxEvent(ctx context.Context, or repository.OutboxRepository, tx pgx.Tx, eventType model.EventType,
+	aggregateId int64, data interface{}) error {
+	event, err := model.NewOutboxEvent(eventType, aggregateId, data)
+	if err != nil {
+		return err
+	}
+
+	return or.CreateOutboxEvent(ctx, tx, event)
+}
+
+// writeItemChange записывает событие outbox об изменении вложенной сущности контрагента contractorId
+// (адреса, контактного лица, договора) и запись entry журнала действий. Событие относится к контрагенту,
+// поэтому доставляется по порядку вместе с его событиями.
+func writeItemChange(ctx context.Context, ar repository.AuditRepository, or repository.OutboxRepository, tx pgx.Tx,
+	eventType model.EventType, contractorId int64, data interface{}, entry *model.AuditEntry) error {
+	if err := writeOutboxEvent(ctx, or, tx, eventType, contractorId, data); err != nil {
+		return err
+	}
+
+	if entry.Details == nil {
+		entry.Details = map[string]interface{}{}
+	}
+	entry.Details["contractorId"] = contractorId
+
+	return ar.CreateAuditEntry(ctx, tx, entry)
+}
+
 func (cs *contractorService) createCredentials(ctx context.Context, tx pgx.Tx, contractor *model.Contractor) error {
 	var err error
 	credentials := model.Credentials{
@@ -114,45 +238,64 @@ func (cs *contractorService) createCredentials(ctx context.Context, tx pgx.Tx, c
 	return err
 }
 
-func (cs *contractorService) UpdateContractor(ctx context.Context, id int64, contractor *model.Contractor) error {
-	existingContractors, _, err := cs.FindContractors(ctx, model.ContractorSearchParameters{
-		Pagination: *model.NewMaxPagination(),
-		Email:      &contractor.Email})
+// UpdateContractor изменяет контрагента. Блокировка всегда распространяется на всех его потомков,
+// разблокировка - только при propagate.
+func (cs *contractorService) UpdateContractor(ctx context.Context, id int64, contractor *model.Contractor,
+	propagate bool) error {
+	previous, err := cs.cr.GetContractor(ctx, id)
 	if err != nil {
-		return cerrors.ErrCouldNotUpdateContractor(err, " - поиск по базе возвратил ошибку")
-	}
-
-	for _, c := range existingContractors {
-		if c.Id != id {
-			errText := fmt.Sprintf("В базе уже есть email %s", contractor.Email)
-			return cerrors.ErrCouldNotUpdateContractor(errors.New(errText), errText)
-		}
+		return err
 	}
 
 	tx, err := cs.cr.WithTransaction(ctx)
 	if err != nil {
-		cs.cr.RollbackQuietly(tx, ctx)
 		return cerrors.ErrCouldNotUpdateContractor(err, " - нет открылся транзакция")
 	}
 
-	if contractor.Status == model.ContractorStatusBlock {
-		blockDate := time.Now().UTC()
-		contractor.BlockDate = &blockDate
-	} else {
-		contractor.BlockDate = nil
-		contractor.Status = model.ContractorStatusActive
+	if err = cs.checkContractorParent(ctx, tx, id, contractor.ParentId); err != nil {
+		cs.cr.RollbackQuietly(tx, ctx)
+		return err
 	}
 
+	if model.NormalizeEmail(contractor.Email) != model.NormalizeEmail(previous.Email) {
+		if err = cs.checkContractorEmail(ctx, tx, contractor.Email); err != nil {
+			cs.cr.RollbackQuietly(tx, ctx)
+			return err
+		}
+	}
+
+	contractor.Status, contractor.BlockDate = contractorStatusChange(contractor.Status)
+
 	if err = cs.cr.UpdateContractorData(ctx, tx, id, contractor); err != nil {
 		cs.cr.RollbackQuietly(tx, ctx)
 		return cerrors.ErrCouldNotUpdateContractor(err, " - основные данные не обновились")
 	}
 
+	if propagate || contractor.Status == model.ContractorStatusBlock {
+		if _, err = propagateContractorStatus(ctx, cs.cr, cs.or, tx, id, contractor.Status); err != nil {
+			cs.cr.RollbackQuietly(tx, ctx)
+			return cerrors.ErrCouldNotUpdateContractor(err, " - статус дочерних контрагентов не обновился")
+		}
+	}
+
 	if err = cs.updateContractorCredentials(ctx, tx, id, contractor); err != nil {
 		cs.cr.RollbackQuietly(tx, ctx)
 		return cerrors.ErrCouldNotUpdateContractor(err, " - данные по паролю не обновились")
 	}
 
+	// Телефоны контрагента заменяются, только если переданы; адреса и контактные лица изменяются отдельно
+	if contractor.Contacts != nil && contractor.Contacts.Phones != nil {
+		if err = cs.kr.ReplacePhones(ctx, tx, id, nil, contractor.Contacts.Phones); err != nil {
+			cs.cr.RollbackQuietly(tx, ctx)
+			return cerrors.ErrCouldNotUpdateContractor(err, " - телефоны не обновились")
+		}
+	}
+
+	if err = cs.writeContractorUpdateEvents(ctx, tx, id, previous.Status, contractor); err != nil {
+		cs.cr.RollbackQuietly(tx, ctx)
+		return cerrors.ErrCouldNotUpdateContractor(err, " - события не записались в базу")
+	}
+
 	err = tx.Commit(ctx)
 	if err != nil {
 		cs.cr.RollbackQuietly(tx, ctx)
@@ -162,6 +305,127 @@ func (cs *contractorService) UpdateContractor(ctx context.Context, id int64, con
 	return nil
 }
 
+// writeContractorUpdateEvents записывает событие изменения контрагента и, если статус изменился,
+// событие блокировки или разблокировки
+func (cs *contractorService) writeContractorUpdateEvents(ctx context.Context, tx pgx.Tx, id int64,
+	previousStatus model.ContractorStatus, contractor *model.Contractor) error {
+	data := model.NewContractorEventData(*contractor)
+	data.Id = id
+	if err := cs.writeEvent(ctx, tx, model.EventContractorUpdated, id, data); err != nil {
+		return err
+	}
+
+	if contractor.Status == previousStatus {
+		return nil
+	}
+
+	return writeStatusEvent(ctx, cs.or, tx, id, contractor.Status, contractor.BlockDate)
+}
+
+// writeStatusEvent записывает событие блокировки или разблокировки контрагента
+func writeStatusEvent(ctx context.Context, or repository.OutboxRepository, tx pgx.Tx, id int64,
+	status model.ContractorStatus, blockDate *time.Time) error {
+	eventType := model.EventContractorUnblocked
+	if status == model.ContractorStatusBlock {
+		eventType = model.EventContractorBlocked
+	}
+
+	return writeOutboxEvent(ctx, or, tx, eventType, id, model.ContractorStatusEventData{
+		Id:        id,
+		Status:    status,
+		BlockDate: blockDate,
+	})
+}
+
+// checkContractorParent проверяет, что родитель parentId действует и не является самим контрагентом id
+// или его потомком, в том числе через удаленных контрагентов. Для нового контрагента id равен 0.
+// Изменения иерархии сериализуются до конца транзакции.
+func (cs *contractorService) checkContractorParent(ctx context.Context, tx pgx.Tx, id int64, parentId *int64) error {
+	if parentId == nil {
+		return nil
+	}
+
+	if *parentId == id {
+		return model.NewValidationError("parentId", "контрагент не может быть родителем самого себя")
+	}
+
+	if err := cs.cr.LockContractorHierarchy(ctx, tx); err != nil {
+		return err
+	}
+
+	if err := cs.cr.ShareLockContractor(ctx, tx, *parentId); err != nil {
+		var notFound *model.NotFoundError
+		if errors.As(err, &notFound) {
+			return model.NewValidationError("parentId",
+				fmt.Sprintf("родительский контрагент с ИД %d не найден", *parentId))
+		}
+		return err
+	}
+
+	if id == 0 {
+		return nil
+	}
+
+	descendantIds, err := cs.cr.FindContractorDescendantIds(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	for _, descendantId := range descendantIds {
+		if descendantId == *parentId {
+			return model.NewValidationError("parentId", fmt.Sprintf(
+				"контрагент с ИД %d является дочерним для контрагента с ИД %d, иерархия не может содержать цикл",
+				*parentId, id))
+		}
+	}
+
+	return nil
+}
+
+// propagateContractorStatus переводит в статус status всех потомков контрагента id, у которых он отличается,
+// и возвращает ИД измененных потомков
+func propagateContractorStatus(ctx context.Context, cr repository.ContractorRepository,
+	or repository.OutboxRepository, tx pgx.Tx, id int64, status model.ContractorStatus) ([]int64, error) {
+	descendants, err := cr.FindContractorDescendants(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := make([]int64, 0, len(descendants))
+	for _, descendant := range descendants {
+		if descendant.Status == status {
+			continue
+		}
+
+		newStatus, blockDate := contractorStatusChange(status)
+		if err = cr.UpdateContractorStatus(ctx, tx, descendant.Id, newStatus, blockDate); err != nil {
+			return nil, err
+		}
+
+		if err = writeStatusEvent(ctx, or, tx, descendant.Id, newStatus, blockDate); err != nil {
+			return nil, err
+		}
+		changed = append(changed, descendant.Id)
+	}
+
+	return changed, nil
+}
+
+func (cs *contractorService) GetContractorTree(ctx context.Context, id int64) ([]model.ContractorTreeNode, error) {
+	return cs.cr.FindContractorTree(ctx, id)
+}
+
+// contractorStatusChange возвращает итоговый статус и дату блокировки контрагента при смене статуса.
+// Любой статус, кроме блокировки, считается активным.
+func contractorStatusChange(status model.ContractorStatus) (model.ContractorStatus, *time.Time) {
+	if status == model.ContractorStatusBlock {
+		blockDate := time.Now().UTC()
+		return model.ContractorStatusBlock, &blockDate
+	}
+
+	return model.ContractorStatusActive, nil
+}
+
 func (cs *contractorService) updateContractorCredentials(ctx context.Context, tx pgx.Tx, id int64,
 	contractor *model.Contractor) error {
 	var err error
@@ -183,17 +447,300 @@ func (cs *contractorService) updateContractorCredentials(ctx context.Context, tx
 	return err
 }
 
-func (cs *contractorService) DeleteContractor(id int64) error {
-	return cs.cr.DeleteContractor(id)
+func (cs *contractorService) DeleteContractor(ctx context.Context, id int64) error {
+	tx, err := cs.cr.WithTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = cs.cr.SetContractorDeleted(ctx, tx, id, true); err != nil {
+		cs.cr.RollbackQuietly(tx, ctx)
+		return err
+	}
+
+	err = cs.writeEvent(ctx, tx, model.EventContractorDeleted, id, model.ContractorRefEventData{Id: id})
+	if err != nil {
+		cs.cr.RollbackQuietly(tx, ctx)
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		cs.cr.RollbackQuietly(tx, ctx)
+		return err
+	}
+
+	return nil
+}
+
+// BulkContractorAction выполняет действие над контрагентами из списка ИД или найденными по фильтру
+// и записывает в журнал действий по одной записи на каждого измененного контрагента
+func (cs *contractorService) BulkContractorAction(ctx context.Context, user model.UserInfo,
+	request model.ContractorBulkRequest) (*model.ContractorBulkResult, error) {
+	ids := request.Ids
+	if request.Filter != nil {
+		params := *request.Filter
+		params.Deleted = request.Action == model.ContractorBulkRestore
+
+		// Лишний ИД показывает, что фильтр отбирает больше допустимого
+		var err error
+		if ids, err = cs.cr.FindContractorIds(ctx, params, model.ContractorBulkMaxItems+1); err != nil {
+			return nil, err
+		}
+		if len(ids) > model.ContractorBulkMaxItems {
+			return nil, model.NewValidationError("filter", fmt.Sprintf(
+				"фильтр отбирает более %d контрагентов, уточните условия", model.ContractorBulkMaxItems))
+		}
+	}
+
+	result := &model.ContractorBulkResult{
+		Action: request.Action,
+		Atomic: request.Atomic,
+		Items:  make([]model.ContractorBulkItemResult, 0, len(ids)),
+	}
+
+	if request.Atomic {
+		tx, err := cs.cr.WithTransaction(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, id := range ids {
+			if err = cs.applyBulkAction(ctx, tx, user, request, id); err != nil {
+				cs.cr.RollbackQuietly(tx, ctx)
+				return nil, err
+			}
+			result.Items = append(result.Items, model.ContractorBulkItemResult{Id: id})
+		}
+
+		if err = tx.Commit(ctx); err != nil {
+			cs.cr.RollbackQuietly(tx, ctx)
+			return nil, err
+		}
+
+		result.Succeeded = len(ids)
+		return result, nil
+	}
+
+	for _, id := range ids {
+		err := cs.applyBulkActionInTransaction(ctx, user, request, id)
+		if err != nil {
+			result.Failed++
+		} else {
+			result.Succeeded++
+		}
+		result.Items = append(result.Items, model.ContractorBulkItemResult{Id: id, Error: err})
+	}
+
+	return result, nil
+}
+
+func (cs *contractorService) applyBulkActionInTransaction(ctx context.Context, user model.UserInfo,
+	request model.ContractorBulkRequest, id int64) error {
+	tx, err := cs.cr.WithTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = cs.applyBulkAction(ctx, tx, user, request, id); err != nil {
+		cs.cr.RollbackQuietly(tx, ctx)
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		cs.cr.RollbackQuietly(tx, ctx)
+		return err
+	}
+
+	return nil
+}
+
+// applyBulkAction выполняет действие запроса над контрагентом id. Блокировка, а при request.Propagate
+// и разблокировка, распространяются на потомков с отдельной записью в журнале действий для каждого.
+func (cs *contractorService) applyBulkAction(ctx context.Context, tx pgx.Tx, user model.UserInfo,
+	request model.ContractorBulkRequest, id int64) error {
+	action := request.Action
+	var err error
+	var eventType model.EventType
+	var eventData interface{} = model.ContractorRefEventData{Id: id}
+	switch action {
+	case model.ContractorBulkBlock, model.ContractorBulkUnblock:
+		target := model.ContractorStatusActive
+		eventType = model.EventContractorUnblocked
+		if action == model.ContractorBulkBlock {
+			target, eventType = model.ContractorStatusBlock, model.EventContractorBlocked
+		}
+		status, blockDate := contractorStatusChange(target)
+		err = cs.cr.UpdateContractorStatus(ctx, tx, id, status, blockDate)
+		eventData = model.ContractorStatusEventData{Id: id, Status: status, BlockDate: blockDate}
+	case model.ContractorBulkDelete:
+		eventType = model.EventContractorDeleted
+		err = cs.cr.SetContractorDeleted(ctx, tx, id, true)
+	case model.ContractorBulkRestore:
+		eventType = model.EventContractorRestored
+		err = cs.checkContractorRestore(ctx, tx, id)
+		if err == nil {
+			err = cs.cr.SetContractorDeleted(ctx, tx, id, false)
+		}
+	default:
+		err = model.NewValidationError("action", fmt.Sprintf("неизвестное действие '%s'", action))
+	}
+	if err != nil {
+		return err
+	}
+
+	if err = cs.writeEvent(ctx, tx, eventType, id, eventData); err != nil {
+		return err
+	}
+
+	err = cs.ar.CreateAuditEntry(ctx, tx, &model.AuditEntry{
+		Entity:     model.AuditEntityContractor,
+		EntityId:   id,
+		Action:     string(action),
+		ActorLogin: user.Login(),
+		Details:    map[string]interface{}{"bulk": true},
+	})
+	if err != nil {
+		return err
+	}
+
+	statusData, ok := eventData.(model.ContractorStatusEventData)
+	if !ok || (!request.Propagate && statusData.Status != model.ContractorStatusBlock) {
+		return nil
+	}
+
+	changed, err := propagateContractorStatus(ctx, cs.cr, cs.or, tx, id, statusData.Status)
+	if err != nil {
+		return err
+	}
+
+	for _, descendantId := range changed {
+		err = cs.ar.CreateAuditEntry(ctx, tx, &model.AuditEntry{
+			Entity:     model.AuditEntityContractor,
+			EntityId:   descendantId,
+			Action:     string(action),
+			ActorLogin: user.Login(),
+			Details:    map[string]interface{}{"bulk": true, "propagatedFrom": id},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ImportContractors проверяет строки импорта на дубликаты email-ов в файле и в базе.
+// Если ошибок нет и dryRun не задан, создает всех контрагентов одной транзакцией со сгенерированными паролями.
+func (cs *contractorService) ImportContractors(ctx context.Context, rows []model.ContractorImportRow,
+	dryRun bool) (*model.ContractorImportReport, error) {
+	emails := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if row.Contractor != nil {
+			emails = append(emails, row.Contractor.Email)
+		}
+	}
+
+	existingEmails, err := cs.cr.FindExistingEmails(ctx, emails)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := make(map[string]bool, len(existingEmails))
+	for _, email := range existingEmails {
+		existing[email] = true
+	}
+
+	report := &model.ContractorImportReport{DryRun: dryRun, Rows: rows}
+	firstRows := make(map[string]int, len(rows))
+	for i := range report.Rows {
+		row := &report.Rows[i]
+		if row.Contractor != nil {
+			email := model.NormalizeEmail(row.Contractor.Email)
+			if existing[email] {
+				row.Errors = append(row.Errors, *model.NewValidationError("email",
+					fmt.Sprintf("В базе уже есть email %s", row.Contractor.Email)))
+			}
+			if firstRow, ok := firstRows[email]; ok {
+				row.Errors = append(row.Errors, *model.NewValidationError("email",
+					fmt.Sprintf("email %s повторяет строку %d", row.Contractor.Email, firstRow)))
+			} else {
+				firstRows[email] = row.Row
+			}
+		}
+
+		if len(row.Errors) > 0 {
+			report.Failed++
+		}
+	}
+
+	if dryRun || report.Failed > 0 {
+		return report, nil
+	}
+
+	tx, err := cs.cr.WithTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range report.Rows {
+		contractor := report.Rows[i].Contractor
+		contractor.Status = model.ContractorStatusActive
+		if contractor.AgentPassword, err = cs.GeneratePassword(); err != nil {
+			cs.cr.RollbackQuietly(tx, ctx)
+			return nil, err
+		}
+
+		if err = cs.checkContractorEmail(ctx, tx, contractor.Email); err != nil {
+			cs.cr.RollbackQuietly(tx, ctx)
+			return nil, err
+		}
+
+		if err = cs.cr.CreateContractor(ctx, tx, contractor); err != nil {
+			cs.cr.RollbackQuietly(tx, ctx)
+			return nil, cerrors.ErrCouldNotCreateContractor(err,
+				fmt.Sprintf(" - строка %d не записалась в базу", report.Rows[i].Row))
+		}
+
+		if err = cs.createCredentials(ctx, tx, contractor); err != nil {
+			cs.cr.RollbackQuietly(tx, ctx)
+			return nil, cerrors.ErrCouldNotCreateContractor(err,
+				fmt.Sprintf(" - учетные данные строки %d не записались в базу", report.Rows[i].Row))
+		}
+
+		err = cs.writeEvent(ctx, tx, model.EventContractorCreated, contractor.Id,
+			model.NewContractorEventData(*contractor))
+		if err != nil {
+			cs.cr.RollbackQuietly(tx, ctx)
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		cs.cr.RollbackQuietly(tx, ctx)
+		return nil, cerrors.ErrCouldNotCreateContractor(err, " - импорт не зафиксирован в базе")
+	}
+
+	report.Created = len(report.Rows)
+
+	return report, nil
 }
 
 func (cs *contractorService) CreateContractorEmployee(ctx context.Context, contractorId int64,
 	employee *model.Employee) error {
+	if _, err := cs.cr.GetContractor(ctx, contractorId); err != nil {
+		return err
+	}
+
 	tx, err := cs.cr.WithTransaction(ctx)
 	if err != nil {
 		return err
 	}
 
+	if err = cs.checkEmployeeEmail(ctx, tx, 0, employee.Email); err != nil {
+		cs.cr.RollbackQuietly(tx, ctx)
+		return err
+	}
+
 	// Create Contractor employee
 	err = cs.cr.CreateContractorEmployee(ctx, tx, contractorId, employee)
 	if err != nil {
@@ -201,6 +748,13 @@ func (cs *contractorService) CreateContractorEmployee(ctx context.Context, contr
 		return err
 	}
 
+	employee.ContractorId = contractorId
+	err = cs.writeEvent(ctx, tx, model.EventEmployeeAdded, contractorId, model.NewEmployeeEventData(*employee))
+	if err != nil {
+		cs.cr.RollbackQuietly(tx, ctx)
+		return err
+	}
+
 	err = tx.Commit(ctx)
 	if err != nil {
 		cs.cr.RollbackQuietly(tx, ctx)
@@ -213,7 +767,6 @@ func (cs *contractorService) CreateContractorEmployee(ctx context.Context, contr
 func (cs *contractorService) UpdateContractorEmployee(ctx context.Context, id int64, employee *model.Employee) error {
 	tx, err := cs.cr.WithTransaction(ctx)
 	if err != nil {
-		cs.cr.RollbackQuietly(tx, ctx)
 		return err
 	}
 
@@ -222,12 +775,25 @@ func (cs *contractorService) UpdateContractorEmployee(ctx context.Context, id in
 		employee.BlockDate = &blockDate
 	}
 
+	if err = cs.checkEmployeeEmail(ctx, tx, id, employee.Email); err != nil {
+		cs.cr.RollbackQuietly(tx, ctx)
+		return err
+	}
+
 	err = cs.cr.UpdateContractorEmployeeData(ctx, tx, id, employee)
 	if err != nil {
 		cs.cr.RollbackQuietly(tx, ctx)
 		return err
 	}
 
+	employee.Id = id
+	err = cs.writeEvent(ctx, tx, model.EventEmployeeUpdated, employee.ContractorId,
+		model.NewEmployeeEventData(*employee))
+	if err != nil {
+		cs.cr.RollbackQuietly(tx, ctx)
+		return err
+	}
+
 	err = tx.Commit(ctx)
 	if err != nil {
 		cs.cr.RollbackQuietly(tx, ctx)
@@ -237,8 +803,200 @@ func (cs *contractorService) UpdateContractorEmployee(ctx context.Context, id in
 	return nil
 }
 
-func (cs *contractorService) DeleteContractorEmployee(id int64) error {
-	return cs.cr.DeleteContractorEmployee(id)
+// UpsertContractorEmployees создает или обновляет (по email-у в рамках контрагента) сотрудников
+// из строк без ошибок одной транзакцией. Строки с ошибками и повторяющиеся email-ы пропускаются.
+func (cs *contractorService) UpsertContractorEmployees(ctx context.Context, contractorId int64,
+	rows []model.EmployeeImportRow) (*model.EmployeeImportReport, error) {
+	if _, err := cs.cr.GetContractor(ctx, contractorId); err != nil {
+		return nil, err
+	}
+
+	tx, err := cs.cr.WithTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := cs.cr.FindContractorEmployeeIds(ctx, tx, contractorId)
+	if err != nil {
+		cs.cr.RollbackQuietly(tx, ctx)
+		return nil, err
+	}
+
+	report := &model.EmployeeImportReport{Rows: rows}
+	firstRows := make(map[string]int, len(rows))
+	for i := range report.Rows {
+		row := &report.Rows[i]
+		if row.Employee != nil && len(row.Errors) == 0 {
+			email := model.NormalizeEmail(row.Employee.Email)
+			if firstRow, ok := firstRows[email]; ok {
+				row.Errors = append(row.Errors, *model.NewValidationError("email",
+					fmt.Sprintf("email %s повторяет строку %d", row.Employee.Email, firstRow)))
+			} else {
+				firstRows[email] = row.Row
+			}
+		}
+
+		var existingId int64
+		if row.Employee != nil && len(row.Errors) == 0 {
+			existingId = existing[model.NormalizeEmail(row.Employee.Email)]
+			err = cs.checkEmployeeEmail(ctx, tx, existingId, row.Employee.Email)
+			var conflict *model.ConflictError
+			if errors.As(err, &conflict) {
+				row.Errors = append(row.Errors, *model.NewValidationError("email", conflict.Message))
+			} else if err != nil {
+				cs.cr.RollbackQuietly(tx, ctx)
+				return nil, err
+			}
+		}
+
+		if len(row.Errors) > 0 {
+			row.Result = model.EmployeeImportFailed
+			report.Failed++
+			continue
+		}
+
+		eventType := model.EventEmployeeAdded
+		if existingId != 0 {
+			cs.prepareEmployeeUpdate(row.Employee)
+			row.Employee.Id = existingId
+			err = cs.cr.UpdateContractorEmployeeData(ctx, tx, existingId, row.Employee)
+			row.Result = model.EmployeeImportUpdated
+			eventType = model.EventEmployeeUpdated
+			report.Updated++
+		} else {
+			err = cs.cr.CreateContractorEmployee(ctx, tx, contractorId, row.Employee)
+			row.Result = model.EmployeeImportCreated
+			report.Created++
+		}
+		row.Employee.ContractorId = contractorId
+
+		if err == nil {
+			err = cs.writeEvent(ctx, tx, eventType, contractorId, model.NewEmployeeEventData(*row.Employee))
+		}
+		if err != nil {
+			cs.cr.RollbackQuietly(tx, ctx)
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		cs.cr.RollbackQuietly(tx, ctx)
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// checkEmployeeEmail проверяет, что email сотрудника employeeId (0 для нового) не является логином контрагента,
+// а при глобальной области уникальности - не занят сотрудником другого контрагента. Уникальность в рамках
+// контрагента обеспечивается индексом. Email блокируется до конца транзакции tx.
+func (cs *contractorService) checkEmployeeEmail(ctx context.Context, tx pgx.Tx, employeeId int64, email string) error {
+	if err := cs.cr.LockEmail(ctx, tx, email); err != nil {
+		return err
+	}
+
+	contractorId, err := cs.cr.FindContractorIdByEmail(ctx, tx, email)
+	if err != nil {
+		return err
+	}
+	if contractorId != nil {
+		conflict := model.NewConflictError(model.EntityContractor, "email", email,
+			fmt.Sprintf("email %s является логином контрагента с ИД %d", email, *contractorId))
+		conflict.ConflictingId = contractorId
+		return conflict
+	}
+
+	if cs.emailScope != model.EmployeeEmailScopeGlobal {
+		return nil
+	}
+
+	other, err := cs.cr.FindEmployeeByEmail(ctx, tx, email, employeeId)
+	if err != nil {
+		return err
+	}
+	if other != nil {
+		conflict := model.NewConflictError(model.EntityEmployee, "email", email,
+			fmt.Sprintf("email %s уже занят сотрудником с ИД %d контрагента с ИД %d", email, other.Id, other.ContractorId))
+		conflict.ConflictingId = &other.Id
+		return conflict
+	}
+
+	return nil
+}
+
+// checkContractorEmail проверяет, что email, становящийся логином контрагента, не занят неудаленным сотрудником
+// любого контрагента. Email блокируется до конца транзакции tx той же блокировкой, что и в checkEmployeeEmail,
+// поэтому встречные записи контрагента и сотрудника с одним email-ом выполняются последовательно.
+func (cs *contractorService) checkContractorEmail(ctx context.Context, tx pgx.Tx, email string) error {
+	if err := cs.cr.LockEmail(ctx, tx, email); err != nil {
+		return err
+	}
+
+	employee, err := cs.cr.FindEmployeeByEmail(ctx, tx, email, 0)
+	if err != nil {
+		return err
+	}
+	if employee != nil {
+		conflict := model.NewConflictError(model.EntityEmployee, "email", email,
+			fmt.Sprintf("email %s занят сотрудником с ИД %d контрагента с ИД %d", email, employee.Id, employee.ContractorId))
+		conflict.ConflictingId = &employee.Id
+		return conflict
+	}
+
+	return nil
+}
+
+// checkContractorRestore проверяет, что логин восстанавливаемого контрагента не занят сотрудником,
+// а его родитель действует и не образует цикл
+func (cs *contractorService) checkContractorRestore(ctx context.Context, tx pgx.Tx, id int64) error {
+	contractor, err := cs.cr.LockContractor(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	if err = cs.checkContractorParent(ctx, tx, id, contractor.ParentId); err != nil {
+		return err
+	}
+
+	return cs.checkContractorEmail(ctx, tx, contractor.Email)
+}
+
+// prepareEmployeeUpdate выставляет статус и дату блокировки обновляемого сотрудника
+func (cs *contractorService) prepareEmployeeUpdate(employee *model.Employee) {
+	if employee.Status == model.EmployeeStatusBlock {
+		blockDate := time.Now().UTC()
+		employee.BlockDate = &blockDate
+	} else {
+		employee.BlockDate = nil
+		employee.Status = model.EmployeeStatusActive
+	}
+}
+
+func (cs *contractorService) DeleteContractorEmployee(ctx context.Context, id int64) error {
+	tx, err := cs.cr.WithTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	contractorId, err := cs.cr.DeleteContractorEmployee(ctx, tx, id)
+	if err != nil {
+		cs.cr.RollbackQuietly(tx, ctx)
+		return err
+	}
+
+	err = cs.writeEvent(ctx, tx, model.EventEmployeeDeleted, contractorId,
+		model.EmployeeRefEventData{Id: id, ContractorId: contractorId})
+	if err != nil {
+		cs.cr.RollbackQuietly(tx, ctx)
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		cs.cr.RollbackQuietly(tx, ctx)
+		return err
+	}
+
+	return nil
 }
 
 func (cs *contractorService) GeneratePassword() (string, error) {
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	uuid "github.com/satori/go.uuid"
+	log "github.com/sirupsen/logrus"
+	"io"
+	"mime"
+	"net/http"
+	"service_admin_contractor/domain/model"
+	"service_admin_contractor/domain/repository"
+	"strings"
+)
+
+// sniffLength является количеством байт, по которым определяется MIME тип содержимого
+const sniffLength = 512
+
+type DocumentService interface {
+	FindDocuments(ctx context.Context, contractorId int64) ([]model.Document, error)
+	GetDocument(ctx context.Context, contractorId int64, id int64) (model.Document, error)
+	UploadDocument(ctx context.Context, user model.UserInfo, document *model.Document, content io.Reader) error
+	OpenDocument(ctx context.Context, contractorId int64, id int64) (model.Document, io.ReadCloser, error)
+	DeleteDocument(ctx context.Context, contractorId int64, id int64) error
+}
+
+type documentService struct {
+	cr           repository.ContractorRepository
+	dr           repository.DocumentRepository
+	storage      repository.DocumentStorage
+	maxSize      int64
+	allowedTypes []string
+}
+
+// NewDocumentService создает сервис документов. Загружаемые файлы ограничены размером maxSize байт
+// и MIME типами allowedTypes, определяемыми по содержимому.
+func NewDocumentService(cr repository.ContractorRepository, dr repository.DocumentRepository,
+	storage repository.DocumentStorage, maxSize int64, allowedTypes []string) DocumentService {
+	return &documentService{cr, dr, storage, maxSize, allowedTypes}
+}
+
+func (ds *documentService) FindDocuments(ctx context.Context, contractorId int64) ([]model.Document, error) {
+	if _, err := ds.cr.GetContractor(ctx, contractorId); err != nil {
+		return nil, err
+	}
+
+	return ds.dr.FindDocuments(ctx, contractorId)
+}
+
+func (ds *documentService) GetDocument(ctx context.Context, contractorId int64, id int64) (model.Document, error) {
+	return ds.dr.GetDocument(ctx, contractorId, id)
+}
+
+// UploadDocument сохраняет содержимое в хранилище, вычисляя SHA-256 и размер при записи, и затем
+// записывает метаданные. Если метаданные не записались, файл удаляется из хранилища.
+func (ds *documentService) UploadDocument(ctx context.Context, user model.UserInfo, document *model.Document,
+	content io.Reader) error {
+	if _, err := ds.cr.GetContractor(ctx, document.ContractorId); err != nil {
+		return err
+	}
+
+	reader := bufio.NewReaderSize(content, sniffLength)
+	head, err := reader.Peek(sniffLength)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return err
+	}
+	if len(head) == 0 {
+		return model.NewValidationError("file", "файл пуст")
+	}
+
+	contentType, _, err := mime.ParseMediaType(http.DetectContentType(head))
+	if err != nil {
+		return err
+	}
+	if !ds.isAllowedType(contentType) {
+		return model.NewValidationError("file", fmt.Sprintf("тип файла %s не разрешен, допустимы: %s",
+			contentType, strings.Join(ds.allowedTypes, ", ")))
+	}
+
+	hash := sha256.New()
+	counter := &countingReader{r: io.TeeReader(io.LimitReader(reader, ds.maxSize+1), hash)}
+	key := fmt.Sprintf("%d/%s", document.ContractorId, uuid.NewV4().String())
+
+	if err = ds.storage.Save(ctx, key, counter); err != nil {
+		return err
+	}
+
+	if counter.n > ds.maxSize {
+		ds.deleteContent(ctx, key)
+		return model.NewValidationError("file", fmt.Sprintf("размер файла превышает %d байт", ds.maxSize))
+	}
+
+	uploadedBy := user.Login()
+	document.ContentType = contentType
+	document.Size = counter.n
+	document.Sha256 = hex.EncodeToString(hash.Sum(nil))
+	document.StorageKey = key
+	document.UploadedBy = &uploadedBy
+
+	if err = ds.dr.CreateDocument(ctx, document); err != nil {
+		ds.deleteContent(ctx, key)
+		return err
+	}
+
+	return nil
+}
+
+func (ds *documentService) OpenDocument(ctx context.Context, contractorId int64,
+	id int64) (model.Document, io.ReadCloser, error) {
+	document, err := ds.dr.GetDocument(ctx, contractorId, id)
+	if err != nil {
+		return model.Document{}, nil, err
+	}
+
+	content, err := ds.storage.Open(ctx, document.StorageKey)
+	if err != nil {
+		return model.Document{}, nil, err
+	}
+
+	return document, content, nil
+}
+
+// DeleteDocument помечает документ удаленным и удаляет его содержимое из хранилища
+func (ds *documentService) DeleteDocument(ctx context.Context, contractorId int64, id int64) error {
+	document, err := ds.dr.DeleteDocument(ctx, contractorId, id)
+	if err != nil {
+		return err
+	}
+
+	ds.deleteContent(ctx, document.StorageKey)
+
+	return nil
+}
+
+func (ds *documentService) isAllowedType(contentType string) bool {
+	for _, allowed := range ds.allowedTypes {
+		if strings.EqualFold(strings.TrimSpace(allowed), contentType) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// deleteContent удаляет содержимое из хранилища. Ошибка только логируется: метаданные уже согласованы,
+// а оставшийся файл недоступен через API.
+func (ds *documentService) deleteContent(ctx context.Context, key string) {
+	if err := ds.storage.Delete(ctx, key); err != nil {
+		log.Warnf("could not delete document content %s: %v", key, err)
+	}
+}
+
+// countingReader подсчитывает количество прочитанных байт
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"service_admin_contractor/application/cerrors"
+	"service_admin_contractor/domain/model"
+	"service_admin_contractor/domain/repository"
+)
+
+type SavedSearchService interface {
+	FindSavedSearches(ctx context.Context, user model.UserInfo) ([]model.SavedSearch, error)
+	GetSavedSearch(ctx context.Context, user model.UserInfo, id int64) (model.SavedSearch, error)
+	CreateSavedSearch(ctx context.Context, user model.UserInfo, search *model.SavedSearch) error
+	UpdateSavedSearch(ctx context.Context, user model.UserInfo, id int64, search *model.SavedSearch) error
+	DeleteSavedSearch(ctx context.Context, user model.UserInfo, id int64) error
+}
+
+type savedSearchService struct {
+	sr repository.SavedSearchRepository
+}
+
+func NewSavedSearchService(sr repository.SavedSearchRepository) SavedSearchService {
+	return &savedSearchService{sr}
+}
+
+func (ss *savedSearchService) FindSavedSearches(ctx context.Context, user model.UserInfo) ([]model.SavedSearch, error) {
+	return ss.sr.FindSavedSearches(ctx, user.Login(), user.Roles())
+}
+
+func (ss *savedSearchService) GetSavedSearch(ctx context.Context, user model.UserInfo,
+	id int64) (model.SavedSearch, error) {
+	search, err := ss.sr.GetSavedSearch(ctx, id)
+	if err != nil {
+		return model.SavedSearch{}, err
+	}
+
+	if search == nil {
+		return model.SavedSearch{}, model.NewNotFoundError(model.EntitySavedSearch, id)
+	}
+
+	if !search.IsVisibleTo(user) {
+		return model.SavedSearch{}, cerrors.ErrSavedSearchAccessDenied(id)
+	}
+
+	return *search, nil
+}
+
+func (ss *savedSearchService) CreateSavedSearch(ctx context.Context, user model.UserInfo,
+	search *model.SavedSearch) error {
+	search.OwnerLogin = user.Login()
+
+	return ss.sr.CreateSavedSearch(ctx, search)
+}
+
+func (ss *savedSearchService) UpdateSavedSearch(ctx context.Context, user model.UserInfo, id int64,
+	search *model.SavedSearch) error {
+	existing, err := ss.getOwnSavedSearch(ctx, user, id)
+	if err != nil {
+		return err
+	}
+
+	search.Id = existing.Id
+	search.OwnerLogin = existing.OwnerLogin
+	search.CreatedAt = existing.CreatedAt
+
+	return ss.sr.UpdateSavedSearch(ctx, search)
+}
+
+func (ss *savedSearchService) DeleteSavedSearch(ctx context.Context, user model.UserInfo, id int64) error {
+	if _, err := ss.getOwnSavedSearch(ctx, user, id); err != nil {
+		return err
+	}
+
+	return ss.sr.DeleteSavedSearch(ctx, id)
+}
+
+// getOwnSavedSearch возвращает поиск, если пользователь является его владельцем.
+// Изменять и удалять поиск, которым поделились через роль, может только владелец.
+func (ss *savedSearchService) getOwnSavedSearch(ctx context.Context, user model.UserInfo,
+	id int64) (*model.SavedSearch, error) {
+	search, err := ss.sr.GetSavedSearch(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if search == nil {
+		return nil, model.NewNotFoundError(model.EntitySavedSearch, id)
+	}
+
+	if !search.IsOwnedBy(user) {
+		return nil, cerrors.ErrSavedSearchAccessDenied(id)
+	}
+
+	return search, nil
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"github.com/jackc/pgx/v4"
+	"service_admin_contractor/domain/model"
+	"service_admin_contractor/domain/repository"
+)
+
+type TagService interface {
+	FindTags(ctx context.Context, category *string) ([]model.Tag, error)
+	GetTag(ctx context.Context, id int64) (model.Tag, error)
+	CreateTag(ctx context.Context, tag *model.Tag) error
+	UpdateTag(ctx context.Context, tag *model.Tag) error
+	DeleteTag(ctx context.Context, user model.UserInfo, id int64) error
+
+	FindContractorTags(ctx context.Context, contractorId int64) ([]model.Tag, error)
+	AddContractorTag(ctx context.Context, user model.UserInfo, contractorId int64, tagId int64) error
+	RemoveContractorTag(ctx context.Context, user model.UserInfo, contractorId int64, tagId int64) error
+	ReplaceContractorTags(ctx context.Context, user model.UserInfo, contractorId int64,
+		tagIds []int64) ([]model.Tag, error)
+}
+
+type tagService struct {
+	cr repository.ContractorRepository
+	gr repository.TagRepository
+	ar repository.AuditRepository
+	or repository.OutboxRepository
+}
+
+func NewTagService(cr repository.ContractorRepository, gr repository.TagRepository,
+	ar repository.AuditRepository, or repository.OutboxRepository) TagService {
+	return &tagService{cr, gr, ar, or}
+}
+
+func (ts *tagService) FindTags(ctx context.Context, category *string) ([]model.Tag, error) {
+	return ts.gr.FindTags(ctx, category)
+}
+
+func (ts *tagService) GetTag(ctx context.Context, id int64) (model.Tag, error) {
+	return ts.gr.GetTag(ctx, id)
+}
+
+func (ts *tagService) CreateTag(ctx context.Context, tag *model.Tag) error {
+	tx, err := ts.gr.WithTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = ts.gr.CreateTag(ctx, tx, tag); err != nil {
+		ts.gr.RollbackQuietly(tx, ctx)
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (ts *tagService) UpdateTag(ctx context.Context, tag *model.Tag) error {
+	tx, err := ts.gr.WithTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = ts.gr.UpdateTag(ctx, tx, tag); err != nil {
+		ts.gr.RollbackQuietly(tx, ctx)
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// DeleteTag удаляет тег и записывает изменение тегов каждого контрагента, с которого он снят
+func (ts *tagService) DeleteTag(ctx context.Context, user model.UserInfo, id int64) error {
+	tx, err := ts.gr.WithTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	contractorIds, err := ts.gr.DeleteTag(ctx, tx, id)
+	if err != nil {
+		ts.gr.RollbackQuietly(tx, ctx)
+		return err
+	}
+
+	for _, contractorId := range contractorIds {
+		if err = ts.writeTagsChange(ctx, tx, user, contractorId, nil); err != nil {
+			ts.gr.RollbackQuietly(tx, ctx)
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (ts *tagService) FindContractorTags(ctx context.Context, contractorId int64) ([]model.Tag, error) {
+	if _, err := ts.cr.GetContractor(ctx, contractorId); err != nil {
+		return nil, err
+	}
+
+	return ts.gr.FindContractorTags(ctx, contractorId)
+}
+
+func (ts *tagService) AddContractorTag(ctx context.Context, user model.UserInfo, contractorId int64,
+	tagId int64) error {
+	if _, err := ts.cr.GetContractor(ctx, contractorId); err != nil {
+		return err
+	}
+
+	if err := ts.checkTagsExist(ctx, []int64{tagId}); err != nil {
+		return err
+	}
+
+	tx, err := ts.gr.WithTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	before, err := ts.gr.FindContractorTagIds(ctx, tx, contractorId)
+	if err != nil {
+		ts.gr.RollbackQuietly(tx, ctx)
+		return err
+	}
+
+	if err = ts.gr.AddContractorTag(ctx, tx, contractorId, tagId); err != nil {
+		ts.gr.RollbackQuietly(tx, ctx)
+		return err
+	}
+
+	if err = ts.writeTagsChange(ctx, tx, user, contractorId, before); err != nil {
+		ts.gr.RollbackQuietly(tx, ctx)
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (ts *tagService) RemoveContractorTag(ctx context.Context, user model.UserInfo, contractorId int64,
+	tagId int64) error {
+	tx, err := ts.gr.WithTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = ts.gr.RemoveContractorTag(ctx, tx, contractorId, tagId); err != nil {
+		ts.gr.RollbackQuietly(tx, ctx)
+		return err
+	}
+
+	if err = ts.writeTagsChange(ctx, tx, user, contractorId, nil); err != nil {
+		ts.gr.RollbackQuietly(tx, ctx)
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ReplaceContractorTags заменяет теги контрагента и возвращает итоговый набор
+func (ts *tagService) ReplaceContractorTags(ctx context.Context, user model.UserInfo, contractorId int64,
+	tagIds []int64) ([]model.Tag, error) {
+	if _, err := ts.cr.GetContractor(ctx, contractorId); err != nil {
+		return nil, err
+	}
+
+	if err := ts.checkTagsExist(ctx, tagIds); err != nil {
+		return nil, err
+	}
+
+	tx, err := ts.gr.WithTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	before, err := ts.gr.FindContractorTagIds(ctx, tx, contractorId)
+	if err != nil {
+		ts.gr.RollbackQuietly(tx, ctx)
+		return nil, err
+	}
+
+	if err = ts.gr.ReplaceContractorTags(ctx, tx, contractorId, tagIds); err != nil {
+		ts.gr.RollbackQuietly(tx, ctx)
+		return nil, err
+	}
+
+	if err = ts.writeTagsChange(ctx, tx, user, contractorId, before); err != nil {
+		ts.gr.RollbackQuietly(tx, ctx)
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return ts.gr.FindContractorTags(ctx, contractorId)
+}
+
+// checkTagsExist возвращает NotFoundError для первого тега из списка, который не найден или удален
+func (ts *tagService) checkTagsExist(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	existing, err := ts.gr.FindExistingTagIds(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	found := make(map[int64]bool, len(existing))
+	for _, id := range existing {
+		found[id] = true
+	}
+
+	for _, id := range ids {
+		if !found[id] {
+			return model.NewNotFoundError(model.EntityTag, id)
+		}
+	}
+
+	return nil
+}
+
+// writeTagsChange записывает событие outbox и запись журнала действий с итоговым набором тегов контрагента.
+// Если передан набор тегов before до изменения и итоговый набор с ним совпадает, ничего не записывается.
+func (ts *tagService) writeTagsChange(ctx context.Context, tx pgx.Tx, user model.UserInfo, contractorId int64,
+	before []int64) error {
+	tagIds, err := ts.gr.FindContractorTagIds(ctx, tx, contractorId)
+	if err != nil {
+		return err
+	}
+
+	if before != nil && equalIds(before, tagIds) {
+		return nil
+	}
+
+	err = writeOutboxEvent(ctx, ts.or, tx, model.EventTagsUpdated, contractorId,
+		model.ContractorTagsEventData{ContractorId: contractorId, TagIds: tagIds})
+	if err != nil {
+		return err
+	}
+
+	return ts.ar.CreateAuditEntry(ctx, tx, &model.AuditEntry{
+		Entity:     model.AuditEntityContractor,
+		EntityId:   contractorId,
+		Action:     model.AuditActionUpdateTags,
+		ActorLogin: user.Login(),
+		Details:    map[string]interface{}{"tagIds": tagIds},
+	})
+}
+
+// equalIds сравнивает упорядоченные списки ИД
+func equalIds(a []int64, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"service_admin_contractor/application/webhook"
+	"service_admin_contractor/domain/model"
+	"service_admin_contractor/domain/repository"
+)
+
+// webhookSecretSize является размером генерируемого секрета подписи в байтах
+const webhookSecretSize = 32
+
+type WebhookService interface {
+	FindWebhookSubscriptions(ctx context.Context) ([]model.WebhookSubscription, error)
+	GetWebhookSubscription(ctx context.Context, id int64) (model.WebhookSubscription, error)
+	CreateWebhookSubscription(ctx context.Context, user model.UserInfo, subscription *model.WebhookSubscription) error
+	UpdateWebhookSubscription(ctx context.Context, id int64, subscription *model.WebhookSubscription) error
+	DeleteWebhookSubscription(ctx context.Context, id int64) error
+
+	FindWebhookDeliveries(ctx context.Context, subscriptionId int64,
+		pagination model.Pagination) ([]model.WebhookDelivery, int64, error)
+	RedeliverWebhookDelivery(ctx context.Context, subscriptionId int64, id int64) (model.WebhookDelivery, error)
+}
+
+type webhookService struct {
+	wr repository.WebhookRepository
+}
+
+func NewWebhookService(wr repository.WebhookRepository) WebhookService {
+	return &webhookService{wr}
+}
+
+func (ws *webhookService) FindWebhookSubscriptions(ctx context.Context) ([]model.WebhookSubscription, error) {
+	return ws.wr.FindWebhookSubscriptions(ctx)
+}
+
+func (ws *webhookService) GetWebhookSubscription(ctx context.Context, id int64) (model.WebhookSubscription, error) {
+	return ws.wr.GetWebhookSubscription(ctx, id)
+}
+
+// CreateWebhookSubscription создает подписку. Если секрет не задан, он генерируется.
+func (ws *webhookService) CreateWebhookSubscription(ctx context.Context, user model.UserInfo,
+	subscription *model.WebhookSubscription) error {
+	if err := checkWebhookTarget(ctx, subscription.Url); err != nil {
+		return err
+	}
+
+	if subscription.Secret == "" {
+		secret, err := generateWebhookSecret()
+		if err != nil {
+			return err
+		}
+		subscription.Secret = secret
+	}
+
+	subscription.CreatedBy = user.Login()
+
+	return ws.wr.CreateWebhookSubscription(ctx, subscription)
+}
+
+// UpdateWebhookSubscription изменяет подписку. Если секрет не задан, сохраняется прежний.
+func (ws *webhookService) UpdateWebhookSubscription(ctx context.Context, id int64,
+	subscription *model.WebhookSubscription) error {
+	existing, err := ws.wr.GetWebhookSubscription(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err = checkWebhookTarget(ctx, subscription.Url); err != nil {
+		return err
+	}
+
+	subscription.Id = id
+	subscription.CreatedBy = existing.CreatedBy
+	subscription.CreatedAt = existing.CreatedAt
+	if subscription.Secret == "" {
+		subscription.Secret = existing.Secret
+	}
+
+	return ws.wr.UpdateWebhookSubscription(ctx, subscription)
+}
+
+func (ws *webhookService) DeleteWebhookSubscription(ctx context.Context, id int64) error {
+	return ws.wr.DeleteWebhookSubscription(ctx, id)
+}
+
+func (ws *webhookService) FindWebhookDeliveries(ctx context.Context, subscriptionId int64,
+	pagination model.Pagination) ([]model.WebhookDelivery, int64, error) {
+	if _, err := ws.wr.GetWebhookSubscription(ctx, subscriptionId); err != nil {
+		return nil, 0, err
+	}
+
+	return ws.wr.FindWebhookDeliveries(ctx, subscriptionId, pagination)
+}
+
+// RedeliverWebhookDelivery ставит доставку в очередь на повторную отправку независимо от ее статуса
+func (ws *webhookService) RedeliverWebhookDelivery(ctx context.Context, subscriptionId int64,
+	id int64) (model.WebhookDelivery, error) {
+	if err := ws.wr.ResetWebhookDelivery(ctx, subscriptionId, id); err != nil {
+		return model.WebhookDelivery{}, err
+	}
+
+	return ws.wr.GetWebhookDelivery(ctx, subscriptionId, id)
+}
+
+// checkWebhookTarget не допускает подписки на адреса внутренней сети
+func checkWebhookTarget(ctx context.Context, url string) error {
+	if err := webhook.CheckTarget(ctx, url); err != nil {
+		return model.NewValidationError("url", err.Error())
+	}
+
+	return nil
+}
+
+func generateWebhookSecret() (string, error) {
+	secret := make([]byte, webhookSecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(secret), nil
+}
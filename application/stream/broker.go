@@ -0,0 +1,94 @@
+package stream
+
+import (
+	"context"
+	log "github.com/sirupsen/logrus"
+	"service_admin_contractor/domain/repository"
+	"sync"
+	"time"
+)
+
+// Broker слушает уведомления об изменениях одним соединением с БД и будит всех подписчиков.
+// Уведомление только сообщает о новых записях журнала: сами изменения подписчик читает из журнала,
+// так как запись становится видимой в нем позже уведомления, если в кластере есть более старая транзакция.
+type Broker struct {
+	cr repository.ChangeRepository
+
+	mu          sync.Mutex
+	subscribers map[chan struct{}]struct{}
+
+	// ReconnectDelay является паузой перед повторной подпиской после ошибки соединения
+	ReconnectDelay time.Duration
+}
+
+func NewBroker(cr repository.ChangeRepository) *Broker {
+	return &Broker{
+		cr:             cr,
+		subscribers:    make(map[chan struct{}]struct{}),
+		ReconnectDelay: 5 * time.Second,
+	}
+}
+
+// Subscribe возвращает канал сигналов о новых изменениях и функцию отписки. Несколько уведомлений,
+// поступивших до чтения сигнала, объединяются в один. Канал закрывается при отписке и при остановке Broker-а.
+func (b *Broker) Subscribe() (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.remove(ch)
+	}
+}
+
+// Run слушает уведомления до отмены ctx, переподключаясь после ошибок
+func (b *Broker) Run(ctx context.Context) {
+	defer b.closeAll()
+
+	for {
+		err := b.cr.ListenChanges(ctx, b.publish)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Error("change broker: ", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(b.ReconnectDelay):
+		}
+	}
+}
+
+func (b *Broker) publish() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (b *Broker) closeAll() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers {
+		b.remove(ch)
+	}
+}
+
+// remove отключает подписчика. Вызывается под b.mu.
+func (b *Broker) remove(ch chan struct{}) {
+	if _, ok := b.subscribers[ch]; ok {
+		delete(b.subscribers, ch)
+		close(ch)
+	}
+}
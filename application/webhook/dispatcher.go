@@ -0,0 +1,175 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"service_admin_contractor/application/outbox"
+	"service_admin_contractor/domain/model"
+	"service_admin_contractor/domain/repository"
+	"strconv"
+	"time"
+)
+
+// maxErrorBodySize ограничивает размер тела ответа, сохраняемого в журнале попыток
+const maxErrorBodySize = 512
+
+// Dispatcher отправляет доставки подписчикам, записывая каждую попытку.
+// Неудачные доставки повторяются с экспоненциальной задержкой, после MaxAttempts помечаются FAILED.
+// Соединения с внутренними адресами отклоняются, см. CheckTarget.
+type Dispatcher struct {
+	wr     repository.WebhookRepository
+	client *http.Client
+
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	MaxBackoff   time.Duration
+}
+
+func NewDispatcher(wr repository.WebhookRepository, timeout time.Duration) *Dispatcher {
+	return &Dispatcher{
+		wr:           wr,
+		client:       newClient(timeout),
+		PollInterval: 5 * time.Second,
+		BatchSize:    20,
+		MaxAttempts:  8,
+		MaxBackoff:   6 * time.Hour,
+	}
+}
+
+// Run отправляет доставки до отмены ctx
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			count, err := d.DispatchBatch(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Error("webhook dispatcher: ", err)
+				}
+				break
+			}
+			if count < d.BatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchBatch захватывает порцию доставок, отправляет их вне транзакции и записывает результат каждой попытки
+// отдельной короткой транзакцией. Возвращает количество обработанных доставок.
+func (d *Dispatcher) DispatchBatch(ctx context.Context) (int, error) {
+	deliveries, err := d.wr.ClaimWebhookDeliveries(ctx, d.BatchSize, d.lease())
+	if err != nil {
+		return 0, err
+	}
+
+	for _, delivery := range deliveries {
+		attempt := d.Send(ctx, delivery)
+
+		if err = d.recordAttempt(ctx, delivery, &attempt); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(deliveries), nil
+}
+
+// recordAttempt записывает попытку и новое состояние доставки. Если запись не удалась,
+// доставка будет отправлена повторно после окончания захвата.
+func (d *Dispatcher) recordAttempt(ctx context.Context, delivery model.PendingWebhookDelivery,
+	attempt *model.WebhookDeliveryAttempt) error {
+	tx, err := d.wr.WithTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = d.wr.CreateWebhookDeliveryAttempt(ctx, tx, attempt); err != nil {
+		d.wr.RollbackQuietly(tx, ctx)
+		return err
+	}
+
+	if attempt.Error == nil {
+		err = d.wr.MarkWebhookDeliveryDelivered(ctx, tx, delivery.Id, *attempt.StatusCode)
+	} else {
+		err = d.wr.MarkWebhookDeliveryFailed(ctx, tx, delivery.Id, attempt.StatusCode, *attempt.Error,
+			d.nextAttemptAt(delivery.Attempts+1))
+	}
+	if err != nil {
+		d.wr.RollbackQuietly(tx, ctx)
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// lease возвращает время захвата порции: каждая доставка порции может ожидать ответа до истечения таймаута клиента
+func (d *Dispatcher) lease() time.Duration {
+	return time.Duration(d.BatchSize)*d.client.Timeout + time.Minute
+}
+
+// Send выполняет одну попытку доставки. Успешной считается попытка с ответом 2xx.
+func (d *Dispatcher) Send(ctx context.Context, delivery model.PendingWebhookDelivery) model.WebhookDeliveryAttempt {
+	attempt := model.WebhookDeliveryAttempt{DeliveryId: delivery.Id, AttemptedAt: time.Now().UTC()}
+	fail := func(err error) model.WebhookDeliveryAttempt {
+		message := err.Error()
+		attempt.Error = &message
+		attempt.Duration = time.Since(attempt.AttemptedAt)
+		return attempt
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return fail(err)
+	}
+
+	timestamp := attempt.AttemptedAt.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEventId, delivery.EventId)
+	req.Header.Set(HeaderEventType, string(delivery.EventType))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(delivery.Secret, timestamp, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fail(err)
+	}
+	defer resp.Body.Close()
+
+	statusCode := resp.StatusCode
+	attempt.StatusCode = &statusCode
+
+	if statusCode < 200 || statusCode >= 300 {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		return fail(fmt.Errorf("получатель ответил %d: %s", statusCode, body))
+	}
+
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	attempt.Duration = time.Since(attempt.AttemptedAt)
+
+	return attempt
+}
+
+// nextAttemptAt возвращает время следующей попытки после attempts неудачных попыток
+// или nil, если попытки исчерпаны
+func (d *Dispatcher) nextAttemptAt(attempts int) *time.Time {
+	if attempts >= d.MaxAttempts {
+		return nil
+	}
+
+	next := time.Now().Add(outbox.Backoff(d.PollInterval, d.MaxBackoff, attempts))
+	return &next
+}
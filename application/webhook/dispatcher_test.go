@@ -0,0 +1,313 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v4"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"service_admin_contractor/domain/model"
+	"service_admin_contractor/domain/repository"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeTx подменяет транзакцию: фиксация и откат только отмечаются
+type fakeTx struct {
+	pgx.Tx
+	committed bool
+}
+
+func (t *fakeTx) Commit(ctx context.Context) error {
+	t.committed = true
+	return nil
+}
+
+func (t *fakeTx) Rollback(ctx context.Context) error {
+	return nil
+}
+
+// fakeWebhookRepository хранит одну доставку в памяти и записывает попытки и итоговое состояние
+type fakeWebhookRepository struct {
+	repository.WebhookRepository
+
+	delivery      model.PendingWebhookDelivery
+	status        model.WebhookDeliveryStatus
+	attempts      []model.WebhookDeliveryAttempt
+	nextAttemptAt *time.Time
+	commits       int
+}
+
+func newFakeWebhookRepository(url string, secret string, payload string) *fakeWebhookRepository {
+	delivery := model.PendingWebhookDelivery{Url: url, Secret: secret}
+	delivery.Id = 1
+	delivery.EventId = "0f6e9a4c-6d3b-4d0f-9a57-3f0d2f6f1b11"
+	delivery.EventType = model.EventType("contractor.updated")
+	delivery.Payload = []byte(payload)
+
+	return &fakeWebhookRepository{delivery: delivery, status: model.WebhookDeliveryPending}
+}
+
+func (r *fakeWebhookRepository) WithTransaction(ctx context.Context) (pgx.Tx, error) {
+	return &fakeTx{}, nil
+}
+
+func (r *fakeWebhookRepository) RollbackQuietly(tx pgx.Tx, ctx context.Context) {
+}
+
+func (r *fakeWebhookRepository) ClaimWebhookDeliveries(ctx context.Context, limit int,
+	lease time.Duration) ([]model.PendingWebhookDelivery, error) {
+	if r.status != model.WebhookDeliveryPending {
+		return nil, nil
+	}
+
+	return []model.PendingWebhookDelivery{r.delivery}, nil
+}
+
+func (r *fakeWebhookRepository) CreateWebhookDeliveryAttempt(ctx context.Context, tx pgx.Tx,
+	attempt *model.WebhookDeliveryAttempt) error {
+	r.attempts = append(r.attempts, *attempt)
+	return nil
+}
+
+func (r *fakeWebhookRepository) MarkWebhookDeliveryDelivered(ctx context.Context, tx pgx.Tx, id int64,
+	statusCode int) error {
+	r.status = model.WebhookDeliveryDelivered
+	r.delivery.Attempts++
+	return r.commit(tx)
+}
+
+func (r *fakeWebhookRepository) MarkWebhookDeliveryFailed(ctx context.Context, tx pgx.Tx, id int64, statusCode *int,
+	lastError string, nextAttemptAt *time.Time) error {
+	if nextAttemptAt == nil {
+		r.status = model.WebhookDeliveryFailed
+	}
+	r.nextAttemptAt = nextAttemptAt
+	r.delivery.Attempts++
+	return r.commit(tx)
+}
+
+func (r *fakeWebhookRepository) commit(tx pgx.Tx) error {
+	if tx.(*fakeTx).committed {
+		return errors.New("transaction is already committed")
+	}
+	r.commits++
+	return nil
+}
+
+// receiver является получателем webhook-ов, отвечающим по очереди кодами statuses
+type receiver struct {
+	mu       sync.Mutex
+	t        *testing.T
+	secret   string
+	statuses []int
+	requests int
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		rc.t.Errorf("read body: %v", err)
+	}
+
+	timestamp, err := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+	if err != nil {
+		rc.t.Errorf("timestamp header: %v", err)
+	}
+	if !Verify(rc.secret, timestamp, body, r.Header.Get(HeaderSignature)) {
+		rc.t.Errorf("signature %q does not match body %q", r.Header.Get(HeaderSignature), body)
+	}
+	if r.Header.Get(HeaderEventId) == "" || r.Header.Get(HeaderEventType) != "contractor.updated" {
+		rc.t.Errorf("unexpected event headers: %v", r.Header)
+	}
+
+	status := rc.statuses[len(rc.statuses)-1]
+	if rc.requests < len(rc.statuses) {
+		status = rc.statuses[rc.requests]
+	}
+	rc.requests++
+
+	w.WriteHeader(status)
+}
+
+func newTestDispatcher(wr repository.WebhookRepository, server *httptest.Server) *Dispatcher {
+	d := NewDispatcher(wr, time.Second)
+	d.client = server.Client()
+	d.PollInterval = time.Second
+	d.MaxBackoff = time.Minute
+	return d
+}
+
+func TestSendSignsPayload(t *testing.T) {
+	rc := &receiver{t: t, secret: "0123456789abcdef", statuses: []int{http.StatusNoContent}}
+	server := httptest.NewServer(rc)
+	defer server.Close()
+
+	wr := newFakeWebhookRepository(server.URL, rc.secret, `{"id":1}`)
+	attempt := newTestDispatcher(wr, server).Send(context.Background(), wr.delivery)
+
+	if attempt.Error != nil {
+		t.Fatalf("unexpected error: %s", *attempt.Error)
+	}
+	if attempt.StatusCode == nil || *attempt.StatusCode != http.StatusNoContent {
+		t.Fatalf("status code = %v, want %d", attempt.StatusCode, http.StatusNoContent)
+	}
+	if rc.requests != 1 {
+		t.Fatalf("receiver got %d requests, want 1", rc.requests)
+	}
+}
+
+func TestVerifyRejectsTamperedBody(t *testing.T) {
+	signature := Sign("secret", 1700000000, []byte(`{"id":1}`))
+
+	if !Verify("secret", 1700000000, []byte(`{"id":1}`), signature) {
+		t.Fatal("signature of the original body is rejected")
+	}
+	if Verify("secret", 1700000000, []byte(`{"id":2}`), signature) {
+		t.Fatal("signature of a tampered body is accepted")
+	}
+	if Verify("secret", 1700000001, []byte(`{"id":1}`), signature) {
+		t.Fatal("signature with another timestamp is accepted")
+	}
+	if Verify("other", 1700000000, []byte(`{"id":1}`), signature) {
+		t.Fatal("signature with another secret is accepted")
+	}
+}
+
+func TestDispatchBatchRetriesUntilDelivered(t *testing.T) {
+	rc := &receiver{t: t, secret: "0123456789abcdef",
+		statuses: []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusOK}}
+	server := httptest.NewServer(rc)
+	defer server.Close()
+
+	wr := newFakeWebhookRepository(server.URL, rc.secret, `{"id":1}`)
+	d := newTestDispatcher(wr, server)
+
+	for i := 0; i < 3; i++ {
+		if _, err := d.DispatchBatch(context.Background()); err != nil {
+			t.Fatalf("dispatch %d: %v", i, err)
+		}
+		if i < 2 && (wr.status != model.WebhookDeliveryPending || wr.nextAttemptAt == nil) {
+			t.Fatalf("dispatch %d: status = %s, next attempt = %v, want a scheduled retry",
+				i, wr.status, wr.nextAttemptAt)
+		}
+	}
+
+	if wr.status != model.WebhookDeliveryDelivered {
+		t.Fatalf("status = %s, want %s", wr.status, model.WebhookDeliveryDelivered)
+	}
+	if len(wr.attempts) != 3 || wr.commits != 3 {
+		t.Fatalf("recorded %d attempts in %d transactions, want 3 in 3", len(wr.attempts), wr.commits)
+	}
+	if wr.attempts[0].Error == nil || wr.attempts[2].Error != nil {
+		t.Fatalf("unexpected attempt log: %+v", wr.attempts)
+	}
+}
+
+func TestDispatchBatchFailsAfterMaxAttempts(t *testing.T) {
+	rc := &receiver{t: t, secret: "0123456789abcdef", statuses: []int{http.StatusServiceUnavailable}}
+	server := httptest.NewServer(rc)
+	defer server.Close()
+
+	wr := newFakeWebhookRepository(server.URL, rc.secret, `{"id":1}`)
+	d := newTestDispatcher(wr, server)
+	d.MaxAttempts = 3
+
+	for i := 0; i < 5; i++ {
+		if _, err := d.DispatchBatch(context.Background()); err != nil {
+			t.Fatalf("dispatch %d: %v", i, err)
+		}
+	}
+
+	if wr.status != model.WebhookDeliveryFailed {
+		t.Fatalf("status = %s, want %s", wr.status, model.WebhookDeliveryFailed)
+	}
+	if rc.requests != 3 {
+		t.Fatalf("receiver got %d requests, want 3", rc.requests)
+	}
+}
+
+func TestNextAttemptAtBacksOff(t *testing.T) {
+	d := NewDispatcher(nil, time.Second)
+	d.PollInterval = time.Second
+	d.MaxBackoff = 4 * time.Second
+	d.MaxAttempts = 5
+
+	for attempts, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 4: 4 * time.Second} {
+		next := d.nextAttemptAt(attempts)
+		if next == nil {
+			t.Fatalf("attempts %d: no retry scheduled", attempts)
+		}
+		if delay := time.Until(*next); delay > want || delay < want-time.Second {
+			t.Fatalf("attempts %d: delay = %s, want %s", attempts, delay, want)
+		}
+	}
+
+	if next := d.nextAttemptAt(5); next != nil {
+		t.Fatalf("retry scheduled after the last attempt: %v", next)
+	}
+}
+
+func TestClientRejectsInternalTargets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request reached a loopback receiver")
+	}))
+	defer server.Close()
+
+	wr := newFakeWebhookRepository(server.URL, "0123456789abcdef", `{}`)
+	attempt := NewDispatcher(wr, time.Second).Send(context.Background(), wr.delivery)
+
+	if attempt.Error == nil || attempt.StatusCode != nil {
+		t.Fatalf("attempt to %s was not rejected: %+v", server.URL, attempt)
+	}
+}
+
+func TestCheckTarget(t *testing.T) {
+	tests := []struct {
+		url string
+		ok  bool
+	}{
+		{"https://93.184.216.34/hook", true},
+		{"http://[2606:2800:220:1:248:1893:25c8:1946]:8080/hook", true},
+		{"ftp://93.184.216.34/hook", false},
+		{"http://127.0.0.1/hook", false},
+		{"http://[::1]/hook", false},
+		{"http://0.0.0.0/hook", false},
+		{"http://10.1.2.3/hook", false},
+		{"http://172.20.0.1/hook", false},
+		{"http://192.168.1.1/hook", false},
+		{"http://169.254.169.254/latest/meta-data", false},
+		{"http://[fe80::1]/hook", false},
+		{"http://[fd00::1]/hook", false},
+		{"http://[::ffff:127.0.0.1]/hook", false},
+		{"http://localhost/hook", false},
+	}
+
+	for _, test := range tests {
+		err := CheckTarget(context.Background(), test.url)
+		if (err == nil) != test.ok {
+			t.Errorf("CheckTarget(%q) = %v, want ok = %t", test.url, err, test.ok)
+		}
+	}
+}
+
+func TestIsForbiddenIP(t *testing.T) {
+	for _, ip := range []string{"127.0.0.1", "10.0.0.1", "100.64.0.1", "224.0.0.1", "::", "::1"} {
+		if !IsForbiddenIP(net.ParseIP(ip)) {
+			t.Errorf("%s is allowed", ip)
+		}
+	}
+
+	for _, ip := range []string{"8.8.8.8", "2001:4860:4860::8888"} {
+		if IsForbiddenIP(net.ParseIP(ip)) {
+			t.Errorf("%s is forbidden", ip)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"service_admin_contractor/domain/model"
+	"service_admin_contractor/domain/repository"
+)
+
+// Publisher создает доставки события outbox для всех подписок на его тип.
+// Отправка и повторы выполняются Dispatcher-ом независимо для каждой подписки.
+type Publisher struct {
+	wr repository.WebhookRepository
+}
+
+func NewPublisher(wr repository.WebhookRepository) *Publisher {
+	return &Publisher{wr}
+}
+
+func (p *Publisher) Publish(ctx context.Context, event model.OutboxEvent) error {
+	payload, err := json.Marshal(event.Envelope())
+	if err != nil {
+		return err
+	}
+
+	return p.wr.CreateWebhookDeliveries(ctx, event, payload)
+}
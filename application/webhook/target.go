@@ -0,0 +1,128 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenTarget возвращается при попытке отправить webhook на внутренний адрес
+var ErrForbiddenTarget = errors.New("адрес получателя находится во внутренней сети")
+
+// forbiddenNetworks содержит диапазоны loopback, link-local, частных и служебных адресов,
+// на которые нельзя отправлять webhook-и
+var forbiddenNetworks = parseNetworks(
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"127.0.0.0/8",
+	"169.254.0.0/16",
+	"172.16.0.0/12",
+	"192.0.0.0/24",
+	"192.168.0.0/16",
+	"198.18.0.0/15",
+	"::1/128",
+	"fc00::/7",
+	"fe80::/10",
+)
+
+func parseNetworks(cidrs ...string) []*net.IPNet {
+	result := make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		result[i] = network
+	}
+
+	return result
+}
+
+// IsForbiddenIP возвращает true для адресов внутренней сети, а также неуказанных и multicast адресов
+func IsForbiddenIP(ip net.IP) bool {
+	if ip.IsUnspecified() || ip.IsMulticast() {
+		return true
+	}
+
+	for _, network := range forbiddenNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CheckTarget проверяет адрес подписки: допускаются только http и https,
+// а все адреса узла должны находиться вне внутренней сети
+func CheckTarget(ctx context.Context, rawUrl string) error {
+	target, err := url.Parse(rawUrl)
+	if err != nil {
+		return err
+	}
+
+	if target.Scheme != "http" && target.Scheme != "https" {
+		return fmt.Errorf("схема '%s' не поддерживается, используйте http или https", target.Scheme)
+	}
+
+	host := target.Hostname()
+	if host == "" {
+		return errors.New("не указан адрес получателя")
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		if IsForbiddenIP(ip) {
+			return ErrForbiddenTarget
+		}
+		return nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return fmt.Errorf("не удалось определить адрес узла '%s'", host)
+	}
+
+	for _, addr := range addrs {
+		if IsForbiddenIP(addr.IP) {
+			return ErrForbiddenTarget
+		}
+	}
+
+	return nil
+}
+
+// newClient создает http клиент, который отказывается соединяться с внутренними адресами.
+// Проверка выполняется для уже разрешенного адреса при каждом соединении, поэтому охватывает
+// перенаправления и смену DNS записи после создания подписки. Прокси из окружения не используется,
+// так как соединение с ним было бы отклонено той же проверкой.
+func newClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control: func(network, address string, c syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+
+			ip := net.ParseIP(host)
+			if ip == nil || IsForbiddenIP(ip) {
+				return ErrForbiddenTarget
+			}
+
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
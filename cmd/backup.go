@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"context"
+	"github.com/spf13/cobra"
+	"io"
+	"log"
+	"os"
+	"service_admin_contractor/application/backup"
+	"service_admin_contractor/application/config"
+	"service_admin_contractor/domain/model"
+	"service_admin_contractor/infrastructure/logging"
+	"service_admin_contractor/infrastructure/persistence/postgres"
+)
+
+var backupFile string
+
+// Является backup командой, сохраняющей данные сервиса в архив NDJSON
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Dumps contractors, employees and credentials to NDJSON archive",
+	Long: `Writes a versioned NDJSON archive with all rows of contractors, employees and credentials
+(password hashes only) read in one consistent snapshot. Writes to stdout if --file is "-".`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := config.CheckEnv(); err != nil {
+			log.Fatal(err)
+		}
+		logging.ConfigureLogger()
+
+		var out io.Writer = os.Stdout
+		if backupFile != "-" {
+			file, err := os.OpenFile(backupFile, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+			if err != nil {
+				log.Fatal(err)
+			}
+			defer file.Close()
+			out = file
+		}
+
+		pc := postgres.DBConn()
+		defer pc.Close()
+
+		counts, err := backup.Dump(context.Background(), postgres.NewBackupRepository(pc), out)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		for _, table := range model.BackupTables {
+			log.Printf("%s: %d rows", table, counts[table])
+		}
+	},
+}
+
+func init() {
+	backupCmd.Flags().StringVarP(&backupFile, "file", "f", "", `path to archive, "-" for stdout`)
+	_ = backupCmd.MarkFlagRequired("file")
+
+	RootCmd.AddCommand(backupCmd)
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"log"
+	"os"
+	"service_admin_contractor/application/config"
+	"service_admin_contractor/application/cvalidator"
+	"service_admin_contractor/application/dto"
+	"service_admin_contractor/application/importer"
+	"service_admin_contractor/application/service"
+	"service_admin_contractor/domain/model"
+	"service_admin_contractor/infrastructure/logging"
+	"service_admin_contractor/infrastructure/persistence/postgres"
+)
+
+var (
+	importFile   string
+	importDryRun bool
+)
+
+// Является import командой, создающей контрагентов из CSV/XLSX файла
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Imports contractors from CSV or XLSX file",
+	Long: `Validates every row of the CSV or XLSX file and creates all contractors in one transaction
+with generated passwords. With --dry-run only prints the validation report.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := config.CheckEnv(); err != nil {
+			log.Fatal(err)
+		}
+		logging.ConfigureLogger()
+		cvalidator.ConfigureValidator()
+
+		format, err := importer.DetectFormat(importFile, "")
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		file, err := os.Open(importFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer file.Close()
+
+		rows, err := importer.ReadContractorImportRows(file, format)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		emailScope, err := model.ParseEmployeeEmailScope(viper.GetString(config.EmployeeEmailScope))
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		pc := postgres.DBConn()
+		defer pc.Close()
+
+		contractorSrvc := service.NewContractorService(postgres.NewContractorRepository(pc),
+			postgres.NewAuditRepository(pc), postgres.NewOutboxRepository(pc), postgres.NewContactRepository(pc), emailScope)
+		report, err := contractorSrvc.ImportContractors(context.Background(), rows, importDryRun)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err = encoder.Encode(dto.ConvertContractorImportReport(*report)); err != nil {
+			log.Fatal(err)
+		}
+
+		if report.Failed > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	importCmd.Flags().StringVarP(&importFile, "file", "f", "", "path to CSV or XLSX file")
+	importCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "only validate rows without creating contractors")
+	_ = importCmd.MarkFlagRequired("file")
+
+	RootCmd.AddCommand(importCmd)
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"context"
+	"github.com/spf13/cobra"
+	"log"
+	"service_admin_contractor/application/config"
+	"service_admin_contractor/infrastructure/logging"
+	"service_admin_contractor/infrastructure/persistence/postgres"
+)
+
+var requeueContractorId int64
+
+// Является outbox-requeue командой, возвращающей события FAILED в очередь доставки
+var outboxRequeueCmd = &cobra.Command{
+	Use:   "outbox-requeue",
+	Short: "Requeues failed outbox events",
+	Long: `Returns outbox events in FAILED status to PENDING with a reset attempt counter. A FAILED event
+holds back later events of its contractor, so the contractor's feed resumes only after the requeue.
+Requeues events of all contractors unless --contractor is set.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := config.CheckEnv(); err != nil {
+			log.Fatal(err)
+		}
+		logging.ConfigureLogger()
+
+		pc := postgres.DBConn()
+		defer pc.Close()
+
+		var aggregateId *int64
+		if requeueContractorId != 0 {
+			aggregateId = &requeueContractorId
+		}
+
+		ctx := context.Background()
+		or := postgres.NewOutboxRepository(pc)
+		tx, err := or.WithTransaction(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		count, err := or.RequeueFailedOutboxEvents(ctx, tx, aggregateId)
+		if err != nil {
+			or.RollbackQuietly(tx, ctx)
+			log.Fatal(err)
+		}
+
+		if err = tx.Commit(ctx); err != nil {
+			log.Fatal(err)
+		}
+
+		log.Printf("requeued %d events", count)
+	},
+}
+
+func init() {
+	outboxRequeueCmd.Flags().Int64VarP(&requeueContractorId, "contractor", "c", 0,
+		"requeue events of this contractor only")
+
+	RootCmd.AddCommand(outboxRequeueCmd)
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"context"
+	"github.com/spf13/cobra"
+	"io"
+	"log"
+	"os"
+	"service_admin_contractor/application/backup"
+	"service_admin_contractor/application/config"
+	"service_admin_contractor/domain/model"
+	"service_admin_contractor/infrastructure/logging"
+	"service_admin_contractor/infrastructure/persistence/postgres"
+)
+
+var restoreFile string
+
+// Является restore командой, загружающей архив backup в пустую схему
+var restoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Restores contractors, employees and credentials from NDJSON archive",
+	Long: `Loads the archive created by backup into empty tables in one transaction, preserving ids
+and resetting bigserial sequences. Reads from stdin if --file is "-".`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := config.CheckEnv(); err != nil {
+			log.Fatal(err)
+		}
+		logging.ConfigureLogger()
+
+		var in io.Reader = os.Stdin
+		if restoreFile != "-" {
+			file, err := os.Open(restoreFile)
+			if err != nil {
+				log.Fatal(err)
+			}
+			defer file.Close()
+			in = file
+		}
+
+		pc := postgres.DBConn()
+		defer pc.Close()
+
+		counts, err := backup.Restore(context.Background(), postgres.NewBackupRepository(pc), in)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		for _, table := range model.BackupTables {
+			log.Printf("%s: %d rows", table, counts[table])
+		}
+	},
+}
+
+func init() {
+	restoreCmd.Flags().StringVarP(&restoreFile, "file", "f", "", `path to archive, "-" for stdin`)
+	_ = restoreCmd.MarkFlagRequired("file")
+
+	RootCmd.AddCommand(restoreCmd)
+}
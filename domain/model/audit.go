@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+const (
+	AuditEntityContractor  = "CONTRACTOR"
+	AuditEntityAddress     = "ADDRESS"
+	AuditEntityContact     = "CONTACT"
+	AuditEntityContract    = "CONTRACT"
+	AuditEntityBankAccount = "BANK_ACCOUNT"
+
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+	// AuditActionAutoBlock отмечает блокировку контрагента фоновым обработчиком, а не пользователем
+	AuditActionAutoBlock = "auto_block"
+	// AuditActionUpdateTags отмечает изменение набора тегов контрагента
+	AuditActionUpdateTags = "update_tags"
+
+	// AuditActorSystem является логином в записях журнала о действиях фоновых обработчиков
+	AuditActorSystem = "system"
+)
+
+// AuditEntry является записью журнала действий над сущностью
+type AuditEntry struct {
+	Id         int64
+	Entity     string
+	EntityId   int64
+	Action     string
+	ActorLogin string
+	Details    map[string]interface{}
+	CreatedAt  time.Time
+}
@@ -0,0 +1,67 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	// BackupFormat идентифицирует архив резервной копии сервиса
+	BackupFormat = "service_admin_contractor.backup"
+	// BackupVersion является версией формата архива. Увеличивается при несовместимом изменении формата строк;
+	// состав таблиц перечисляется в заголовке архива.
+	BackupVersion = 1
+)
+
+// BackupTables содержит таблицы резервной копии в порядке восстановления (сначала родительские)
+var BackupTables = []string{
+	"contractors_contractor",
+	"contractors_contractor_employee",
+	"contractors_credentials",
+	"contractors_contractor_address",
+	"contractors_contractor_contact",
+	"contractors_contractor_phone",
+	"contractors_contractor_contract",
+	"contractors_contractor_bank_account",
+	"contractors_contractor_document",
+	"contractors_tag",
+	"contractors_contractor_tag",
+}
+
+// BackupHeader является первой строкой архива
+type BackupHeader struct {
+	Format    string    `json:"format"`
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"createdAt"`
+	Tables    []string  `json:"tables"`
+}
+
+// BackupRecord является строкой таблицы в архиве. Row содержит все колонки строки как JSON-объект.
+type BackupRecord struct {
+	Table string          `json:"table"`
+	Row   json.RawMessage `json:"row"`
+}
+
+// BackupTrailer является последней строкой архива. По нему при восстановлении проверяется,
+// что архив не обрезан.
+type BackupTrailer struct {
+	Counts map[string]int64 `json:"counts"`
+}
+
+// BackupLine является любой строкой архива: заголовком, записью или завершением
+type BackupLine struct {
+	Header  *BackupHeader  `json:"header,omitempty"`
+	Record  *BackupRecord  `json:"record,omitempty"`
+	Trailer *BackupTrailer `json:"trailer,omitempty"`
+}
+
+// IsBackupTable проверяет, входит ли таблица в резервную копию
+func IsBackupTable(table string) bool {
+	for _, t := range BackupTables {
+		if t == table {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// BankAccount является банковским счетом контрагента. Iban хранится в электронном формате: без пробелов,
+// в верхнем регистре. У контрагента может быть не более одного основного счета.
+type BankAccount struct {
+	Id           int64
+	ContractorId int64
+	Iban         string
+	Bik          string
+	BankName     string
+	Currency     string
+	Primary      bool
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (a BankAccount) ReadModel(reader DbModelReader) (interface{}, error) {
+	tmp := BankAccount{}
+	err := reader.Scan(&tmp.Id, &tmp.ContractorId, &tmp.Iban, &tmp.Bik, &tmp.BankName, &tmp.Currency, &tmp.Primary,
+		&tmp.CreatedAt, &tmp.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tmp, nil
+}
+
+// ibanVisibleSuffix определяет количество последних символов номера счета, видимых в маскированном IBAN
+const ibanVisibleSuffix = 4
+
+// MaskedIban возвращает IBAN, в котором скрыты все символы, кроме кода страны, контрольных цифр и последних
+// четырех символов номера счета, например KZ86************1234
+func (a BankAccount) MaskedIban() string {
+	if len(a.Iban) <= 4+ibanVisibleSuffix {
+		return a.Iban
+	}
+
+	return a.Iban[:4] + strings.Repeat("*", len(a.Iban)-4-ibanVisibleSuffix) + a.Iban[len(a.Iban)-ibanVisibleSuffix:]
+}
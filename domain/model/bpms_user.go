@@ -18,6 +18,19 @@ func (u UserInfo) Roles() []RoleCode {
 	return u.roles
 }
 
+// HasAnyRole возвращает true, если у пользователя есть хотя бы одна из ролей roles
+func (u UserInfo) HasAnyRole(roles []RoleCode) bool {
+	for _, role := range u.roles {
+		for _, r := range roles {
+			if role == r {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 func (u UserInfo) BasicAuth() string {
 	return u.basicAuth
 }
@@ -0,0 +1,100 @@
+package model
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type ChangeEntity string
+
+const (
+	ChangeEntityContractor ChangeEntity = "CONTRACTOR"
+	ChangeEntityEmployee   ChangeEntity = "EMPLOYEE"
+	// ChangeEntityAddress и ChangeEntityContact журналируются без текущего состояния записи.
+	// Изменение телефонов журналируется как изменение контактного лица или контрагента.
+	ChangeEntityAddress ChangeEntity = "ADDRESS"
+	ChangeEntityContact ChangeEntity = "CONTACT"
+)
+
+type ChangeOperation string
+
+const (
+	ChangeOperationInserted ChangeOperation = "INSERTED"
+	ChangeOperationUpdated  ChangeOperation = "UPDATED"
+	ChangeOperationDeleted  ChangeOperation = "DELETED"
+)
+
+// Change является записью журнала изменений контрагентов и сотрудников.
+// Contractor и Employee содержат текущее состояние записи и заполняются только для вставок и изменений.
+type Change struct {
+	Token        ChangeToken
+	Entity       ChangeEntity
+	EntityId     int64
+	ContractorId int64
+	Operation    ChangeOperation
+	ChangedAt    time.Time
+	Contractor   *Contractor
+	Employee     *Employee
+}
+
+func (c Change) ReadModel(reader DbModelReader) (interface{}, error) {
+	tmp := Change{}
+	var contractorId *int64
+	err := reader.Scan(&tmp.Token.TxId, &tmp.Token.Id, &tmp.Entity, &tmp.EntityId, &contractorId, &tmp.Operation,
+		&tmp.ChangedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if contractorId != nil {
+		tmp.ContractorId = *contractorId
+	}
+
+	return &tmp, nil
+}
+
+// ChangeToken указывает позицию в журнале изменений.
+// Журнал упорядочен по ИД транзакции и ИД записи, а читаются из него только завершенные транзакции,
+// поэтому продолжение чтения с токена не пропускает изменений.
+type ChangeToken struct {
+	TxId int64
+	Id   int64
+}
+
+// String кодирует токен в непрозрачную строку для передачи клиенту
+func (t ChangeToken) String() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d.%d", t.TxId, t.Id)))
+}
+
+// After возвращает true, если токен указывает на позицию журнала после other
+func (t ChangeToken) After(other ChangeToken) bool {
+	return t.TxId > other.TxId || (t.TxId == other.TxId && t.Id > other.Id)
+}
+
+// ParseChangeToken разбирает токен, полученный из ChangeToken.String. Пустая строка означает начало журнала.
+func ParseChangeToken(value string) (ChangeToken, error) {
+	if value == "" {
+		return ChangeToken{}, nil
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return ChangeToken{}, errors.New("некорректный токен продолжения")
+	}
+
+	token := ChangeToken{}
+	if _, err = fmt.Sscanf(string(decoded), "%d.%d", &token.TxId, &token.Id); err != nil {
+		return ChangeToken{}, errors.New("некорректный токен продолжения")
+	}
+
+	return token, nil
+}
+
+// ChangeFeed является страницей журнала изменений. Next передается в следующий запрос для продолжения чтения.
+type ChangeFeed struct {
+	Changes []Change
+	Next    ChangeToken
+	HasMore bool
+}
@@ -0,0 +1,97 @@
+package model
+
+import "time"
+
+type AddressType string
+
+const (
+	AddressTypeLegal  AddressType = "LEGAL"
+	AddressTypeActual AddressType = "ACTUAL"
+	AddressTypePostal AddressType = "POSTAL"
+)
+
+// Address является адресом контрагента. У контрагента может быть только один адрес каждого типа.
+type Address struct {
+	Id           int64
+	ContractorId int64
+	Type         AddressType
+	Country      *string
+	Region       *string
+	City         string
+	Street       string
+	PostalCode   *string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (a Address) ReadModel(reader DbModelReader) (interface{}, error) {
+	tmp := Address{}
+	err := reader.Scan(&tmp.Id, &tmp.ContractorId, &tmp.Type, &tmp.Country, &tmp.Region, &tmp.City, &tmp.Street,
+		&tmp.PostalCode, &tmp.CreatedAt, &tmp.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tmp, nil
+}
+
+type PhoneType string
+
+const (
+	PhoneTypeMobile PhoneType = "MOBILE"
+	PhoneTypeWork   PhoneType = "WORK"
+	PhoneTypeHome   PhoneType = "HOME"
+	PhoneTypeFax    PhoneType = "FAX"
+	PhoneTypeOther  PhoneType = "OTHER"
+)
+
+// Phone является телефоном в формате E.164. ContactId равен nil для телефона самого контрагента.
+type Phone struct {
+	Id           int64
+	ContractorId int64
+	ContactId    *int64
+	Type         PhoneType
+	Number       string
+	Extension    *string
+}
+
+func (p Phone) ReadModel(reader DbModelReader) (interface{}, error) {
+	tmp := Phone{}
+	err := reader.Scan(&tmp.Id, &tmp.ContractorId, &tmp.ContactId, &tmp.Type, &tmp.Number, &tmp.Extension)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tmp, nil
+}
+
+// Contact является контактным лицом контрагента
+type Contact struct {
+	Id           int64
+	ContractorId int64
+	FullName     string
+	Position     *string
+	Email        *string
+	Comment      *string
+	Phones       []Phone
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (c Contact) ReadModel(reader DbModelReader) (interface{}, error) {
+	tmp := Contact{}
+	err := reader.Scan(&tmp.Id, &tmp.ContractorId, &tmp.FullName, &tmp.Position, &tmp.Email, &tmp.Comment,
+		&tmp.CreatedAt, &tmp.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tmp, nil
+}
+
+// ContractorContacts содержит адреса, телефоны и контактные лица контрагента
+type ContractorContacts struct {
+	Addresses []Address
+	Phones    []Phone
+	Contacts  []Contact
+}
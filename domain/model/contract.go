@@ -0,0 +1,54 @@
+package model
+
+import "time"
+
+type ContractStatus string
+
+const (
+	ContractStatusDraft      ContractStatus = "DRAFT"
+	ContractStatusActive     ContractStatus = "ACTIVE"
+	ContractStatusSuspended  ContractStatus = "SUSPENDED"
+	ContractStatusTerminated ContractStatus = "TERMINATED"
+)
+
+// Contract является договором с контрагентом. Договор действует, если он в статусе ACTIVE и текущая дата
+// попадает в период StartDate - EndDate; EndDate равный nil означает бессрочный договор.
+// Сумма хранится строкой, чтобы не терять точность numeric.
+type Contract struct {
+	Id             int64
+	ContractorId   int64
+	Number         string
+	StartDate      time.Time
+	EndDate        *time.Time
+	Amount         string
+	Currency       string
+	Status         ContractStatus
+	ExpiryWarnedAt *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+func (c Contract) ReadModel(reader DbModelReader) (interface{}, error) {
+	tmp := Contract{}
+	err := reader.Scan(&tmp.Id, &tmp.ContractorId, &tmp.Number, &tmp.StartDate, &tmp.EndDate, &tmp.Amount,
+		&tmp.Currency, &tmp.Status, &tmp.ExpiryWarnedAt, &tmp.CreatedAt, &tmp.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tmp, nil
+}
+
+// IsActiveAt возвращает true, если договор действует в день date
+func (c Contract) IsActiveAt(date time.Time) bool {
+	if c.Status != ContractStatusActive || date.Before(c.StartDate) {
+		return false
+	}
+
+	return c.EndDate == nil || !date.After(*c.EndDate)
+}
+
+// Today возвращает текущую дату в UTC без времени, с которой сравниваются даты договоров
+func Today() time.Time {
+	return time.Now().UTC().Truncate(24 * time.Hour)
+}
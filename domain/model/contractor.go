@@ -3,7 +3,7 @@ package model
 import (
 	"fmt"
 	"golang.org/x/crypto/bcrypt"
-	"strconv"
+	"strings"
 	"time"
 )
 
@@ -13,6 +13,7 @@ const (
 
 type Contractor struct {
 	Id            int64
+	ParentId      *int64
 	Resident      bool
 	Bin           *string
 	Name          *string
@@ -22,54 +23,46 @@ type Contractor struct {
 	AgentPosition *string
 	BlockDate     *time.Time
 	Status        ContractorStatus
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
 	Employees     []Employee
+	Tags          []Tag
+	// Country содержит код страны ISO 3166-1 alpha-2; у резидентов всегда CountryKazakhstan
+	Country *string
+	// ForeignTaxId и RegistrationAddress заполняются для нерезидентов
+	ForeignTaxId        *string
+	RegistrationAddress *string
+	// Contacts заполняется только при получении контрагента по ИД
+	Contacts *ContractorContacts
 }
 
 func (c Contractor) ReadModel(reader DbModelReader) (interface{}, error) {
 	tmp := Contractor{}
-	var employees []interface{}
-	err := reader.Scan(&tmp.Id, &tmp.Resident, &tmp.Bin, &tmp.Name, &tmp.Email, &tmp.BlockDate, &tmp.Status, &employees,
-		&tmp.AgentName, &tmp.AgentPosition)
+	err := reader.Scan(&tmp.Id, &tmp.Resident, &tmp.Bin, &tmp.Name, &tmp.Email, &tmp.BlockDate, &tmp.Status,
+		&tmp.AgentName, &tmp.AgentPosition, &tmp.CreatedAt, &tmp.UpdatedAt, &tmp.ParentId, &tmp.Country,
+		&tmp.ForeignTaxId, &tmp.RegistrationAddress)
 	if err != nil {
 		return nil, err
 	}
 
-	var employeeArray []Employee
-	if len(employees) > 0 {
-		for _, l := range employees {
-			currentEmployee := l.(map[string]interface{})
-
-			currentEmployeeIdStr := fmt.Sprintf("%.0f", currentEmployee["id"].(float64))
-			currentContractorEmployeeIdStr := fmt.Sprintf("%.0f", currentEmployee["contractor_id"].(float64))
-			currentEmployeeId, err := strconv.Atoi(currentEmployeeIdStr)
-			if err != nil {
-				return nil, err
-			}
-			currentContractorEmployeeId, err := strconv.Atoi(currentContractorEmployeeIdStr)
-			if err != nil {
-				return nil, err
-			}
-
-			var blockDate time.Time
-			if currentEmployee["block_date"] != nil {
-				blockDate, err = time.Parse(time.RFC3339, currentEmployee["block_date"].(string))
-				if err != nil {
-					return nil, err
-				}
-			}
-
-			employeeArray = append(employeeArray, Employee{
-				Id:           int64(currentEmployeeId),
-				ContractorId: int64(currentContractorEmployeeId),
-				Email:        currentEmployee["email"].(string),
-				FullName:     currentEmployee["full_name"].(string),
-				Position:     currentEmployee["position"].(string),
-				BlockDate:    &blockDate,
-				Status:       EmployeeStatus(currentEmployee["status"].(string)),
-			})
-		}
+	return &tmp, nil
+}
+
+// ContractorTreeNode является контрагентом в иерархии; Depth равен 0 для корня
+type ContractorTreeNode struct {
+	Contractor Contractor
+	Depth      int
+}
+
+func (n ContractorTreeNode) ReadModel(reader DbModelReader) (interface{}, error) {
+	tmp := ContractorTreeNode{}
+	c := &tmp.Contractor
+	err := reader.Scan(&c.Id, &c.Resident, &c.Bin, &c.Name, &c.Email, &c.BlockDate, &c.Status,
+		&c.AgentName, &c.AgentPosition, &c.CreatedAt, &c.UpdatedAt, &c.ParentId, &c.Country, &c.ForeignTaxId,
+		&c.RegistrationAddress, &tmp.Depth)
+	if err != nil {
+		return nil, err
 	}
-	tmp.Employees = employeeArray
 
 	return &tmp, nil
 }
@@ -82,6 +75,103 @@ type Employee struct {
 	Position     string
 	BlockDate    *time.Time
 	Status       EmployeeStatus
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (e Employee) ReadModel(reader DbModelReader) (interface{}, error) {
+	tmp := Employee{}
+	var fullName, position, status *string
+	err := reader.Scan(&tmp.Id, &tmp.ContractorId, &tmp.Email, &fullName, &position, &tmp.BlockDate, &status,
+		&tmp.CreatedAt, &tmp.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if fullName != nil {
+		tmp.FullName = *fullName
+	}
+	if position != nil {
+		tmp.Position = *position
+	}
+	if status != nil {
+		tmp.Status = EmployeeStatus(*status)
+	}
+
+	return &tmp, nil
+}
+
+// NormalizeEmail приводит email к виду, в котором он хранится в базе: без пробелов по краям и в нижнем регистре
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// EmployeeEmailScope определяет, в каких пределах email сотрудника должен быть уникален
+type EmployeeEmailScope string
+
+const (
+	// EmployeeEmailScopeContractor требует уникальности email-а среди сотрудников одного контрагента
+	EmployeeEmailScopeContractor EmployeeEmailScope = "contractor"
+	// EmployeeEmailScopeGlobal требует уникальности email-а среди сотрудников всех контрагентов
+	EmployeeEmailScopeGlobal EmployeeEmailScope = "global"
+)
+
+func ParseEmployeeEmailScope(value string) (EmployeeEmailScope, error) {
+	switch scope := EmployeeEmailScope(strings.ToLower(value)); scope {
+	case EmployeeEmailScopeContractor, EmployeeEmailScopeGlobal:
+		return scope, nil
+	default:
+		return "", fmt.Errorf("неизвестная область уникальности email-а сотрудника '%s'", value)
+	}
+}
+
+// ContractorEmployeeRow является строкой выгрузки контрагентов, развернутой по сотрудникам.
+// Employee равен nil для контрагента без сотрудников.
+type ContractorEmployeeRow struct {
+	Contractor Contractor
+	Employee   *Employee
+}
+
+func (r ContractorEmployeeRow) ReadModel(reader DbModelReader) (interface{}, error) {
+	tmp := ContractorEmployeeRow{}
+	c := &tmp.Contractor
+	var employeeId, employeeContractorId *int64
+	var email, fullName, position, status *string
+	var blockDate, createdAt, updatedAt *time.Time
+	err := reader.Scan(&c.Id, &c.Resident, &c.Bin, &c.Name, &c.Email, &c.BlockDate, &c.Status,
+		&c.AgentName, &c.AgentPosition, &c.CreatedAt, &c.UpdatedAt, &c.ParentId, &c.Country, &c.ForeignTaxId,
+		&c.RegistrationAddress,
+		&employeeId, &employeeContractorId, &email, &fullName, &position, &blockDate, &status,
+		&createdAt, &updatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if employeeId == nil {
+		return &tmp, nil
+	}
+
+	tmp.Employee = &Employee{
+		Id:           *employeeId,
+		ContractorId: c.Id,
+		BlockDate:    blockDate,
+		CreatedAt:    *createdAt,
+		UpdatedAt:    *updatedAt,
+	}
+	if email != nil {
+		tmp.Employee.Email = *email
+	}
+	if fullName != nil {
+		tmp.Employee.FullName = *fullName
+	}
+	if position != nil {
+		tmp.Employee.Position = *position
+	}
+	if status != nil {
+		tmp.Employee.Status = EmployeeStatus(*status)
+	}
+
+	return &tmp, nil
 }
 
 type ContractorStatus string
@@ -100,11 +190,135 @@ const (
 
 type ContractorSearchParameters struct {
 	Pagination Pagination
+	Facets     []ContractorFacet
+	Filter     RsqlNode
+
+	// WithEmployees определяет, загружать ли сотрудников найденных контрагентов
+	WithEmployees bool
+	// Deleted определяет, искать ли среди удаленных контрагентов вместо действующих
+	Deleted bool
 
 	Bin    *string
 	Name   *string
 	Email  *string
 	Status *ContractorStatus
+	// Countries отбирает контрагентов из любой из перечисленных стран
+	Countries []string
+	// TagIds отбирает контрагентов, которым назначены все перечисленные теги
+	TagIds []int64
+}
+
+// ContractorRsqlFields содержит поля контрагента, доступные в параметре `filter`
+var ContractorRsqlFields = RsqlFields{
+	"id":            {Type: RsqlFieldInteger},
+	"parentId":      {Type: RsqlFieldInteger},
+	"resident":      {Type: RsqlFieldBoolean},
+	"bin":           {Type: RsqlFieldString},
+	"country":       {Type: RsqlFieldString},
+	"foreignTaxId":  {Type: RsqlFieldString},
+	"name":          {Type: RsqlFieldString},
+	"email":         {Type: RsqlFieldString},
+	"agentName":     {Type: RsqlFieldString},
+	"agentPosition": {Type: RsqlFieldString},
+	"blockDate":     {Type: RsqlFieldDate},
+	"createdAt":     {Type: RsqlFieldDate},
+	"updatedAt":     {Type: RsqlFieldDate},
+	"status": {
+		Type:   RsqlFieldString,
+		Values: []string{string(ContractorStatusActive), string(ContractorStatusBlock)},
+	},
+}
+
+// ContractorFacet определяет признак, по которому считаются агрегаты при поиске контрагентов
+type ContractorFacet string
+
+const (
+	ContractorFacetStatus    ContractorFacet = "status"
+	ContractorFacetResident  ContractorFacet = "resident"
+	ContractorFacetEmployees ContractorFacet = "employees"
+)
+
+var ContractorFacets = []ContractorFacet{
+	ContractorFacetStatus,
+	ContractorFacetResident,
+	ContractorFacetEmployees,
+}
+
+// ContractorImportRow является строкой файла импорта контрагентов.
+// Row содержит номер строки в файле, Errors - найденные в строке ошибки.
+type ContractorImportRow struct {
+	Row        int
+	Contractor *Contractor
+	Errors     []ValidationError
+}
+
+// ContractorImportReport является результатом импорта контрагентов
+type ContractorImportReport struct {
+	DryRun  bool
+	Created int
+	Failed  int
+	Rows    []ContractorImportRow
+}
+
+type EmployeeImportResult string
+
+const (
+	EmployeeImportCreated EmployeeImportResult = "CREATED"
+	EmployeeImportUpdated EmployeeImportResult = "UPDATED"
+	EmployeeImportFailed  EmployeeImportResult = "FAILED"
+)
+
+// EmployeeImportRow является строкой массовой загрузки сотрудников контрагента
+type EmployeeImportRow struct {
+	Row      int
+	Employee *Employee
+	Errors   []ValidationError
+	Result   EmployeeImportResult
+}
+
+// EmployeeImportReport является результатом массовой загрузки сотрудников контрагента
+type EmployeeImportReport struct {
+	Created int
+	Updated int
+	Failed  int
+	Rows    []EmployeeImportRow
+}
+
+type ContractorBulkAction string
+
+const (
+	ContractorBulkBlock   ContractorBulkAction = "block"
+	ContractorBulkUnblock ContractorBulkAction = "unblock"
+	ContractorBulkDelete  ContractorBulkAction = "delete"
+	ContractorBulkRestore ContractorBulkAction = "restore"
+)
+
+// ContractorBulkRequest описывает массовое действие над контрагентами, заданными списком Ids или фильтром Filter.
+// При Atomic все изменения выполняются одной транзакцией, иначе каждый контрагент обрабатывается отдельно.
+type ContractorBulkRequest struct {
+	Action ContractorBulkAction
+	Ids    []int64
+	Filter *ContractorSearchParameters
+	Atomic bool
+	// Propagate распространяет разблокировку на всех потомков контрагентов, блокировка распространяется всегда
+	Propagate bool
+}
+
+// ContractorBulkMaxItems ограничивает количество контрагентов одного массового действия как для списка Ids,
+// так и для контрагентов, отобранных фильтром
+const ContractorBulkMaxItems = 1000
+
+type ContractorBulkItemResult struct {
+	Id    int64
+	Error error
+}
+
+type ContractorBulkResult struct {
+	Action    ContractorBulkAction
+	Atomic    bool
+	Succeeded int
+	Failed    int
+	Items     []ContractorBulkItemResult
 }
 
 type Credentials struct {
@@ -0,0 +1,254 @@
+package model
+
+// Countries содержит страны справочника ISO 3166-1 с русскими названиями, упорядоченные по коду alpha-2
+var Countries = []Country{
+	{Code: "AD", Alpha3: "AND", Numeric: "020", Name: "Андорра"},
+	{Code: "AE", Alpha3: "ARE", Numeric: "784", Name: "Объединенные Арабские Эмираты"},
+	{Code: "AF", Alpha3: "AFG", Numeric: "004", Name: "Афганистан"},
+	{Code: "AG", Alpha3: "ATG", Numeric: "028", Name: "Антигуа и Барбуда"},
+	{Code: "AI", Alpha3: "AIA", Numeric: "660", Name: "Ангвилла"},
+	{Code: "AL", Alpha3: "ALB", Numeric: "008", Name: "Албания"},
+	{Code: "AM", Alpha3: "ARM", Numeric: "051", Name: "Армения"},
+	{Code: "AO", Alpha3: "AGO", Numeric: "024", Name: "Ангола"},
+	{Code: "AQ", Alpha3: "ATA", Numeric: "010", Name: "Антарктика"},
+	{Code: "AR", Alpha3: "ARG", Numeric: "032", Name: "Аргентина"},
+	{Code: "AS", Alpha3: "ASM", Numeric: "016", Name: "Американские Самоа"},
+	{Code: "AT", Alpha3: "AUT", Numeric: "040", Name: "Австрия"},
+	{Code: "AU", Alpha3: "AUS", Numeric: "036", Name: "Австралия"},
+	{Code: "AW", Alpha3: "ABW", Numeric: "533", Name: "Аруба"},
+	{Code: "AX", Alpha3: "ALA", Numeric: "248", Name: "Аландские острова"},
+	{Code: "AZ", Alpha3: "AZE", Numeric: "031", Name: "Азербайджан"},
+	{Code: "BA", Alpha3: "BIH", Numeric: "070", Name: "Босния и Герцеговина"},
+	{Code: "BB", Alpha3: "BRB", Numeric: "052", Name: "Барбадос"},
+	{Code: "BD", Alpha3: "BGD", Numeric: "050", Name: "Бангладеш"},
+	{Code: "BE", Alpha3: "BEL", Numeric: "056", Name: "Бельгия"},
+	{Code: "BF", Alpha3: "BFA", Numeric: "854", Name: "Буркина-Фасо"},
+	{Code: "BG", Alpha3: "BGR", Numeric: "100", Name: "Болгария"},
+	{Code: "BH", Alpha3: "BHR", Numeric: "048", Name: "Бахрейн"},
+	{Code: "BI", Alpha3: "BDI", Numeric: "108", Name: "Бурунди"},
+	{Code: "BJ", Alpha3: "BEN", Numeric: "204", Name: "Бенин"},
+	{Code: "BL", Alpha3: "BLM", Numeric: "652", Name: "Сен-Бартельми"},
+	{Code: "BM", Alpha3: "BMU", Numeric: "060", Name: "Бермуды"},
+	{Code: "BN", Alpha3: "BRN", Numeric: "096", Name: "Бруней Даруссалам"},
+	{Code: "BO", Alpha3: "BOL", Numeric: "068", Name: "Боливия"},
+	{Code: "BQ", Alpha3: "BES", Numeric: "535", Name: "Бонайре, Синт-Эстатиус и Саба"},
+	{Code: "BR", Alpha3: "BRA", Numeric: "076", Name: "Бразилия"},
+	{Code: "BS", Alpha3: "BHS", Numeric: "044", Name: "Багамы"},
+	{Code: "BT", Alpha3: "BTN", Numeric: "064", Name: "Бутан"},
+	{Code: "BV", Alpha3: "BVT", Numeric: "074", Name: "Остров Буве"},
+	{Code: "BW", Alpha3: "BWA", Numeric: "072", Name: "Ботсвана"},
+	{Code: "BY", Alpha3: "BLR", Numeric: "112", Name: "Беларусь"},
+	{Code: "BZ", Alpha3: "BLZ", Numeric: "084", Name: "Белиз"},
+	{Code: "CA", Alpha3: "CAN", Numeric: "124", Name: "Канада"},
+	{Code: "CC", Alpha3: "CCK", Numeric: "166", Name: "Кокосовые острова"},
+	{Code: "CD", Alpha3: "COD", Numeric: "180", Name: "Демократическая Республика Конго"},
+	{Code: "CF", Alpha3: "CAF", Numeric: "140", Name: "Центрально-африканская республика"},
+	{Code: "CG", Alpha3: "COG", Numeric: "178", Name: "Конго"},
+	{Code: "CH", Alpha3: "CHE", Numeric: "756", Name: "Швейцария"},
+	{Code: "CI", Alpha3: "CIV", Numeric: "384", Name: "Кот-д'Ивуар"},
+	{Code: "CK", Alpha3: "COK", Numeric: "184", Name: "Острова Кука"},
+	{Code: "CL", Alpha3: "CHL", Numeric: "152", Name: "Чили"},
+	{Code: "CM", Alpha3: "CMR", Numeric: "120", Name: "Камерун"},
+	{Code: "CN", Alpha3: "CHN", Numeric: "156", Name: "Китай"},
+	{Code: "CO", Alpha3: "COL", Numeric: "170", Name: "Колумбия"},
+	{Code: "CR", Alpha3: "CRI", Numeric: "188", Name: "Коста-Рика"},
+	{Code: "CU", Alpha3: "CUB", Numeric: "192", Name: "Куба"},
+	{Code: "CV", Alpha3: "CPV", Numeric: "132", Name: "Кабо-Верде"},
+	{Code: "CW", Alpha3: "CUW", Numeric: "531", Name: "Кюрасао"},
+	{Code: "CX", Alpha3: "CXR", Numeric: "162", Name: "Остров Рождества"},
+	{Code: "CY", Alpha3: "CYP", Numeric: "196", Name: "Кипр"},
+	{Code: "CZ", Alpha3: "CZE", Numeric: "203", Name: "Чехия"},
+	{Code: "DE", Alpha3: "DEU", Numeric: "276", Name: "Германия"},
+	{Code: "DJ", Alpha3: "DJI", Numeric: "262", Name: "Джибути"},
+	{Code: "DK", Alpha3: "DNK", Numeric: "208", Name: "Дания"},
+	{Code: "DM", Alpha3: "DMA", Numeric: "212", Name: "Доминика"},
+	{Code: "DO", Alpha3: "DOM", Numeric: "214", Name: "Доминиканская республика"},
+	{Code: "DZ", Alpha3: "DZA", Numeric: "012", Name: "Алжир"},
+	{Code: "EC", Alpha3: "ECU", Numeric: "218", Name: "Эквадор"},
+	{Code: "EE", Alpha3: "EST", Numeric: "233", Name: "Эстония"},
+	{Code: "EG", Alpha3: "EGY", Numeric: "818", Name: "Египет"},
+	{Code: "EH", Alpha3: "ESH", Numeric: "732", Name: "Западная Сахара"},
+	{Code: "ER", Alpha3: "ERI", Numeric: "232", Name: "Эритрея"},
+	{Code: "ES", Alpha3: "ESP", Numeric: "724", Name: "Испания"},
+	{Code: "ET", Alpha3: "ETH", Numeric: "231", Name: "Эфиопия"},
+	{Code: "FI", Alpha3: "FIN", Numeric: "246", Name: "Финляндия"},
+	{Code: "FJ", Alpha3: "FJI", Numeric: "242", Name: "Фиджи"},
+	{Code: "FK", Alpha3: "FLK", Numeric: "238", Name: "Фолклендские (Мальвинские) острова"},
+	{Code: "FM", Alpha3: "FSM", Numeric: "583", Name: "Федеративные Штаты Микронезии"},
+	{Code: "FO", Alpha3: "FRO", Numeric: "234", Name: "Фарерские острова"},
+	{Code: "FR", Alpha3: "FRA", Numeric: "250", Name: "Франция"},
+	{Code: "GA", Alpha3: "GAB", Numeric: "266", Name: "Габон"},
+	{Code: "GB", Alpha3: "GBR", Numeric: "826", Name: "Соединенное Королевство"},
+	{Code: "GD", Alpha3: "GRD", Numeric: "308", Name: "Гренада"},
+	{Code: "GE", Alpha3: "GEO", Numeric: "268", Name: "Грузия"},
+	{Code: "GF", Alpha3: "GUF", Numeric: "254", Name: "Французская Гвиана"},
+	{Code: "GG", Alpha3: "GGY", Numeric: "831", Name: "Гернси"},
+	{Code: "GH", Alpha3: "GHA", Numeric: "288", Name: "Гана"},
+	{Code: "GI", Alpha3: "GIB", Numeric: "292", Name: "Гибралтар"},
+	{Code: "GL", Alpha3: "GRL", Numeric: "304", Name: "Гренландия"},
+	{Code: "GM", Alpha3: "GMB", Numeric: "270", Name: "Гамбия"},
+	{Code: "GN", Alpha3: "GIN", Numeric: "324", Name: "Гвинея"},
+	{Code: "GP", Alpha3: "GLP", Numeric: "312", Name: "Гваделупа"},
+	{Code: "GQ", Alpha3: "GNQ", Numeric: "226", Name: "Экваториальная Гвинея"},
+	{Code: "GR", Alpha3: "GRC", Numeric: "300", Name: "Греция"},
+	{Code: "GS", Alpha3: "SGS", Numeric: "239", Name: "Южная Джорджия и Южные Сандвичевы острова"},
+	{Code: "GT", Alpha3: "GTM", Numeric: "320", Name: "Гватемала"},
+	{Code: "GU", Alpha3: "GUM", Numeric: "316", Name: "Гуам"},
+	{Code: "GW", Alpha3: "GNB", Numeric: "624", Name: "Гвинея-Бисау"},
+	{Code: "GY", Alpha3: "GUY", Numeric: "328", Name: "Гайана"},
+	{Code: "HK", Alpha3: "HKG", Numeric: "344", Name: "Гонконг"},
+	{Code: "HM", Alpha3: "HMD", Numeric: "334", Name: "Остров Херд и острова МакДональд"},
+	{Code: "HN", Alpha3: "HND", Numeric: "340", Name: "Гондурас"},
+	{Code: "HR", Alpha3: "HRV", Numeric: "191", Name: "Хорватия"},
+	{Code: "HT", Alpha3: "HTI", Numeric: "332", Name: "Гаити"},
+	{Code: "HU", Alpha3: "HUN", Numeric: "348", Name: "Венгрия"},
+	{Code: "ID", Alpha3: "IDN", Numeric: "360", Name: "Индонезия"},
+	{Code: "IE", Alpha3: "IRL", Numeric: "372", Name: "Ирландия"},
+	{Code: "IL", Alpha3: "ISR", Numeric: "376", Name: "Израиль"},
+	{Code: "IM", Alpha3: "IMN", Numeric: "833", Name: "Остров Мэн"},
+	{Code: "IN", Alpha3: "IND", Numeric: "356", Name: "Индия"},
+	{Code: "IO", Alpha3: "IOT", Numeric: "086", Name: "Британская территория Индийского океана"},
+	{Code: "IQ", Alpha3: "IRQ", Numeric: "368", Name: "Ирак"},
+	{Code: "IR", Alpha3: "IRN", Numeric: "364", Name: "Иран"},
+	{Code: "IS", Alpha3: "ISL", Numeric: "352", Name: "Исландия"},
+	{Code: "IT", Alpha3: "ITA", Numeric: "380", Name: "Италия"},
+	{Code: "JE", Alpha3: "JEY", Numeric: "832", Name: "Джерси"},
+	{Code: "JM", Alpha3: "JAM", Numeric: "388", Name: "Ямайка"},
+	{Code: "JO", Alpha3: "JOR", Numeric: "400", Name: "Иордания"},
+	{Code: "JP", Alpha3: "JPN", Numeric: "392", Name: "Япония"},
+	{Code: "KE", Alpha3: "KEN", Numeric: "404", Name: "Кения"},
+	{Code: "KG", Alpha3: "KGZ", Numeric: "417", Name: "Киргизия"},
+	{Code: "KH", Alpha3: "KHM", Numeric: "116", Name: "Камбоджа"},
+	{Code: "KI", Alpha3: "KIR", Numeric: "296", Name: "Кирибати"},
+	{Code: "KM", Alpha3: "COM", Numeric: "174", Name: "Коморы"},
+	{Code: "KN", Alpha3: "KNA", Numeric: "659", Name: "Сент-Китс и Невис"},
+	{Code: "KP", Alpha3: "PRK", Numeric: "408", Name: "Корейская Народно-Демократическая Республика"},
+	{Code: "KR", Alpha3: "KOR", Numeric: "410", Name: "Республика Корея"},
+	{Code: "KW", Alpha3: "KWT", Numeric: "414", Name: "Кувейт"},
+	{Code: "KY", Alpha3: "CYM", Numeric: "136", Name: "Каймановы острова"},
+	{Code: "KZ", Alpha3: "KAZ", Numeric: "398", Name: "Казахстан"},
+	{Code: "LA", Alpha3: "LAO", Numeric: "418", Name: "Лаосская Народно-Демократическая Республика"},
+	{Code: "LB", Alpha3: "LBN", Numeric: "422", Name: "Ливан"},
+	{Code: "LC", Alpha3: "LCA", Numeric: "662", Name: "Сент-Люсия"},
+	{Code: "LI", Alpha3: "LIE", Numeric: "438", Name: "Лихтенштейн"},
+	{Code: "LK", Alpha3: "LKA", Numeric: "144", Name: "Шри-Ланка"},
+	{Code: "LR", Alpha3: "LBR", Numeric: "430", Name: "Либерия"},
+	{Code: "LS", Alpha3: "LSO", Numeric: "426", Name: "Лесото"},
+	{Code: "LT", Alpha3: "LTU", Numeric: "440", Name: "Литва"},
+	{Code: "LU", Alpha3: "LUX", Numeric: "442", Name: "Люксембург"},
+	{Code: "LV", Alpha3: "LVA", Numeric: "428", Name: "Латвия"},
+	{Code: "LY", Alpha3: "LBY", Numeric: "434", Name: "Ливия"},
+	{Code: "MA", Alpha3: "MAR", Numeric: "504", Name: "Марокко"},
+	{Code: "MC", Alpha3: "MCO", Numeric: "492", Name: "Монако"},
+	{Code: "MD", Alpha3: "MDA", Numeric: "498", Name: "Республика Молдова"},
+	{Code: "ME", Alpha3: "MNE", Numeric: "499", Name: "Черногория"},
+	{Code: "MF", Alpha3: "MAF", Numeric: "663", Name: "Сен-Мартен (Франция)"},
+	{Code: "MG", Alpha3: "MDG", Numeric: "450", Name: "Мадагаскар"},
+	{Code: "MH", Alpha3: "MHL", Numeric: "584", Name: "Маршалловы острова"},
+	{Code: "MK", Alpha3: "MKD", Numeric: "807", Name: "Северная Македония"},
+	{Code: "ML", Alpha3: "MLI", Numeric: "466", Name: "Мали"},
+	{Code: "MM", Alpha3: "MMR", Numeric: "104", Name: "Мьянма"},
+	{Code: "MN", Alpha3: "MNG", Numeric: "496", Name: "Монголия"},
+	{Code: "MO", Alpha3: "MAC", Numeric: "446", Name: "Макао"},
+	{Code: "MP", Alpha3: "MNP", Numeric: "580", Name: "Острова северной Марианы"},
+	{Code: "MQ", Alpha3: "MTQ", Numeric: "474", Name: "Мартиника"},
+	{Code: "MR", Alpha3: "MRT", Numeric: "478", Name: "Мавритания"},
+	{Code: "MS", Alpha3: "MSR", Numeric: "500", Name: "Монтсеррат"},
+	{Code: "MT", Alpha3: "MLT", Numeric: "470", Name: "Мальта"},
+	{Code: "MU", Alpha3: "MUS", Numeric: "480", Name: "Маврикий"},
+	{Code: "MV", Alpha3: "MDV", Numeric: "462", Name: "Мальдивы"},
+	{Code: "MW", Alpha3: "MWI", Numeric: "454", Name: "Малави"},
+	{Code: "MX", Alpha3: "MEX", Numeric: "484", Name: "Мексика"},
+	{Code: "MY", Alpha3: "MYS", Numeric: "458", Name: "Малайзия"},
+	{Code: "MZ", Alpha3: "MOZ", Numeric: "508", Name: "Мозамбик"},
+	{Code: "NA", Alpha3: "NAM", Numeric: "516", Name: "Намибия"},
+	{Code: "NC", Alpha3: "NCL", Numeric: "540", Name: "Новая Каледония"},
+	{Code: "NE", Alpha3: "NER", Numeric: "562", Name: "Нигер"},
+	{Code: "NF", Alpha3: "NFK", Numeric: "574", Name: "Остров Норфолк"},
+	{Code: "NG", Alpha3: "NGA", Numeric: "566", Name: "Нигерия"},
+	{Code: "NI", Alpha3: "NIC", Numeric: "558", Name: "Никарагуа"},
+	{Code: "NL", Alpha3: "NLD", Numeric: "528", Name: "Нидерланды"},
+	{Code: "NO", Alpha3: "NOR", Numeric: "578", Name: "Норвегия"},
+	{Code: "NP", Alpha3: "NPL", Numeric: "524", Name: "Непал"},
+	{Code: "NR", Alpha3: "NRU", Numeric: "520", Name: "Науру"},
+	{Code: "NU", Alpha3: "NIU", Numeric: "570", Name: "Ниуэ"},
+	{Code: "NZ", Alpha3: "NZL", Numeric: "554", Name: "Новая Зеландия"},
+	{Code: "OM", Alpha3: "OMN", Numeric: "512", Name: "Оман"},
+	{Code: "PA", Alpha3: "PAN", Numeric: "591", Name: "Панама"},
+	{Code: "PE", Alpha3: "PER", Numeric: "604", Name: "Перу"},
+	{Code: "PF", Alpha3: "PYF", Numeric: "258", Name: "Французская Полинезия"},
+	{Code: "PG", Alpha3: "PNG", Numeric: "598", Name: "Папуа — Новая Гвинея"},
+	{Code: "PH", Alpha3: "PHL", Numeric: "608", Name: "Филиппины"},
+	{Code: "PK", Alpha3: "PAK", Numeric: "586", Name: "Пакистан"},
+	{Code: "PL", Alpha3: "POL", Numeric: "616", Name: "Польша"},
+	{Code: "PM", Alpha3: "SPM", Numeric: "666", Name: "Сен-Пьер и Микелон"},
+	{Code: "PN", Alpha3: "PCN", Numeric: "612", Name: "Питкэрн"},
+	{Code: "PR", Alpha3: "PRI", Numeric: "630", Name: "Пуэрто-Рико"},
+	{Code: "PS", Alpha3: "PSE", Numeric: "275", Name: "Палестина"},
+	{Code: "PT", Alpha3: "PRT", Numeric: "620", Name: "Португалия"},
+	{Code: "PW", Alpha3: "PLW", Numeric: "585", Name: "Палау"},
+	{Code: "PY", Alpha3: "PRY", Numeric: "600", Name: "Парагвай"},
+	{Code: "QA", Alpha3: "QAT", Numeric: "634", Name: "Катар"},
+	{Code: "RE", Alpha3: "REU", Numeric: "638", Name: "Реюньон"},
+	{Code: "RO", Alpha3: "ROU", Numeric: "642", Name: "Румыния"},
+	{Code: "RS", Alpha3: "SRB", Numeric: "688", Name: "Сербия"},
+	{Code: "RU", Alpha3: "RUS", Numeric: "643", Name: "Российская Федерация"},
+	{Code: "RW", Alpha3: "RWA", Numeric: "646", Name: "Руанда"},
+	{Code: "SA", Alpha3: "SAU", Numeric: "682", Name: "Саудовская Аравия"},
+	{Code: "SB", Alpha3: "SLB", Numeric: "090", Name: "Соломоновы Острова"},
+	{Code: "SC", Alpha3: "SYC", Numeric: "690", Name: "Сейшелы"},
+	{Code: "SD", Alpha3: "SDN", Numeric: "729", Name: "Судан"},
+	{Code: "SE", Alpha3: "SWE", Numeric: "752", Name: "Швеция"},
+	{Code: "SG", Alpha3: "SGP", Numeric: "702", Name: "Сингапур"},
+	{Code: "SH", Alpha3: "SHN", Numeric: "654", Name: "Остров Святой Елены, Остров Вознесения и Тристан-да-Кунья"},
+	{Code: "SI", Alpha3: "SVN", Numeric: "705", Name: "Словения"},
+	{Code: "SJ", Alpha3: "SJM", Numeric: "744", Name: "Шпицберген и Ян-Майен"},
+	{Code: "SK", Alpha3: "SVK", Numeric: "703", Name: "Словакия"},
+	{Code: "SL", Alpha3: "SLE", Numeric: "694", Name: "Сьерра-Леоне"},
+	{Code: "SM", Alpha3: "SMR", Numeric: "674", Name: "Сан-Марино"},
+	{Code: "SN", Alpha3: "SEN", Numeric: "686", Name: "Сенегал"},
+	{Code: "SO", Alpha3: "SOM", Numeric: "706", Name: "Сомали"},
+	{Code: "SR", Alpha3: "SUR", Numeric: "740", Name: "Суринам"},
+	{Code: "SS", Alpha3: "SSD", Numeric: "728", Name: "Южный Судан"},
+	{Code: "ST", Alpha3: "STP", Numeric: "678", Name: "Сан-Томе и Принсипи"},
+	{Code: "SV", Alpha3: "SLV", Numeric: "222", Name: "Сальвадор"},
+	{Code: "SX", Alpha3: "SXM", Numeric: "534", Name: "Синт-Мартен (голландская часть)"},
+	{Code: "SY", Alpha3: "SYR", Numeric: "760", Name: "Сирийская Арабская Республика"},
+	{Code: "SZ", Alpha3: "SWZ", Numeric: "748", Name: "Эсватини"},
+	{Code: "TC", Alpha3: "TCA", Numeric: "796", Name: "Острова Туркс и Каикос"},
+	{Code: "TD", Alpha3: "TCD", Numeric: "148", Name: "Чад"},
+	{Code: "TF", Alpha3: "ATF", Numeric: "260", Name: "Французские южные территории"},
+	{Code: "TG", Alpha3: "TGO", Numeric: "768", Name: "Того"},
+	{Code: "TH", Alpha3: "THA", Numeric: "764", Name: "Таиланд"},
+	{Code: "TJ", Alpha3: "TJK", Numeric: "762", Name: "Таджикистан"},
+	{Code: "TK", Alpha3: "TKL", Numeric: "772", Name: "Токелау"},
+	{Code: "TL", Alpha3: "TLS", Numeric: "626", Name: "Восточный Тимор"},
+	{Code: "TM", Alpha3: "TKM", Numeric: "795", Name: "Туркменистан"},
+	{Code: "TN", Alpha3: "TUN", Numeric: "788", Name: "Тунис"},
+	{Code: "TO", Alpha3: "TON", Numeric: "776", Name: "Тонга"},
+	{Code: "TR", Alpha3: "TUR", Numeric: "792", Name: "Турция"},
+	{Code: "TT", Alpha3: "TTO", Numeric: "780", Name: "Тринидад и Тобаго"},
+	{Code: "TV", Alpha3: "TUV", Numeric: "798", Name: "Тувалу"},
+	{Code: "TW", Alpha3: "TWN", Numeric: "158", Name: "Китайская провинция Тайвань"},
+	{Code: "TZ", Alpha3: "TZA", Numeric: "834", Name: "Танзания"},
+	{Code: "UA", Alpha3: "UKR", Numeric: "804", Name: "Украина"},
+	{Code: "UG", Alpha3: "UGA", Numeric: "800", Name: "Уганда"},
+	{Code: "UM", Alpha3: "UMI", Numeric: "581", Name: "Соединенные штаты Малых Удаленных островов"},
+	{Code: "US", Alpha3: "USA", Numeric: "840", Name: "Соединенные Штаты"},
+	{Code: "UY", Alpha3: "URY", Numeric: "858", Name: "Уругвай"},
+	{Code: "UZ", Alpha3: "UZB", Numeric: "860", Name: "Узбекистан"},
+	{Code: "VA", Alpha3: "VAT", Numeric: "336", Name: "Государство-город Ватикан"},
+	{Code: "VC", Alpha3: "VCT", Numeric: "670", Name: "Сент-Винсент и Гренадины"},
+	{Code: "VE", Alpha3: "VEN", Numeric: "862", Name: "Боливарианская Республика Венесуэла"},
+	{Code: "VG", Alpha3: "VGB", Numeric: "092", Name: "Виргинские острова (Британия)"},
+	{Code: "VI", Alpha3: "VIR", Numeric: "850", Name: "Виргинские острова (США)"},
+	{Code: "VN", Alpha3: "VNM", Numeric: "704", Name: "Вьетнам"},
+	{Code: "VU", Alpha3: "VUT", Numeric: "548", Name: "Вануату"},
+	{Code: "WF", Alpha3: "WLF", Numeric: "876", Name: "Уоллес и Футана"},
+	{Code: "WS", Alpha3: "WSM", Numeric: "882", Name: "Самоа"},
+	{Code: "YE", Alpha3: "YEM", Numeric: "887", Name: "Йемен"},
+	{Code: "YT", Alpha3: "MYT", Numeric: "175", Name: "Майот"},
+	{Code: "ZA", Alpha3: "ZAF", Numeric: "710", Name: "Южная Африка"},
+	{Code: "ZM", Alpha3: "ZMB", Numeric: "894", Name: "Замбия"},
+	{Code: "ZW", Alpha3: "ZWE", Numeric: "716", Name: "Зимбабве"},
+}
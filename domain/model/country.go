@@ -0,0 +1,27 @@
+package model
+
+// CountryKazakhstan является кодом страны резидентов
+const CountryKazakhstan = "KZ"
+
+// Country является страной справочника ISO 3166-1. Code содержит код alpha-2, которым страна хранится у контрагента.
+type Country struct {
+	Code    string
+	Alpha3  string
+	Numeric string
+	Name    string
+}
+
+var countriesByCode = func() map[string]Country {
+	result := make(map[string]Country, len(Countries))
+	for _, c := range Countries {
+		result[c.Code] = c
+	}
+
+	return result
+}()
+
+// FindCountry возвращает страну по коду alpha-2
+func FindCountry(code string) (Country, bool) {
+	c, ok := countriesByCode[code]
+	return c, ok
+}
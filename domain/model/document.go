@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+type DocumentType string
+
+const (
+	DocumentTypeCharter                 DocumentType = "CHARTER"
+	DocumentTypeRegistrationCertificate DocumentType = "REGISTRATION_CERTIFICATE"
+	DocumentTypeNda                     DocumentType = "NDA"
+	DocumentTypeOther                   DocumentType = "OTHER"
+)
+
+// Document является метаданными документа контрагента. Содержимое хранится в repository.DocumentStorage
+// под ключом StorageKey.
+type Document struct {
+	Id           int64
+	ContractorId int64
+	Type         DocumentType
+	Number       *string
+	IssueDate    *time.Time
+	ExpiryDate   *time.Time
+	FileName     string
+	ContentType  string
+	Size         int64
+	Sha256       string
+	StorageKey   string
+	UploadedBy   *string
+	UploadedAt   time.Time
+}
+
+func (d Document) ReadModel(reader DbModelReader) (interface{}, error) {
+	tmp := Document{}
+	err := reader.Scan(&tmp.Id, &tmp.ContractorId, &tmp.Type, &tmp.Number, &tmp.IssueDate, &tmp.ExpiryDate,
+		&tmp.FileName, &tmp.ContentType, &tmp.Size, &tmp.Sha256, &tmp.StorageKey, &tmp.UploadedBy, &tmp.UploadedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tmp, nil
+}
@@ -0,0 +1,73 @@
+package model
+
+import "fmt"
+
+const (
+	EntityContractor  = "контрагент"
+	EntityEmployee    = "сотрудник"
+	EntitySavedSearch = "сохраненный поиск"
+	EntityWebhook     = "подписка на события"
+	EntityDelivery    = "доставка события"
+	EntityAddress     = "адрес"
+	EntityContact     = "контакт"
+	EntityPhone       = "телефон"
+	EntityDocument    = "документ"
+	EntityContract    = "договор"
+	EntityTag         = "тег"
+	EntityBankAccount = "банковский счет"
+)
+
+// NotFoundError возвращается, если сущность не существует или удалена
+type NotFoundError struct {
+	Entity string
+	Id     interface{}
+}
+
+func NewNotFoundError(entity string, id interface{}) *NotFoundError {
+	return &NotFoundError{Entity: entity, Id: id}
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%s с ИД %v не найден", e.Entity, e.Id)
+}
+
+// ConflictError возвращается, если операция нарушает уникальность или целостность данных.
+// ConflictingId содержит ИД записи, с которой возник конфликт, если он известен.
+type ConflictError struct {
+	Entity        string
+	Field         string
+	Value         interface{}
+	ConflictingId *int64
+	Message       string
+	Err           error
+}
+
+func NewConflictError(entity string, field string, value interface{}, message string) *ConflictError {
+	return &ConflictError{Entity: entity, Field: field, Value: value, Message: message}
+}
+
+func (e *ConflictError) Error() string {
+	if e.Err == nil {
+		return e.Message
+	}
+
+	return fmt.Sprintf("%s: %s", e.Message, e.Err.Error())
+}
+
+func (e *ConflictError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError возвращается, если данные не прошли проверку бизнес-правил
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func NewValidationError(field string, message string) *ValidationError {
+	return &ValidationError{Field: field, Message: message}
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
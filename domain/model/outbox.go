@@ -0,0 +1,290 @@
+package model
+
+import (
+	"encoding/json"
+	uuid "github.com/satori/go.uuid"
+	"time"
+)
+
+type EventType string
+
+const (
+	EventContractorCreated   EventType = "contractor.created"
+	EventContractorUpdated   EventType = "contractor.updated"
+	EventContractorBlocked   EventType = "contractor.blocked"
+	EventContractorUnblocked EventType = "contractor.unblocked"
+	EventContractorDeleted   EventType = "contractor.deleted"
+	EventContractorRestored  EventType = "contractor.restored"
+	EventEmployeeAdded       EventType = "employee.added"
+	EventEmployeeUpdated     EventType = "employee.updated"
+	EventEmployeeDeleted     EventType = "employee.deleted"
+	EventContractExpiring    EventType = "contract.expiring"
+	EventAddressAdded        EventType = "address.added"
+	EventAddressUpdated      EventType = "address.updated"
+	EventAddressDeleted      EventType = "address.deleted"
+	EventContactAdded        EventType = "contact.added"
+	EventContactUpdated      EventType = "contact.updated"
+	EventContactDeleted      EventType = "contact.deleted"
+	EventContractAdded       EventType = "contract.added"
+	EventContractUpdated     EventType = "contract.updated"
+	EventContractDeleted     EventType = "contract.deleted"
+	EventTagsUpdated         EventType = "tags.updated"
+)
+
+// EventVersion является версией схемы данных событий. Увеличивается при несовместимом изменении
+// структур *EventData.
+const EventVersion = 1
+
+type OutboxStatus string
+
+const (
+	OutboxStatusPending   OutboxStatus = "PENDING"
+	OutboxStatusDelivered OutboxStatus = "DELIVERED"
+	OutboxStatusFailed    OutboxStatus = "FAILED"
+)
+
+// OutboxEvent является событием жизненного цикла контрагента, записанным в outbox в транзакции изменения.
+// AggregateId равен ИД контрагента, события одного контрагента доставляются в порядке записи.
+type OutboxEvent struct {
+	Id          int64
+	EventId     string
+	Type        EventType
+	Version     int
+	AggregateId int64
+	Payload     json.RawMessage
+	OccurredAt  time.Time
+	Attempts    int
+}
+
+func (e OutboxEvent) ReadModel(reader DbModelReader) (interface{}, error) {
+	tmp := OutboxEvent{}
+	err := reader.Scan(&tmp.Id, &tmp.EventId, &tmp.Type, &tmp.Version, &tmp.AggregateId, &tmp.Payload,
+		&tmp.OccurredAt, &tmp.Attempts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tmp, nil
+}
+
+// NewOutboxEvent создает событие текущей версии схемы с данными data
+func NewOutboxEvent(eventType EventType, aggregateId int64, data interface{}) (*OutboxEvent, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OutboxEvent{
+		EventId:     uuid.NewV4().String(),
+		Type:        eventType,
+		Version:     EventVersion,
+		AggregateId: aggregateId,
+		Payload:     payload,
+		OccurredAt:  time.Now().UTC(),
+	}, nil
+}
+
+// EventEnvelope является сообщением, передаваемым получателям событий
+type EventEnvelope struct {
+	Id          string          `json:"id"`
+	Type        EventType       `json:"type"`
+	Version     int             `json:"version"`
+	AggregateId int64           `json:"aggregateId"`
+	OccurredAt  time.Time       `json:"occurredAt"`
+	Data        json.RawMessage `json:"data"`
+}
+
+func (e OutboxEvent) Envelope() EventEnvelope {
+	return EventEnvelope{
+		Id:          e.EventId,
+		Type:        e.Type,
+		Version:     e.Version,
+		AggregateId: e.AggregateId,
+		OccurredAt:  e.OccurredAt,
+		Data:        e.Payload,
+	}
+}
+
+// ContractorEventData является данными событий создания и изменения контрагента
+type ContractorEventData struct {
+	Id            int64            `json:"id"`
+	ParentId      *int64           `json:"parentId"`
+	Resident      bool             `json:"resident"`
+	Bin           *string          `json:"bin"`
+	Country       *string          `json:"country"`
+	ForeignTaxId  *string          `json:"foreignTaxId"`
+	Name          *string          `json:"name"`
+	Email         string           `json:"email"`
+	AgentName     *string          `json:"agentName"`
+	AgentPosition *string          `json:"agentPosition"`
+	Status        ContractorStatus `json:"status"`
+	BlockDate     *time.Time       `json:"blockDate"`
+}
+
+func NewContractorEventData(contractor Contractor) ContractorEventData {
+	return ContractorEventData{
+		Id:            contractor.Id,
+		ParentId:      contractor.ParentId,
+		Resident:      contractor.Resident,
+		Bin:           contractor.Bin,
+		Country:       contractor.Country,
+		ForeignTaxId:  contractor.ForeignTaxId,
+		Name:          contractor.Name,
+		Email:         contractor.Email,
+		AgentName:     contractor.AgentName,
+		AgentPosition: contractor.AgentPosition,
+		Status:        contractor.Status,
+		BlockDate:     contractor.BlockDate,
+	}
+}
+
+// ContractorStatusEventData является данными событий блокировки и разблокировки контрагента
+type ContractorStatusEventData struct {
+	Id        int64            `json:"id"`
+	Status    ContractorStatus `json:"status"`
+	BlockDate *time.Time       `json:"blockDate"`
+}
+
+// ContractExpiringEventData является данными предупреждения об окончании последнего действующего договора.
+// Если до EndDate не появится другой действующий договор, контрагент будет заблокирован.
+type ContractExpiringEventData struct {
+	ContractorId int64  `json:"contractorId"`
+	ContractId   int64  `json:"contractId"`
+	Number       string `json:"number"`
+	EndDate      string `json:"endDate"`
+}
+
+// ContractEventData является данными событий добавления и изменения договора
+type ContractEventData struct {
+	Id           int64          `json:"id"`
+	ContractorId int64          `json:"contractorId"`
+	Number       string         `json:"number"`
+	StartDate    string         `json:"startDate"`
+	EndDate      *string        `json:"endDate"`
+	Amount       string         `json:"amount"`
+	Currency     string         `json:"currency"`
+	Status       ContractStatus `json:"status"`
+}
+
+func NewContractEventData(contract Contract) ContractEventData {
+	var endDate *string
+	if contract.EndDate != nil {
+		value := contract.EndDate.Format("2006-01-02")
+		endDate = &value
+	}
+
+	return ContractEventData{
+		Id:           contract.Id,
+		ContractorId: contract.ContractorId,
+		Number:       contract.Number,
+		StartDate:    contract.StartDate.Format("2006-01-02"),
+		EndDate:      endDate,
+		Amount:       contract.Amount,
+		Currency:     contract.Currency,
+		Status:       contract.Status,
+	}
+}
+
+// ContractorRefEventData является данными событий удаления и восстановления контрагента
+type ContractorRefEventData struct {
+	Id int64 `json:"id"`
+}
+
+// EmployeeEventData является данными событий добавления и изменения сотрудника
+type EmployeeEventData struct {
+	Id           int64          `json:"id"`
+	ContractorId int64          `json:"contractorId"`
+	Email        string         `json:"email"`
+	FullName     string         `json:"fullName"`
+	Position     string         `json:"position"`
+	Status       EmployeeStatus `json:"status"`
+	BlockDate    *time.Time     `json:"blockDate"`
+}
+
+func NewEmployeeEventData(employee Employee) EmployeeEventData {
+	return EmployeeEventData{
+		Id:           employee.Id,
+		ContractorId: employee.ContractorId,
+		Email:        employee.Email,
+		FullName:     employee.FullName,
+		Position:     employee.Position,
+		Status:       employee.Status,
+		BlockDate:    employee.BlockDate,
+	}
+}
+
+// EmployeeRefEventData является данными события удаления сотрудника
+type EmployeeRefEventData struct {
+	Id           int64 `json:"id"`
+	ContractorId int64 `json:"contractorId"`
+}
+
+// AddressEventData является данными событий добавления и изменения адреса контрагента
+type AddressEventData struct {
+	Id           int64       `json:"id"`
+	ContractorId int64       `json:"contractorId"`
+	Type         AddressType `json:"type"`
+	Country      *string     `json:"country"`
+	Region       *string     `json:"region"`
+	City         string      `json:"city"`
+	Street       string      `json:"street"`
+	PostalCode   *string     `json:"postalCode"`
+}
+
+func NewAddressEventData(address Address) AddressEventData {
+	return AddressEventData{
+		Id:           address.Id,
+		ContractorId: address.ContractorId,
+		Type:         address.Type,
+		Country:      address.Country,
+		Region:       address.Region,
+		City:         address.City,
+		Street:       address.Street,
+		PostalCode:   address.PostalCode,
+	}
+}
+
+// ContactEventData является данными событий добавления и изменения контактного лица вместе с его телефонами
+type ContactEventData struct {
+	Id           int64            `json:"id"`
+	ContractorId int64            `json:"contractorId"`
+	FullName     string           `json:"fullName"`
+	Position     *string          `json:"position"`
+	Email        *string          `json:"email"`
+	Phones       []PhoneEventData `json:"phones"`
+}
+
+// PhoneEventData является телефоном в данных событий
+type PhoneEventData struct {
+	Type      PhoneType `json:"type"`
+	Number    string    `json:"number"`
+	Extension *string   `json:"extension"`
+}
+
+func NewContactEventData(contact Contact) ContactEventData {
+	phones := make([]PhoneEventData, len(contact.Phones))
+	for i, phone := range contact.Phones {
+		phones[i] = PhoneEventData{Type: phone.Type, Number: phone.Number, Extension: phone.Extension}
+	}
+
+	return ContactEventData{
+		Id:           contact.Id,
+		ContractorId: contact.ContractorId,
+		FullName:     contact.FullName,
+		Position:     contact.Position,
+		Email:        contact.Email,
+		Phones:       phones,
+	}
+}
+
+// ContractorTagsEventData является данными события изменения тегов контрагента и содержит итоговый набор тегов
+type ContractorTagsEventData struct {
+	ContractorId int64   `json:"contractorId"`
+	TagIds       []int64 `json:"tagIds"`
+}
+
+// ContractorItemRefEventData является данными событий удаления адреса, контактного лица и договора контрагента
+type ContractorItemRefEventData struct {
+	Id           int64 `json:"id"`
+	ContractorId int64 `json:"contractorId"`
+}
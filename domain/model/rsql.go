@@ -0,0 +1,428 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// RsqlNode является узлом дерева разобранного RSQL/FIQL выражения
+type RsqlNode interface {
+	isRsqlNode()
+}
+
+type RsqlLogicalOperator string
+
+const (
+	RsqlAnd RsqlLogicalOperator = "and"
+	RsqlOr  RsqlLogicalOperator = "or"
+)
+
+// RsqlLogical объединяет дочерние выражения логическим оператором
+type RsqlLogical struct {
+	Operator RsqlLogicalOperator
+	Children []RsqlNode
+}
+
+func (RsqlLogical) isRsqlNode() {}
+
+type RsqlOperator string
+
+const (
+	RsqlEqual          RsqlOperator = "=="
+	RsqlNotEqual       RsqlOperator = "!="
+	RsqlLessThan       RsqlOperator = "=lt="
+	RsqlLessOrEqual    RsqlOperator = "=le="
+	RsqlGreaterThan    RsqlOperator = "=gt="
+	RsqlGreaterOrEqual RsqlOperator = "=ge="
+	RsqlIn             RsqlOperator = "=in="
+	RsqlNotIn          RsqlOperator = "=out="
+	RsqlLike           RsqlOperator = "=like="
+	RsqlIsNull         RsqlOperator = "=isnull="
+)
+
+// rsqlOperatorAliases содержит сокращенные формы операторов сравнения
+var rsqlOperatorAliases = map[string]RsqlOperator{
+	"<":  RsqlLessThan,
+	"<=": RsqlLessOrEqual,
+	">":  RsqlGreaterThan,
+	">=": RsqlGreaterOrEqual,
+}
+
+var rsqlOperators = map[RsqlOperator]bool{
+	RsqlEqual:          true,
+	RsqlNotEqual:       true,
+	RsqlLessThan:       true,
+	RsqlLessOrEqual:    true,
+	RsqlGreaterThan:    true,
+	RsqlGreaterOrEqual: true,
+	RsqlIn:             true,
+	RsqlNotIn:          true,
+	RsqlLike:           true,
+	RsqlIsNull:         true,
+}
+
+// RsqlComparison является сравнением поля Selector с аргументами
+type RsqlComparison struct {
+	Selector  string
+	Operator  RsqlOperator
+	Arguments []string
+}
+
+func (RsqlComparison) isRsqlNode() {}
+
+// RsqlWildcard заменяет любое количество символов в строковых аргументах
+const RsqlWildcard = "*"
+
+// ParseRsql разбирает RSQL/FIQL выражение вида `status==BLOCK;name=like=ТОО*,resident==false`.
+// Оператор `;` (and) имеет больший приоритет, чем `,` (or).
+func ParseRsql(expression string) (RsqlNode, error) {
+	p := &rsqlParser{input: []rune(expression)}
+
+	p.skipSpaces()
+	if p.eof() {
+		return nil, errors.New("пустое выражение фильтра")
+	}
+
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpaces()
+	if !p.eof() {
+		return nil, p.errorf("неожиданный символ '%c'", p.peek())
+	}
+
+	return node, nil
+}
+
+type rsqlParser struct {
+	input []rune
+	pos   int
+}
+
+func (p *rsqlParser) parseOr() (RsqlNode, error) {
+	return p.parseLogical(RsqlOr, ',', p.parseAnd)
+}
+
+func (p *rsqlParser) parseAnd() (RsqlNode, error) {
+	return p.parseLogical(RsqlAnd, ';', p.parseConstraint)
+}
+
+func (p *rsqlParser) parseLogical(operator RsqlLogicalOperator, separator rune,
+	parseOperand func() (RsqlNode, error)) (RsqlNode, error) {
+	first, err := parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	children := []RsqlNode{first}
+	for {
+		p.skipSpaces()
+		if p.eof() || p.peek() != separator {
+			break
+		}
+		p.pos++
+
+		next, err := parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, next)
+	}
+
+	if len(children) == 1 {
+		return first, nil
+	}
+
+	return RsqlLogical{Operator: operator, Children: children}, nil
+}
+
+func (p *rsqlParser) parseConstraint() (RsqlNode, error) {
+	p.skipSpaces()
+	if p.eof() {
+		return nil, p.errorf("ожидалось условие")
+	}
+
+	if p.peek() == '(' {
+		p.pos++
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		p.skipSpaces()
+		if p.eof() || p.peek() != ')' {
+			return nil, p.errorf("ожидалась ')'")
+		}
+		p.pos++
+
+		return node, nil
+	}
+
+	return p.parseComparison()
+}
+
+func (p *rsqlParser) parseComparison() (RsqlNode, error) {
+	start := p.pos
+	for !p.eof() && isRsqlSelectorRune(p.peek()) {
+		p.pos++
+	}
+	if start == p.pos {
+		return nil, p.errorf("ожидалось название поля")
+	}
+	selector := string(p.input[start:p.pos])
+
+	p.skipSpaces()
+	operator, err := p.parseOperator()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpaces()
+	arguments, err := p.parseArguments()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(arguments) > 1 && operator != RsqlIn && operator != RsqlNotIn {
+		return nil, p.errorf("оператор '%s' принимает только одно значение", operator)
+	}
+
+	return RsqlComparison{Selector: selector, Operator: operator, Arguments: arguments}, nil
+}
+
+func (p *rsqlParser) parseOperator() (RsqlOperator, error) {
+	start := p.pos
+	if p.eof() {
+		return "", p.errorf("ожидался оператор сравнения")
+	}
+
+	switch p.peek() {
+	case '<', '>':
+		p.pos++
+		if !p.eof() && p.peek() == '=' {
+			p.pos++
+		}
+		return rsqlOperatorAliases[string(p.input[start:p.pos])], nil
+	case '!':
+		p.pos++
+		if p.eof() || p.peek() != '=' {
+			return "", p.errorf("ожидался оператор '!='")
+		}
+		p.pos++
+		return RsqlNotEqual, nil
+	case '=':
+		p.pos++
+		for !p.eof() && unicode.IsLetter(p.peek()) {
+			p.pos++
+		}
+		if p.eof() || p.peek() != '=' {
+			return "", p.errorf("незавершенный оператор сравнения")
+		}
+		p.pos++
+
+		operator := RsqlOperator(strings.ToLower(string(p.input[start:p.pos])))
+		if !rsqlOperators[operator] {
+			return "", p.errorf("неизвестный оператор '%s'", operator)
+		}
+		return operator, nil
+	default:
+		return "", p.errorf("ожидался оператор сравнения")
+	}
+}
+
+func (p *rsqlParser) parseArguments() ([]string, error) {
+	if p.eof() || p.peek() != '(' {
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		return []string{value}, nil
+	}
+	p.pos++
+
+	result := make([]string, 0)
+	for {
+		p.skipSpaces()
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, value)
+
+		p.skipSpaces()
+		if p.eof() {
+			return nil, p.errorf("ожидалась ')'")
+		}
+		if p.peek() == ')' {
+			p.pos++
+			return result, nil
+		}
+		if p.peek() != ',' {
+			return nil, p.errorf("ожидалась ',' или ')'")
+		}
+		p.pos++
+	}
+}
+
+func (p *rsqlParser) parseValue() (string, error) {
+	if p.eof() {
+		return "", p.errorf("ожидалось значение")
+	}
+
+	quote := p.peek()
+	if quote == '"' || quote == '\'' {
+		p.pos++
+		var sb strings.Builder
+		for !p.eof() {
+			r := p.peek()
+			p.pos++
+			if r == '\\' && !p.eof() {
+				sb.WriteRune(p.peek())
+				p.pos++
+				continue
+			}
+			if r == quote {
+				return sb.String(), nil
+			}
+			sb.WriteRune(r)
+		}
+		return "", p.errorf("незакрытая кавычка")
+	}
+
+	start := p.pos
+	for !p.eof() && !isRsqlReservedRune(p.peek()) {
+		p.pos++
+	}
+	if start == p.pos {
+		return "", p.errorf("ожидалось значение")
+	}
+
+	return string(p.input[start:p.pos]), nil
+}
+
+func (p *rsqlParser) skipSpaces() {
+	for !p.eof() && unicode.IsSpace(p.peek()) {
+		p.pos++
+	}
+}
+
+func (p *rsqlParser) eof() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *rsqlParser) peek() rune {
+	return p.input[p.pos]
+}
+
+func (p *rsqlParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("позиция %d: %s", p.pos+1, fmt.Sprintf(format, args...))
+}
+
+func isRsqlSelectorRune(r rune) bool {
+	return r == '_' || r == '.' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func isRsqlReservedRune(r rune) bool {
+	return strings.ContainsRune(`"'();,=!~<>`, r) || unicode.IsSpace(r)
+}
+
+type RsqlFieldType int
+
+const (
+	RsqlFieldString RsqlFieldType = iota
+	RsqlFieldInteger
+	RsqlFieldBoolean
+	RsqlFieldDate
+)
+
+// RsqlField описывает поле ресурса, разрешенное к фильтрации.
+// Values ограничивает допустимые значения перечислимых полей.
+type RsqlField struct {
+	Type   RsqlFieldType
+	Values []string
+}
+
+// Convert приводит строковый аргумент выражения к типу поля
+func (f RsqlField) Convert(value string) (interface{}, error) {
+	switch f.Type {
+	case RsqlFieldInteger:
+		return strconv.ParseInt(value, 10, 64)
+	case RsqlFieldBoolean:
+		return strconv.ParseBool(value)
+	case RsqlFieldDate:
+		for _, layout := range []string{time.RFC3339, "2006-01-02", "02.01.2006"} {
+			if result, err := time.Parse(layout, value); err == nil {
+				return result, nil
+			}
+		}
+		return nil, fmt.Errorf("'%s' не является датой", value)
+	default:
+		if len(f.Values) > 0 && !containsString(f.Values, value) {
+			return nil, fmt.Errorf("'%s' не входит в список допустимых значений %v", value, f.Values)
+		}
+		return value, nil
+	}
+}
+
+// RsqlFields является белым списком полей ресурса, доступных для фильтрации
+type RsqlFields map[string]RsqlField
+
+// Validate проверяет, что выражение использует только разрешенные поля,
+// а аргументы соответствуют типам полей
+func (fs RsqlFields) Validate(node RsqlNode) error {
+	switch n := node.(type) {
+	case RsqlLogical:
+		for _, child := range n.Children {
+			if err := fs.Validate(child); err != nil {
+				return err
+			}
+		}
+		return nil
+	case RsqlComparison:
+		field, ok := fs[n.Selector]
+		if !ok {
+			return fmt.Errorf("фильтрация по полю '%s' не поддерживается", n.Selector)
+		}
+
+		switch n.Operator {
+		case RsqlIsNull:
+			if _, err := strconv.ParseBool(n.Arguments[0]); err != nil {
+				return fmt.Errorf("поле '%s': оператор '%s' принимает true или false", n.Selector, n.Operator)
+			}
+			return nil
+		case RsqlLike:
+			if field.Type != RsqlFieldString {
+				return fmt.Errorf("поле '%s': оператор '%s' применим только к строкам", n.Selector, n.Operator)
+			}
+			return nil
+		}
+
+		for _, argument := range n.Arguments {
+			if field.Type == RsqlFieldString && strings.Contains(argument, RsqlWildcard) {
+				continue
+			}
+			if _, err := field.Convert(argument); err != nil {
+				return fmt.Errorf("поле '%s': %s", n.Selector, err.Error())
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("неизвестный узел выражения %T", node)
+	}
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+
+	return false
+}
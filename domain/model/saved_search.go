@@ -0,0 +1,48 @@
+package model
+
+import "time"
+
+// SavedSearch является именованным набором параметров поиска контрагентов,
+// принадлежащим пользователю OwnerLogin и, опционально, доступным роли SharedRole
+type SavedSearch struct {
+	Id         int64
+	OwnerLogin string
+	Name       string
+	SharedRole *RoleCode
+	Query      string
+	CreatedAt  time.Time
+}
+
+func (s SavedSearch) ReadModel(reader DbModelReader) (interface{}, error) {
+	tmp := SavedSearch{}
+	err := reader.Scan(&tmp.Id, &tmp.OwnerLogin, &tmp.Name, &tmp.SharedRole, &tmp.Query, &tmp.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tmp, nil
+}
+
+// IsOwnedBy возвращает true, если пользователь является владельцем поиска
+func (s SavedSearch) IsOwnedBy(user UserInfo) bool {
+	return s.OwnerLogin == user.Login()
+}
+
+// IsVisibleTo возвращает true, если пользователь является владельцем поиска или имеет роль, с которой им поделились
+func (s SavedSearch) IsVisibleTo(user UserInfo) bool {
+	if s.IsOwnedBy(user) {
+		return true
+	}
+
+	if s.SharedRole == nil {
+		return false
+	}
+
+	for _, role := range user.Roles() {
+		if role == *s.SharedRole {
+			return true
+		}
+	}
+
+	return false
+}
@@ -53,3 +53,19 @@ func (d DateFilter) WithTime() *DateFilter {
 func NewDateFilter(from *time.Time, to *time.Time) *DateFilter {
 	return &DateFilter{From: from, To: to}
 }
+
+// FacetValue содержит количество записей, имеющих значение Value
+type FacetValue struct {
+	Value string
+	Count int64
+}
+
+func (f FacetValue) ReadModel(reader DbModelReader) (interface{}, error) {
+	tmp := FacetValue{}
+	err := reader.Scan(&tmp.Value, &tmp.Count)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tmp, nil
+}
@@ -0,0 +1,43 @@
+package model
+
+import "time"
+
+// Tag является элементом справочника тегов контрагентов. Category группирует теги,
+// например, по виду услуг или уровню риска.
+type Tag struct {
+	Id          int64
+	Category    string
+	Name        string
+	Description *string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	// UsageCount заполняется только в списке тегов и равен количеству действующих контрагентов с тегом
+	UsageCount int64
+}
+
+func (t Tag) ReadModel(reader DbModelReader) (interface{}, error) {
+	tmp := Tag{}
+	err := reader.Scan(&tmp.Id, &tmp.Category, &tmp.Name, &tmp.Description, &tmp.CreatedAt, &tmp.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tmp, nil
+}
+
+// ContractorTag является тегом, назначенным контрагенту
+type ContractorTag struct {
+	ContractorId int64
+	Tag          Tag
+}
+
+func (t ContractorTag) ReadModel(reader DbModelReader) (interface{}, error) {
+	tmp := ContractorTag{}
+	err := reader.Scan(&tmp.ContractorId, &tmp.Tag.Id, &tmp.Tag.Category, &tmp.Tag.Name, &tmp.Tag.Description,
+		&tmp.Tag.CreatedAt, &tmp.Tag.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tmp, nil
+}
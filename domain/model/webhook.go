@@ -0,0 +1,139 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// EventTypes перечисляет все типы событий, на которые можно подписаться
+var EventTypes = []EventType{
+	EventContractorCreated,
+	EventContractorUpdated,
+	EventContractorBlocked,
+	EventContractorUnblocked,
+	EventContractorDeleted,
+	EventContractorRestored,
+	EventEmployeeAdded,
+	EventEmployeeUpdated,
+	EventEmployeeDeleted,
+	EventContractExpiring,
+	EventAddressAdded,
+	EventAddressUpdated,
+	EventAddressDeleted,
+	EventContactAdded,
+	EventContactUpdated,
+	EventContactDeleted,
+	EventContractAdded,
+	EventContractUpdated,
+	EventContractDeleted,
+	EventTagsUpdated,
+}
+
+// WebhookSubscription является подпиской внешней системы на события контрагентов.
+// Secret используется для подписи доставляемых сообщений.
+type WebhookSubscription struct {
+	Id         int64
+	Url        string
+	EventTypes []EventType
+	Secret     string
+	Active     bool
+	CreatedBy  string
+	CreatedAt  time.Time
+}
+
+func (s WebhookSubscription) ReadModel(reader DbModelReader) (interface{}, error) {
+	tmp := WebhookSubscription{}
+	var eventTypes []string
+	var createdBy *string
+	err := reader.Scan(&tmp.Id, &tmp.Url, &eventTypes, &tmp.Secret, &tmp.Active, &createdBy, &tmp.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	tmp.EventTypes = make([]EventType, len(eventTypes))
+	for i, eventType := range eventTypes {
+		tmp.EventTypes[i] = EventType(eventType)
+	}
+	if createdBy != nil {
+		tmp.CreatedBy = *createdBy
+	}
+
+	return &tmp, nil
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "PENDING"
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "DELIVERED"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "FAILED"
+)
+
+// WebhookDelivery является доставкой одного события одной подписке
+type WebhookDelivery struct {
+	Id             int64
+	SubscriptionId int64
+	EventId        string
+	EventType      EventType
+	Payload        json.RawMessage
+	Status         WebhookDeliveryStatus
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastStatusCode *int
+	LastError      *string
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+	AttemptLog     []WebhookDeliveryAttempt
+}
+
+func (d WebhookDelivery) ReadModel(reader DbModelReader) (interface{}, error) {
+	tmp := WebhookDelivery{}
+	err := reader.Scan(&tmp.Id, &tmp.SubscriptionId, &tmp.EventId, &tmp.EventType, &tmp.Payload, &tmp.Status,
+		&tmp.Attempts, &tmp.NextAttemptAt, &tmp.LastStatusCode, &tmp.LastError, &tmp.CreatedAt, &tmp.DeliveredAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tmp, nil
+}
+
+// PendingWebhookDelivery является доставкой, ожидающей отправки, вместе с адресом и секретом подписки
+type PendingWebhookDelivery struct {
+	WebhookDelivery
+	Url    string
+	Secret string
+}
+
+func (d PendingWebhookDelivery) ReadModel(reader DbModelReader) (interface{}, error) {
+	tmp := PendingWebhookDelivery{}
+	err := reader.Scan(&tmp.Id, &tmp.SubscriptionId, &tmp.EventId, &tmp.EventType, &tmp.Payload, &tmp.Attempts,
+		&tmp.Url, &tmp.Secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tmp, nil
+}
+
+// WebhookDeliveryAttempt является записью об одной попытке доставки.
+// StatusCode равен nil, если ответ не был получен.
+type WebhookDeliveryAttempt struct {
+	Id          int64
+	DeliveryId  int64
+	AttemptedAt time.Time
+	StatusCode  *int
+	Error       *string
+	Duration    time.Duration
+}
+
+func (a WebhookDeliveryAttempt) ReadModel(reader DbModelReader) (interface{}, error) {
+	tmp := WebhookDeliveryAttempt{}
+	var durationMs int64
+	err := reader.Scan(&tmp.Id, &tmp.DeliveryId, &tmp.AttemptedAt, &tmp.StatusCode, &tmp.Error, &durationMs)
+	if err != nil {
+		return nil, err
+	}
+	tmp.Duration = time.Duration(durationMs) * time.Millisecond
+
+	return &tmp, nil
+}
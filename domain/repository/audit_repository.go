@@ -0,0 +1,11 @@
+package repository
+
+import (
+	"context"
+	"github.com/jackc/pgx/v4"
+	"service_admin_contractor/domain/model"
+)
+
+type AuditRepository interface {
+	CreateAuditEntry(ctx context.Context, tx pgx.Tx, entry *model.AuditEntry) error
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/jackc/pgx/v4"
+	"service_admin_contractor/infrastructure/persistence/postgres"
+)
+
+type BackupRepository interface {
+	postgres.Transactional
+	WithSnapshotTransaction(ctx context.Context) (pgx.Tx, error)
+	CountTableRows(ctx context.Context, tx pgx.Tx, table string) (int64, error)
+	ForEachTableRow(ctx context.Context, tx pgx.Tx, table string, fn func(row json.RawMessage) error) error
+	DisableChangeLog(ctx context.Context, tx pgx.Tx) error
+	FindTableColumns(ctx context.Context, tx pgx.Tx, table string) ([]string, error)
+	InsertTableRow(ctx context.Context, tx pgx.Tx, table string, columns []string, row json.RawMessage) error
+	ResetTableSequence(ctx context.Context, tx pgx.Tx, table string) error
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"github.com/jackc/pgx/v4"
+	"service_admin_contractor/domain/model"
+	"service_admin_contractor/infrastructure/persistence/postgres"
+)
+
+type BankAccountRepository interface {
+	postgres.Transactional
+	FindBankAccounts(ctx context.Context, contractorId int64) ([]model.BankAccount, error)
+	GetBankAccount(ctx context.Context, contractorId int64, id int64) (model.BankAccount, error)
+	CreateBankAccount(ctx context.Context, tx pgx.Tx, account *model.BankAccount) error
+	UpdateBankAccount(ctx context.Context, tx pgx.Tx, account *model.BankAccount) error
+	DeleteBankAccount(ctx context.Context, tx pgx.Tx, contractorId int64, id int64) error
+	// ResetPrimaryBankAccount снимает признак основного со всех счетов контрагента, кроме счета exceptId
+	ResetPrimaryBankAccount(ctx context.Context, tx pgx.Tx, contractorId int64, exceptId int64) error
+}
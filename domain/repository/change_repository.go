@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+	"service_admin_contractor/domain/model"
+)
+
+type ChangeRepository interface {
+	FindChanges(ctx context.Context, since model.ChangeToken, limit int) ([]model.Change, error)
+	FindLatestChangeToken(ctx context.Context) (model.ChangeToken, error)
+	FindContractorsByIds(ctx context.Context, ids []int64) ([]model.Contractor, error)
+	FindEmployeesByIds(ctx context.Context, ids []int64) ([]model.Employee, error)
+	ListenChanges(ctx context.Context, fn func()) error
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"github.com/jackc/pgx/v4"
+	"service_admin_contractor/domain/model"
+	"service_admin_contractor/infrastructure/persistence/postgres"
+)
+
+type ContactRepository interface {
+	postgres.Transactional
+	FindContractorContacts(ctx context.Context, contractorId int64) (model.ContractorContacts, error)
+
+	FindAddresses(ctx context.Context, contractorId int64) ([]model.Address, error)
+	CreateAddress(ctx context.Context, tx pgx.Tx, address *model.Address) error
+	UpdateAddress(ctx context.Context, tx pgx.Tx, address *model.Address) error
+	DeleteAddress(ctx context.Context, tx pgx.Tx, contractorId int64, id int64) error
+
+	FindContacts(ctx context.Context, contractorId int64) ([]model.Contact, error)
+	GetContact(ctx context.Context, contractorId int64, id int64) (model.Contact, error)
+	CreateContact(ctx context.Context, tx pgx.Tx, contact *model.Contact) error
+	UpdateContact(ctx context.Context, tx pgx.Tx, contact *model.Contact) error
+	DeleteContact(ctx context.Context, tx pgx.Tx, contractorId int64, id int64) error
+
+	ReplacePhones(ctx context.Context, tx pgx.Tx, contractorId int64, contactId *int64, phones []model.Phone) error
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+	"github.com/jackc/pgx/v4"
+	"service_admin_contractor/domain/model"
+	"service_admin_contractor/infrastructure/persistence/postgres"
+	"time"
+)
+
+type ContractRepository interface {
+	postgres.Transactional
+	FindContracts(ctx context.Context, contractorId int64) ([]model.Contract, error)
+	GetContract(ctx context.Context, contractorId int64, id int64) (model.Contract, error)
+	CreateContract(ctx context.Context, tx pgx.Tx, contract *model.Contract) error
+	UpdateContract(ctx context.Context, tx pgx.Tx, contract *model.Contract) error
+	DeleteContract(ctx context.Context, tx pgx.Tx, contractorId int64, id int64) error
+
+	// LockContractorsWithoutActiveContract блокирует строки не более limit действующих контрагентов, у которых
+	// нет договора, действующего в день today. Контрагенты без единого договора выбираются только при withoutContracts.
+	LockContractorsWithoutActiveContract(ctx context.Context, tx pgx.Tx, today time.Time,
+		withoutContracts bool, limit int) ([]int64, error)
+	// LockExpiringContracts блокирует не более limit договоров без отправленного предупреждения, которые действуют
+	// в день today, заканчиваются не позже until и после окончания которых у контрагента не остается действующих договоров
+	LockExpiringContracts(ctx context.Context, tx pgx.Tx, today time.Time, until time.Time,
+		limit int) ([]model.Contract, error)
+	MarkContractExpiryWarned(ctx context.Context, tx pgx.Tx, id int64) error
+}
@@ -5,19 +5,40 @@ import (
 	"github.com/jackc/pgx/v4"
 	"service_admin_contractor/domain/model"
 	"service_admin_contractor/infrastructure/persistence/postgres"
+	"time"
 )
 
 type ContractorRepository interface {
 	postgres.Transactional
 	FindContractors(ctx context.Context, params model.ContractorSearchParameters) ([]model.Contractor, int64, error)
+	StreamContractors(ctx context.Context, params model.ContractorSearchParameters,
+		fn func(contractor model.Contractor) error) error
+	StreamContractorEmployees(ctx context.Context, params model.ContractorSearchParameters,
+		fn func(row model.ContractorEmployeeRow) error) error
+	FindContractorFacets(ctx context.Context,
+		params model.ContractorSearchParameters) (map[model.ContractorFacet][]model.FacetValue, error)
 	GetContractor(ctx context.Context, id int64) (model.Contractor, error)
+	LockContractor(ctx context.Context, tx pgx.Tx, id int64) (model.Contractor, error)
+	ShareLockContractor(ctx context.Context, tx pgx.Tx, id int64) error
+	FindContractorIds(ctx context.Context, params model.ContractorSearchParameters, limit int) ([]int64, error)
+	FindExistingEmails(ctx context.Context, emails []string) ([]string, error)
 	CreateContractor(ctx context.Context, tx pgx.Tx, contractor *model.Contractor) error
 	UpdateContractorData(ctx context.Context, tx pgx.Tx, contractorId int64, contractor *model.Contractor) error
-	DeleteContractor(id int64) error
+	UpdateContractorStatus(ctx context.Context, tx pgx.Tx, contractorId int64, status model.ContractorStatus,
+		blockDate *time.Time) error
+	SetContractorDeleted(ctx context.Context, tx pgx.Tx, contractorId int64, deleted bool) error
+	LockContractorHierarchy(ctx context.Context, tx pgx.Tx) error
+	FindContractorDescendants(ctx context.Context, tx pgx.Tx, id int64) ([]model.Contractor, error)
+	FindContractorDescendantIds(ctx context.Context, tx pgx.Tx, id int64) ([]int64, error)
+	FindContractorTree(ctx context.Context, id int64) ([]model.ContractorTreeNode, error)
 
 	CreateContractorEmployee(ctx context.Context, tx pgx.Tx, contractorId int64, employee *model.Employee) error
+	FindContractorEmployeeIds(ctx context.Context, tx pgx.Tx, contractorId int64) (map[string]int64, error)
 	UpdateContractorEmployeeData(ctx context.Context, tx pgx.Tx, employeeId int64, employee *model.Employee) error
-	DeleteContractorEmployee(id int64) error
+	DeleteContractorEmployee(ctx context.Context, tx pgx.Tx, id int64) (int64, error)
+	LockEmail(ctx context.Context, tx pgx.Tx, email string) error
+	FindContractorIdByEmail(ctx context.Context, tx pgx.Tx, email string) (*int64, error)
+	FindEmployeeByEmail(ctx context.Context, tx pgx.Tx, email string, excludeId int64) (*model.Employee, error)
 
 	CreateCredentials(ctx context.Context, tx pgx.Tx, credentials model.Credentials) error
 	UpdateContractorCredentials(ctx context.Context, tx pgx.Tx, credentials model.Credentials) error
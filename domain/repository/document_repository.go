@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"io"
+	"service_admin_contractor/domain/model"
+)
+
+type DocumentRepository interface {
+	FindDocuments(ctx context.Context, contractorId int64) ([]model.Document, error)
+	GetDocument(ctx context.Context, contractorId int64, id int64) (model.Document, error)
+	CreateDocument(ctx context.Context, document *model.Document) error
+	DeleteDocument(ctx context.Context, contractorId int64, id int64) (model.Document, error)
+}
+
+// DocumentStorage хранит содержимое документов по ключу
+type DocumentStorage interface {
+	Save(ctx context.Context, key string, content io.Reader) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"github.com/jackc/pgx/v4"
+	"service_admin_contractor/domain/model"
+	"service_admin_contractor/infrastructure/persistence/postgres"
+	"time"
+)
+
+type OutboxRepository interface {
+	postgres.Transactional
+	CreateOutboxEvent(ctx context.Context, tx pgx.Tx, event *model.OutboxEvent) error
+	ClaimOutboxEvents(ctx context.Context, limit int, lease time.Duration) ([]model.OutboxEvent, error)
+	MarkOutboxEventDelivered(ctx context.Context, tx pgx.Tx, id int64) error
+	MarkOutboxEventFailed(ctx context.Context, tx pgx.Tx, id int64, lastError string, nextAttemptAt *time.Time) error
+	// RequeueFailedOutboxEvents возвращает события FAILED контрагента aggregateId (всех, если nil) в очередь
+	RequeueFailedOutboxEvents(ctx context.Context, tx pgx.Tx, aggregateId *int64) (int64, error)
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+	"service_admin_contractor/domain/model"
+)
+
+type SavedSearchRepository interface {
+	FindSavedSearches(ctx context.Context, login string, roles []model.RoleCode) ([]model.SavedSearch, error)
+	GetSavedSearch(ctx context.Context, id int64) (*model.SavedSearch, error)
+	CreateSavedSearch(ctx context.Context, search *model.SavedSearch) error
+	UpdateSavedSearch(ctx context.Context, search *model.SavedSearch) error
+	DeleteSavedSearch(ctx context.Context, id int64) error
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+	"github.com/jackc/pgx/v4"
+	"service_admin_contractor/domain/model"
+	"service_admin_contractor/infrastructure/persistence/postgres"
+)
+
+type TagRepository interface {
+	postgres.Transactional
+	// FindTags возвращает теги категории category (все, если nil) с количеством использований
+	FindTags(ctx context.Context, category *string) ([]model.Tag, error)
+	GetTag(ctx context.Context, id int64) (model.Tag, error)
+	// FindExistingTagIds возвращает ИД действующих тегов из списка ids
+	FindExistingTagIds(ctx context.Context, ids []int64) ([]int64, error)
+	CreateTag(ctx context.Context, tx pgx.Tx, tag *model.Tag) error
+	UpdateTag(ctx context.Context, tx pgx.Tx, tag *model.Tag) error
+	// DeleteTag помечает тег удаленным, снимает его со всех контрагентов и возвращает ИД этих контрагентов
+	DeleteTag(ctx context.Context, tx pgx.Tx, id int64) ([]int64, error)
+
+	FindContractorTags(ctx context.Context, contractorId int64) ([]model.Tag, error)
+	// FindContractorTagIds возвращает ИД тегов, назначенных контрагенту, в транзакции tx
+	FindContractorTagIds(ctx context.Context, tx pgx.Tx, contractorId int64) ([]int64, error)
+	AddContractorTag(ctx context.Context, tx pgx.Tx, contractorId int64, tagId int64) error
+	RemoveContractorTag(ctx context.Context, tx pgx.Tx, contractorId int64, tagId int64) error
+	ReplaceContractorTags(ctx context.Context, tx pgx.Tx, contractorId int64, tagIds []int64) error
+}
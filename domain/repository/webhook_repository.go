@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"github.com/jackc/pgx/v4"
+	"service_admin_contractor/domain/model"
+	"service_admin_contractor/infrastructure/persistence/postgres"
+	"time"
+)
+
+type WebhookRepository interface {
+	postgres.Transactional
+	FindWebhookSubscriptions(ctx context.Context) ([]model.WebhookSubscription, error)
+	GetWebhookSubscription(ctx context.Context, id int64) (model.WebhookSubscription, error)
+	CreateWebhookSubscription(ctx context.Context, subscription *model.WebhookSubscription) error
+	UpdateWebhookSubscription(ctx context.Context, subscription *model.WebhookSubscription) error
+	DeleteWebhookSubscription(ctx context.Context, id int64) error
+
+	CreateWebhookDeliveries(ctx context.Context, event model.OutboxEvent, payload []byte) error
+	FindWebhookDeliveries(ctx context.Context, subscriptionId int64,
+		pagination model.Pagination) ([]model.WebhookDelivery, int64, error)
+	GetWebhookDelivery(ctx context.Context, subscriptionId int64, id int64) (model.WebhookDelivery, error)
+	ResetWebhookDelivery(ctx context.Context, subscriptionId int64, id int64) error
+
+	ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]model.PendingWebhookDelivery, error)
+	CreateWebhookDeliveryAttempt(ctx context.Context, tx pgx.Tx, attempt *model.WebhookDeliveryAttempt) error
+	MarkWebhookDeliveryDelivered(ctx context.Context, tx pgx.Tx, id int64, statusCode int) error
+	MarkWebhookDeliveryFailed(ctx context.Context, tx pgx.Tx, id int64, statusCode *int, lastError string,
+		nextAttemptAt *time.Time) error
+}
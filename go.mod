@@ -8,6 +8,7 @@ require (
 	github.com/go-playground/validator/v10 v10.8.0
 	github.com/gorilla/context v1.1.1 // indirect
 	github.com/gorilla/mux v1.6.2
+	github.com/jackc/pgconn v1.10.1
 	github.com/jackc/pgtype v1.9.1
 	github.com/jackc/pgx/v4 v4.14.1
 	github.com/lib/pq v1.10.2
@@ -16,5 +17,6 @@ require (
 	github.com/sirupsen/logrus v1.4.2
 	github.com/spf13/cobra v1.2.1
 	github.com/spf13/viper v1.8.1
-	golang.org/x/crypto v0.0.0-20210711020723-a769d52b0f97
+	github.com/xuri/excelize/v2 v2.7.0
+	golang.org/x/crypto v0.5.0
 )
@@ -0,0 +1,37 @@
+package postgres
+
+import (
+	"context"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"service_admin_contractor/domain/model"
+)
+
+type AuditRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAuditRepository(db *pgxpool.Pool) *AuditRepository {
+	return &AuditRepository{db}
+}
+
+func (a *AuditRepository) CreateAuditEntry(ctx context.Context, tx pgx.Tx, entry *model.AuditEntry) error {
+	query := `INSERT INTO contractors_audit_log (
+					entity, entity_id, action, actor_login, details
+				) VALUES (
+					:entity, :entity_id, :action, :actor_login, :details
+				) RETURNING id, created_at`
+
+	finalQuery, queryArgs, err := InlineNamedPlaceholders(query, map[string]interface{}{
+		"entity":      entry.Entity,
+		"entity_id":   entry.EntityId,
+		"action":      entry.Action,
+		"actor_login": entry.ActorLogin,
+		"details":     entry.Details,
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.QueryRow(ctx, finalQuery, queryArgs...).Scan(&entry.Id, &entry.CreatedAt)
+}
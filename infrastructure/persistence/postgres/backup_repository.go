@@ -0,0 +1,171 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	log "github.com/sirupsen/logrus"
+	"service_admin_contractor/domain/model"
+	"strings"
+)
+
+// BackupRepository читает и записывает строки таблиц целиком, как JSON-объекты, чтобы резервная копия
+// включала все колонки, в том числе не представленные в моделях
+type BackupRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewBackupRepository(db *pgxpool.Pool) *BackupRepository {
+	return &BackupRepository{db}
+}
+
+func (b *BackupRepository) RollbackQuietly(tx pgx.Tx, ctx context.Context) {
+	err := tx.Rollback(ctx)
+	if err != nil {
+		log.Warn(err)
+	}
+}
+
+func (b *BackupRepository) WithTransaction(ctx context.Context) (pgx.Tx, error) {
+	return b.db.BeginTx(ctx, pgx.TxOptions{})
+}
+
+// WithSnapshotTransaction открывает читающую транзакцию, в которой все таблицы видны на один момент времени
+func (b *BackupRepository) WithSnapshotTransaction(ctx context.Context) (pgx.Tx, error) {
+	return b.db.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+}
+
+func (b *BackupRepository) CountTableRows(ctx context.Context, tx pgx.Tx, table string) (int64, error) {
+	name, err := backupTableName(table)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	err = tx.QueryRow(ctx, `select count(*) from `+name).Scan(&count)
+	return count, err
+}
+
+func (b *BackupRepository) ForEachTableRow(ctx context.Context, tx pgx.Tx, table string,
+	fn func(row json.RawMessage) error) error {
+	name, err := backupTableName(table)
+	if err != nil {
+		return err
+	}
+
+	query := `select row_to_json(t) from ` + name + ` t order by t.id`
+
+	return QueryWithMap(tx, ctx, query, map[string]interface{}{}).ForEach(
+		model.NewSimpleModelProvider(func(reader model.DbModelReader) (interface{}, error) {
+			var row []byte
+			err := reader.Scan(&row)
+			return json.RawMessage(row), err
+		}), func(item interface{}) error {
+			return fn(item.(*model.SimpleModelProvider).Value().(json.RawMessage))
+		})
+}
+
+// DisableChangeLog отключает запись журнала изменений и уведомления до конца транзакции tx.
+// Остальные триггеры и проверки внешних ключей продолжают работать.
+func (b *BackupRepository) DisableChangeLog(ctx context.Context, tx pgx.Tx) error {
+	_, err := tx.Exec(ctx, `select set_config('contractors.restore', 'on', true)`)
+	return err
+}
+
+// FindTableColumns возвращает колонки таблицы в порядке их объявления
+func (b *BackupRepository) FindTableColumns(ctx context.Context, tx pgx.Tx, table string) ([]string, error) {
+	name, err := backupTableName(table)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `select a.attname from pg_attribute a
+				where a.attrelid = :table::regclass and a.attnum > 0 and not a.attisdropped
+				order by a.attnum`
+
+	columns := make([]string, 0)
+	err = QueryWithMap(tx, ctx, query, map[string]interface{}{"table": name}).ForEach(
+		model.NewSimpleModelProvider(func(reader model.DbModelReader) (interface{}, error) {
+			var column string
+			err := reader.Scan(&column)
+			return column, err
+		}),
+		func(item interface{}) error {
+			columns = append(columns, item.(*model.SimpleModelProvider).Value().(string))
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	return columns, nil
+}
+
+// InsertTableRow вставляет строку с сохранением всех значений, включая id. Вставляются только колонки columns,
+// присутствующие в строке: колонки, добавленные после создания архива, получают значения по умолчанию.
+func (b *BackupRepository) InsertTableRow(ctx context.Context, tx pgx.Tx, table string, columns []string,
+	row json.RawMessage) error {
+	name, err := backupTableName(table)
+	if err != nil {
+		return err
+	}
+
+	values := map[string]json.RawMessage{}
+	if err = json.Unmarshal(row, &values); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if _, ok := values[column]; ok {
+			names = append(names, pgx.Identifier{column}.Sanitize())
+		}
+	}
+	if len(names) == 0 {
+		return model.NewValidationError("row", "строка не содержит колонок таблицы '"+table+"'")
+	}
+
+	list := strings.Join(names, ", ")
+	query := `insert into ` + name + ` (` + list + `) select ` + list +
+		` from json_populate_record(null::` + name + `, :row::json)`
+
+	finalQuery, queryArgs, err := InlineNamedPlaceholders(query, map[string]interface{}{
+		"row": string(row),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, finalQuery, queryArgs...)
+	return err
+}
+
+// ResetTableSequence устанавливает последовательность bigserial колонки id после максимального id таблицы
+func (b *BackupRepository) ResetTableSequence(ctx context.Context, tx pgx.Tx, table string) error {
+	name, err := backupTableName(table)
+	if err != nil {
+		return err
+	}
+
+	query := `select setval(pg_get_serial_sequence(:table, 'id'), coalesce((select max(id) from ` + name + `), 0) + 1, false)`
+
+	finalQuery, queryArgs, err := InlineNamedPlaceholders(query, map[string]interface{}{
+		"table": name,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, finalQuery, queryArgs...)
+	return err
+}
+
+// backupTableName возвращает экранированное имя таблицы, допуская только таблицы резервной копии
+func backupTableName(table string) (string, error) {
+	if !model.IsBackupTable(table) {
+		return "", model.NewValidationError("table", "таблица '"+table+"' не входит в резервную копию")
+	}
+
+	return pgx.Identifier{table}.Sanitize(), nil
+}
@@ -0,0 +1,170 @@
+package postgres
+
+import (
+	"context"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	log "github.com/sirupsen/logrus"
+	"service_admin_contractor/domain/model"
+)
+
+// bankAccountColumns перечисляет колонки в порядке, ожидаемом model.BankAccount.ReadModel
+const bankAccountColumns = `ba.id, ba.contractor_id, ba.iban, ba.bik, ba.bank_name, ba.currency, ba.is_primary,
+							ba.created_at, ba.updated_at`
+
+type BankAccountRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewBankAccountRepository(db *pgxpool.Pool) *BankAccountRepository {
+	return &BankAccountRepository{db}
+}
+
+func (b *BankAccountRepository) RollbackQuietly(tx pgx.Tx, ctx context.Context) {
+	err := tx.Rollback(ctx)
+	if err != nil {
+		log.Warn(err)
+	}
+}
+
+func (b *BankAccountRepository) WithTransaction(ctx context.Context) (pgx.Tx, error) {
+	return b.db.BeginTx(ctx, pgx.TxOptions{})
+}
+
+func (b *BankAccountRepository) FindBankAccounts(ctx context.Context, contractorId int64) ([]model.BankAccount, error) {
+	query := `select ` + bankAccountColumns + ` from contractors_contractor_bank_account ba
+				where ba.contractor_id = :contractor_id and ba.is_delete = false
+				order by ba.is_primary desc, ba.id`
+
+	res, err := QueryWithMap(b.db, ctx, query, map[string]interface{}{"contractor_id": contractorId}).
+		ReadAll(model.BankAccount{})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.([]model.BankAccount), nil
+}
+
+func (b *BankAccountRepository) GetBankAccount(ctx context.Context, contractorId int64,
+	id int64) (model.BankAccount, error) {
+	query := `select ` + bankAccountColumns + ` from contractors_contractor_bank_account ba
+				where ba.id = :id and ba.contractor_id = :contractor_id and ba.is_delete = false`
+
+	res, err := QueryWithMap(b.db, ctx, query, map[string]interface{}{
+		"id":            id,
+		"contractor_id": contractorId,
+	}).Read(model.BankAccount{})
+	if err != nil {
+		return model.BankAccount{}, err
+	}
+	if res == nil {
+		return model.BankAccount{}, model.NewNotFoundError(model.EntityBankAccount, id)
+	}
+
+	return *res.(*model.BankAccount), nil
+}
+
+func (b *BankAccountRepository) CreateBankAccount(ctx context.Context, tx pgx.Tx, account *model.BankAccount) error {
+	query := `insert into contractors_contractor_bank_account (
+					contractor_id, iban, bik, bank_name, currency, is_primary
+				) values (
+					:contractor_id, :iban, :bik, :bank_name, :currency, :is_primary
+				) returning id, created_at, updated_at`
+
+	finalQuery, queryArgs, err := InlineNamedPlaceholders(query, map[string]interface{}{
+		"contractor_id": account.ContractorId,
+		"iban":          account.Iban,
+		"bik":           account.Bik,
+		"bank_name":     account.BankName,
+		"currency":      account.Currency,
+		"is_primary":    account.Primary,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = tx.QueryRow(ctx, finalQuery, queryArgs...).Scan(&account.Id, &account.CreatedAt, &account.UpdatedAt)
+	if err != nil {
+		return translateError(err, model.EntityBankAccount)
+	}
+
+	return nil
+}
+
+func (b *BankAccountRepository) UpdateBankAccount(ctx context.Context, tx pgx.Tx, account *model.BankAccount) error {
+	query := `update contractors_contractor_bank_account
+				set
+					iban = 			:iban,
+					bik = 			:bik,
+					bank_name = 	:bank_name,
+					currency = 		:currency,
+					is_primary = 	:is_primary
+				where id = :id and contractor_id = :contractor_id and is_delete = false
+				returning created_at, updated_at`
+
+	finalQuery, queryArgs, err := InlineNamedPlaceholders(query, map[string]interface{}{
+		"iban":          account.Iban,
+		"bik":           account.Bik,
+		"bank_name":     account.BankName,
+		"currency":      account.Currency,
+		"is_primary":    account.Primary,
+		"id":            account.Id,
+		"contractor_id": account.ContractorId,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = tx.QueryRow(ctx, finalQuery, queryArgs...).Scan(&account.CreatedAt, &account.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return model.NewNotFoundError(model.EntityBankAccount, account.Id)
+	}
+	if err != nil {
+		return translateError(err, model.EntityBankAccount)
+	}
+
+	return nil
+}
+
+func (b *BankAccountRepository) DeleteBankAccount(ctx context.Context, tx pgx.Tx, contractorId int64, id int64) error {
+	query := `update contractors_contractor_bank_account
+				set is_delete = true, is_primary = false
+				where id = :id and contractor_id = :contractor_id and is_delete = false`
+
+	finalQuery, queryArgs, err := InlineNamedPlaceholders(query, map[string]interface{}{
+		"id":            id,
+		"contractor_id": contractorId,
+	})
+	if err != nil {
+		return err
+	}
+
+	tag, err := tx.Exec(ctx, finalQuery, queryArgs...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return model.NewNotFoundError(model.EntityBankAccount, id)
+	}
+
+	return nil
+}
+
+func (b *BankAccountRepository) ResetPrimaryBankAccount(ctx context.Context, tx pgx.Tx, contractorId int64,
+	exceptId int64) error {
+	query := `update contractors_contractor_bank_account
+				set is_primary = false
+				where contractor_id = :contractor_id and id <> :except_id and is_primary = true and is_delete = false`
+
+	finalQuery, queryArgs, err := InlineNamedPlaceholders(query, map[string]interface{}{
+		"contractor_id": contractorId,
+		"except_id":     exceptId,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, finalQuery, queryArgs...)
+	return err
+}
@@ -0,0 +1,117 @@
+package postgres
+
+import (
+	"context"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"service_admin_contractor/domain/model"
+)
+
+type ChangeRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewChangeRepository(db *pgxpool.Pool) *ChangeRepository {
+	return &ChangeRepository{db}
+}
+
+// FindChanges возвращает не более limit записей журнала изменений, следующих за токеном since.
+// Записи транзакций, начатых после самой старой незавершенной транзакции, не возвращаются до ее завершения,
+// чтобы изменения с меньшим ИД транзакции не появились в журнале позже прочитанного токена.
+func (c *ChangeRepository) FindChanges(ctx context.Context, since model.ChangeToken,
+	limit int) ([]model.Change, error) {
+	query := `select l.tx_id, l.id, l.entity, l.entity_id, l.contractor_id, l.operation, l.changed_at
+				from contractors_change_log l
+				where (l.tx_id, l.id) > (:tx_id, :id)
+				  and l.tx_id < txid_snapshot_xmin(txid_current_snapshot())
+				order by l.tx_id, l.id
+				limit :limit`
+
+	result, err := QueryWithMap(c.db, ctx, query, map[string]interface{}{
+		"tx_id": since.TxId,
+		"id":    since.Id,
+		"limit": limit,
+	}).ReadAll(model.Change{})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.([]model.Change), nil
+}
+
+// FindLatestChangeToken возвращает токен последней записи журнала, уже доступной для чтения FindChanges
+func (c *ChangeRepository) FindLatestChangeToken(ctx context.Context) (model.ChangeToken, error) {
+	query := `select l.tx_id, l.id
+				from contractors_change_log l
+				where l.tx_id < txid_snapshot_xmin(txid_current_snapshot())
+				order by l.tx_id desc, l.id desc
+				limit 1`
+
+	token := model.ChangeToken{}
+	err := c.db.QueryRow(ctx, query).Scan(&token.TxId, &token.Id)
+	if err != nil && err != pgx.ErrNoRows {
+		return model.ChangeToken{}, err
+	}
+
+	return token, nil
+}
+
+// FindContractorsByIds возвращает текущее состояние контрагентов, в том числе удаленных, без сотрудников
+func (c *ChangeRepository) FindContractorsByIds(ctx context.Context, ids []int64) ([]model.Contractor, error) {
+	if len(ids) == 0 {
+		return []model.Contractor{}, nil
+	}
+
+	query := `select ` + contractorColumns + ` from contractors_contractor c where c.id = any(:ids)`
+
+	result, err := QueryWithMap(c.db, ctx, query, map[string]interface{}{"ids": ids}).ReadAll(model.Contractor{})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.([]model.Contractor), nil
+}
+
+// FindEmployeesByIds возвращает текущее состояние сотрудников, в том числе удаленных
+func (c *ChangeRepository) FindEmployeesByIds(ctx context.Context, ids []int64) ([]model.Employee, error) {
+	if len(ids) == 0 {
+		return []model.Employee{}, nil
+	}
+
+	query := `select ` + employeeColumns + ` from contractors_contractor_employee e where e.id = any(:ids)`
+
+	result, err := QueryWithMap(c.db, ctx, query, map[string]interface{}{"ids": ids}).ReadAll(model.Employee{})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.([]model.Employee), nil
+}
+
+// changesChannel является каналом LISTEN/NOTIFY, в который триггер журнала изменений отправляет уведомления
+const changesChannel = "contractors_changes"
+
+// ListenChanges занимает отдельное соединение пула, подписывается на уведомления об изменениях
+// и вызывает fn на каждое уведомление до отмены ctx или ошибки соединения
+func (c *ChangeRepository) ListenChanges(ctx context.Context, fn func()) error {
+	conn, err := c.db.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_, _ = conn.Exec(context.Background(), "unlisten *")
+		conn.Release()
+	}()
+
+	if _, err = conn.Exec(ctx, "listen "+changesChannel); err != nil {
+		return err
+	}
+
+	for {
+		if _, err = conn.Conn().WaitForNotification(ctx); err != nil {
+			return err
+		}
+
+		fn()
+	}
+}
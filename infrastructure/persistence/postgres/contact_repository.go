@@ -0,0 +1,383 @@
+package postgres
+
+import (
+	"context"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	log "github.com/sirupsen/logrus"
+	"service_admin_contractor/domain/model"
+)
+
+// addressColumns перечисляет колонки в порядке, ожидаемом model.Address.ReadModel
+const addressColumns = `a.id, a.contractor_id, a.type, a.country, a.region, a.city, a.street, a.postal_code,
+							a.created_at, a.updated_at`
+
+// contactColumns перечисляет колонки в порядке, ожидаемом model.Contact.ReadModel
+const contactColumns = `p.id, p.contractor_id, p.full_name, p.position, p.email, p.comment, p.created_at, p.updated_at`
+
+// phoneColumns перечисляет колонки в порядке, ожидаемом model.Phone.ReadModel
+const phoneColumns = `t.id, t.contractor_id, t.contact_id, t.type, t.number, t.extension`
+
+type ContactRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewContactRepository(db *pgxpool.Pool) *ContactRepository {
+	return &ContactRepository{db}
+}
+
+func (c *ContactRepository) RollbackQuietly(tx pgx.Tx, ctx context.Context) {
+	err := tx.Rollback(ctx)
+	if err != nil {
+		log.Warn(err)
+	}
+}
+
+func (c *ContactRepository) WithTransaction(ctx context.Context) (pgx.Tx, error) {
+	return c.db.BeginTx(ctx, pgx.TxOptions{})
+}
+
+// FindContractorContacts загружает адреса, телефоны и контактные лица контрагента
+func (c *ContactRepository) FindContractorContacts(ctx context.Context,
+	contractorId int64) (model.ContractorContacts, error) {
+	addresses, err := c.FindAddresses(ctx, contractorId)
+	if err != nil {
+		return model.ContractorContacts{}, err
+	}
+
+	contacts, err := c.FindContacts(ctx, contractorId)
+	if err != nil {
+		return model.ContractorContacts{}, err
+	}
+
+	phones, err := c.findPhones(ctx, contractorId, false)
+	if err != nil {
+		return model.ContractorContacts{}, err
+	}
+
+	return model.ContractorContacts{Addresses: addresses, Phones: phones, Contacts: contacts}, nil
+}
+
+func (c *ContactRepository) FindAddresses(ctx context.Context, contractorId int64) ([]model.Address, error) {
+	query := `select ` + addressColumns + ` from contractors_contractor_address a
+				where a.contractor_id = :contractor_id and a.is_delete = false
+				order by a.type, a.id`
+
+	res, err := QueryWithMap(c.db, ctx, query, map[string]interface{}{"contractor_id": contractorId}).
+		ReadAll(model.Address{})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.([]model.Address), nil
+}
+
+func (c *ContactRepository) CreateAddress(ctx context.Context, tx pgx.Tx, address *model.Address) error {
+	query := `insert into contractors_contractor_address (
+					contractor_id, type, country, region, city, street, postal_code
+				) values (
+					:contractor_id, :type, :country, :region, :city, :street, :postal_code
+				) returning id, created_at, updated_at`
+
+	finalQuery, queryArgs, err := InlineNamedPlaceholders(query, map[string]interface{}{
+		"contractor_id": address.ContractorId,
+		"type":          address.Type,
+		"country":       address.Country,
+		"region":        address.Region,
+		"city":          address.City,
+		"street":        address.Street,
+		"postal_code":   address.PostalCode,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = tx.QueryRow(ctx, finalQuery, queryArgs...).Scan(&address.Id, &address.CreatedAt, &address.UpdatedAt)
+	if err != nil {
+		return translateError(err, model.EntityAddress)
+	}
+
+	return nil
+}
+
+func (c *ContactRepository) UpdateAddress(ctx context.Context, tx pgx.Tx, address *model.Address) error {
+	query := `update contractors_contractor_address
+				set
+					type = 			:type,
+					country = 		:country,
+					region = 		:region,
+					city = 			:city,
+					street = 		:street,
+					postal_code = 	:postal_code
+				where id = :id and contractor_id = :contractor_id and is_delete = false
+				returning created_at, updated_at`
+
+	finalQuery, queryArgs, err := InlineNamedPlaceholders(query, map[string]interface{}{
+		"type":          address.Type,
+		"country":       address.Country,
+		"region":        address.Region,
+		"city":          address.City,
+		"street":        address.Street,
+		"postal_code":   address.PostalCode,
+		"id":            address.Id,
+		"contractor_id": address.ContractorId,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = tx.QueryRow(ctx, finalQuery, queryArgs...).Scan(&address.CreatedAt, &address.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return model.NewNotFoundError(model.EntityAddress, address.Id)
+	}
+	if err != nil {
+		return translateError(err, model.EntityAddress)
+	}
+
+	return nil
+}
+
+func (c *ContactRepository) DeleteAddress(ctx context.Context, tx pgx.Tx, contractorId int64, id int64) error {
+	query := `update contractors_contractor_address
+				set is_delete = true
+				where id = :id and contractor_id = :contractor_id and is_delete = false`
+
+	finalQuery, queryArgs, err := InlineNamedPlaceholders(query, map[string]interface{}{
+		"id":            id,
+		"contractor_id": contractorId,
+	})
+	if err != nil {
+		return err
+	}
+
+	tag, err := tx.Exec(ctx, finalQuery, queryArgs...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return model.NewNotFoundError(model.EntityAddress, id)
+	}
+
+	return nil
+}
+
+// FindContacts возвращает контактные лица контрагента с их телефонами
+func (c *ContactRepository) FindContacts(ctx context.Context, contractorId int64) ([]model.Contact, error) {
+	query := `select ` + contactColumns + ` from contractors_contractor_contact p
+				where p.contractor_id = :contractor_id and p.is_delete = false
+				order by p.full_name, p.id`
+
+	res, err := QueryWithMap(c.db, ctx, query, map[string]interface{}{"contractor_id": contractorId}).
+		ReadAll(model.Contact{})
+	if err != nil {
+		return nil, err
+	}
+	contacts := res.([]model.Contact)
+
+	phones, err := c.findPhones(ctx, contractorId, true)
+	if err != nil {
+		return nil, err
+	}
+
+	byContact := make(map[int64][]model.Phone)
+	for _, phone := range phones {
+		byContact[*phone.ContactId] = append(byContact[*phone.ContactId], phone)
+	}
+	for i := range contacts {
+		contacts[i].Phones = byContact[contacts[i].Id]
+	}
+
+	return contacts, nil
+}
+
+func (c *ContactRepository) GetContact(ctx context.Context, contractorId int64, id int64) (model.Contact, error) {
+	query := `select ` + contactColumns + ` from contractors_contractor_contact p
+				where p.id = :id and p.contractor_id = :contractor_id and p.is_delete = false`
+
+	res, err := QueryWithMap(c.db, ctx, query, map[string]interface{}{
+		"id":            id,
+		"contractor_id": contractorId,
+	}).Read(model.Contact{})
+	if err != nil {
+		return model.Contact{}, err
+	}
+	if res == nil {
+		return model.Contact{}, model.NewNotFoundError(model.EntityContact, id)
+	}
+	contact := *res.(*model.Contact)
+
+	query = `select ` + phoneColumns + ` from contractors_contractor_phone t
+				where t.contact_id = :contact_id
+				order by t.id`
+
+	phones, err := QueryWithMap(c.db, ctx, query, map[string]interface{}{"contact_id": id}).ReadAll(model.Phone{})
+	if err != nil {
+		return model.Contact{}, err
+	}
+	contact.Phones = phones.([]model.Phone)
+
+	return contact, nil
+}
+
+func (c *ContactRepository) CreateContact(ctx context.Context, tx pgx.Tx, contact *model.Contact) error {
+	query := `insert into contractors_contractor_contact (
+					contractor_id, full_name, position, email, comment
+				) values (
+					:contractor_id, :full_name, :position, :email, :comment
+				) returning id, created_at, updated_at`
+
+	contact.Email = normalizeOptionalEmail(contact.Email)
+	finalQuery, queryArgs, err := InlineNamedPlaceholders(query, map[string]interface{}{
+		"contractor_id": contact.ContractorId,
+		"full_name":     contact.FullName,
+		"position":      contact.Position,
+		"email":         contact.Email,
+		"comment":       contact.Comment,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = tx.QueryRow(ctx, finalQuery, queryArgs...).Scan(&contact.Id, &contact.CreatedAt, &contact.UpdatedAt)
+	if err != nil {
+		return translateError(err, model.EntityContact)
+	}
+
+	return nil
+}
+
+func (c *ContactRepository) UpdateContact(ctx context.Context, tx pgx.Tx, contact *model.Contact) error {
+	query := `update contractors_contractor_contact
+				set
+					full_name = :full_name,
+					position = 	:position,
+					email = 	:email,
+					comment = 	:comment
+				where id = :id and contractor_id = :contractor_id and is_delete = false
+				returning created_at, updated_at`
+
+	contact.Email = normalizeOptionalEmail(contact.Email)
+	finalQuery, queryArgs, err := InlineNamedPlaceholders(query, map[string]interface{}{
+		"full_name":     contact.FullName,
+		"position":      contact.Position,
+		"email":         contact.Email,
+		"comment":       contact.Comment,
+		"id":            contact.Id,
+		"contractor_id": contact.ContractorId,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = tx.QueryRow(ctx, finalQuery, queryArgs...).Scan(&contact.CreatedAt, &contact.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return model.NewNotFoundError(model.EntityContact, contact.Id)
+	}
+	if err != nil {
+		return translateError(err, model.EntityContact)
+	}
+
+	return nil
+}
+
+// DeleteContact помечает контактное лицо удаленным и удаляет его телефоны
+func (c *ContactRepository) DeleteContact(ctx context.Context, tx pgx.Tx, contractorId int64, id int64) error {
+	query := `update contractors_contractor_contact
+				set is_delete = true
+				where id = :id and contractor_id = :contractor_id and is_delete = false`
+
+	finalQuery, queryArgs, err := InlineNamedPlaceholders(query, map[string]interface{}{
+		"id":            id,
+		"contractor_id": contractorId,
+	})
+	if err != nil {
+		return err
+	}
+
+	tag, err := tx.Exec(ctx, finalQuery, queryArgs...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return model.NewNotFoundError(model.EntityContact, id)
+	}
+
+	return c.ReplacePhones(ctx, tx, contractorId, &id, nil)
+}
+
+// ReplacePhones заменяет телефоны контрагента (contactId равен nil) или его контактного лица набором phones
+// и заполняет их ИД
+func (c *ContactRepository) ReplacePhones(ctx context.Context, tx pgx.Tx, contractorId int64, contactId *int64,
+	phones []model.Phone) error {
+	query := `delete from contractors_contractor_phone
+				where contractor_id = :contractor_id and contact_id is not distinct from :contact_id::bigint`
+
+	finalQuery, queryArgs, err := InlineNamedPlaceholders(query, map[string]interface{}{
+		"contractor_id": contractorId,
+		"contact_id":    contactId,
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.Exec(ctx, finalQuery, queryArgs...); err != nil {
+		return err
+	}
+
+	query = `insert into contractors_contractor_phone (
+					contractor_id, contact_id, type, number, extension
+				) values (
+					:contractor_id, :contact_id, :type, :number, :extension
+				) returning id`
+
+	for i := range phones {
+		phone := &phones[i]
+		phone.ContractorId = contractorId
+		phone.ContactId = contactId
+
+		finalQuery, queryArgs, err = InlineNamedPlaceholders(query, map[string]interface{}{
+			"contractor_id": phone.ContractorId,
+			"contact_id":    phone.ContactId,
+			"type":          phone.Type,
+			"number":        phone.Number,
+			"extension":     phone.Extension,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err = tx.QueryRow(ctx, finalQuery, queryArgs...).Scan(&phone.Id); err != nil {
+			return translateError(err, model.EntityPhone)
+		}
+	}
+
+	return nil
+}
+
+// findPhones возвращает телефоны контактных лиц (ofContacts) или самого контрагента
+func (c *ContactRepository) findPhones(ctx context.Context, contractorId int64, ofContacts bool) ([]model.Phone, error) {
+	query := `select ` + phoneColumns + ` from contractors_contractor_phone t
+				where t.contractor_id = :contractor_id and (t.contact_id is not null) = :of_contacts
+				order by t.id`
+
+	res, err := QueryWithMap(c.db, ctx, query, map[string]interface{}{
+		"contractor_id": contractorId,
+		"of_contacts":   ofContacts,
+	}).ReadAll(model.Phone{})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.([]model.Phone), nil
+}
+
+func normalizeOptionalEmail(email *string) *string {
+	if email == nil {
+		return nil
+	}
+
+	normalized := model.NormalizeEmail(*email)
+	return &normalized
+}
@@ -0,0 +1,254 @@
+package postgres
+
+import (
+	"context"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	log "github.com/sirupsen/logrus"
+	"service_admin_contractor/domain/model"
+	"time"
+)
+
+// contractColumns перечисляет колонки в порядке, ожидаемом model.Contract.ReadModel
+const contractColumns = `ct.id, ct.contractor_id, ct.number, ct.start_date, ct.end_date, ct.amount::text, ct.currency,
+							ct.status, ct.expiry_warned_at, ct.created_at, ct.updated_at`
+
+// activeContractCondition отбирает договоры алиаса ct, действующие в день :today
+const activeContractCondition = `ct.is_delete = false and ct.status = :active_contract
+							and ct.start_date <= :today::date and (ct.end_date is null or ct.end_date >= :today::date)`
+
+type ContractRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewContractRepository(db *pgxpool.Pool) *ContractRepository {
+	return &ContractRepository{db}
+}
+
+func (c *ContractRepository) RollbackQuietly(tx pgx.Tx, ctx context.Context) {
+	err := tx.Rollback(ctx)
+	if err != nil {
+		log.Warn(err)
+	}
+}
+
+func (c *ContractRepository) WithTransaction(ctx context.Context) (pgx.Tx, error) {
+	return c.db.BeginTx(ctx, pgx.TxOptions{})
+}
+
+func (c *ContractRepository) FindContracts(ctx context.Context, contractorId int64) ([]model.Contract, error) {
+	query := `select ` + contractColumns + ` from contractors_contractor_contract ct
+				where ct.contractor_id = :contractor_id and ct.is_delete = false
+				order by ct.start_date desc, ct.id desc`
+
+	res, err := QueryWithMap(c.db, ctx, query, map[string]interface{}{"contractor_id": contractorId}).
+		ReadAll(model.Contract{})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.([]model.Contract), nil
+}
+
+func (c *ContractRepository) GetContract(ctx context.Context, contractorId int64, id int64) (model.Contract, error) {
+	query := `select ` + contractColumns + ` from contractors_contractor_contract ct
+				where ct.id = :id and ct.contractor_id = :contractor_id and ct.is_delete = false`
+
+	res, err := QueryWithMap(c.db, ctx, query, map[string]interface{}{
+		"id":            id,
+		"contractor_id": contractorId,
+	}).Read(model.Contract{})
+	if err != nil {
+		return model.Contract{}, err
+	}
+	if res == nil {
+		return model.Contract{}, model.NewNotFoundError(model.EntityContract, id)
+	}
+
+	return *res.(*model.Contract), nil
+}
+
+func (c *ContractRepository) CreateContract(ctx context.Context, tx pgx.Tx, contract *model.Contract) error {
+	query := `insert into contractors_contractor_contract (
+					contractor_id, number, start_date, end_date, amount, currency, status
+				) values (
+					:contractor_id, :number, :start_date::date, :end_date::date, :amount::numeric, :currency, :status
+				) returning id, amount::text, created_at, updated_at`
+
+	finalQuery, queryArgs, err := InlineNamedPlaceholders(query, map[string]interface{}{
+		"contractor_id": contract.ContractorId,
+		"number":        contract.Number,
+		"start_date":    contract.StartDate,
+		"end_date":      contract.EndDate,
+		"amount":        contract.Amount,
+		"currency":      contract.Currency,
+		"status":        contract.Status,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = tx.QueryRow(ctx, finalQuery, queryArgs...).
+		Scan(&contract.Id, &contract.Amount, &contract.CreatedAt, &contract.UpdatedAt)
+	if err != nil {
+		return translateError(err, model.EntityContract)
+	}
+
+	return nil
+}
+
+// UpdateContract изменяет договор. При изменении даты окончания предупреждение об окончании отправляется заново.
+func (c *ContractRepository) UpdateContract(ctx context.Context, tx pgx.Tx, contract *model.Contract) error {
+	query := `update contractors_contractor_contract
+				set
+					number = 			:number,
+					start_date = 		:start_date::date,
+					end_date = 			:end_date::date,
+					amount = 			:amount::numeric,
+					currency = 			:currency,
+					status = 			:status,
+					expiry_warned_at = 	case when end_date is distinct from :end_date::date
+											then null else expiry_warned_at end
+				where id = :id and contractor_id = :contractor_id and is_delete = false
+				returning amount::text, expiry_warned_at, created_at, updated_at`
+
+	finalQuery, queryArgs, err := InlineNamedPlaceholders(query, map[string]interface{}{
+		"number":        contract.Number,
+		"start_date":    contract.StartDate,
+		"end_date":      contract.EndDate,
+		"amount":        contract.Amount,
+		"currency":      contract.Currency,
+		"status":        contract.Status,
+		"id":            contract.Id,
+		"contractor_id": contract.ContractorId,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = tx.QueryRow(ctx, finalQuery, queryArgs...).
+		Scan(&contract.Amount, &contract.ExpiryWarnedAt, &contract.CreatedAt, &contract.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return model.NewNotFoundError(model.EntityContract, contract.Id)
+	}
+	if err != nil {
+		return translateError(err, model.EntityContract)
+	}
+
+	return nil
+}
+
+func (c *ContractRepository) DeleteContract(ctx context.Context, tx pgx.Tx, contractorId int64, id int64) error {
+	query := `update contractors_contractor_contract
+				set is_delete = true
+				where id = :id and contractor_id = :contractor_id and is_delete = false`
+
+	finalQuery, queryArgs, err := InlineNamedPlaceholders(query, map[string]interface{}{
+		"id":            id,
+		"contractor_id": contractorId,
+	})
+	if err != nil {
+		return err
+	}
+
+	tag, err := tx.Exec(ctx, finalQuery, queryArgs...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return model.NewNotFoundError(model.EntityContract, id)
+	}
+
+	return nil
+}
+
+func (c *ContractRepository) LockContractorsWithoutActiveContract(ctx context.Context, tx pgx.Tx,
+	today time.Time, withoutContracts bool, limit int) ([]int64, error) {
+	query := `select c.id from contractors_contractor c
+				where c.is_delete = false
+				  and c.status = :active_contractor
+				  and (:without_contracts::boolean or exists(
+						select 1 from contractors_contractor_contract ct
+						where ct.contractor_id = c.id and ct.is_delete = false
+					))
+				  and not exists(
+						select 1 from contractors_contractor_contract ct
+						where ct.contractor_id = c.id and ` + activeContractCondition + `
+					)
+				order by c.id
+				limit :limit
+				for update of c skip locked`
+
+	result := make([]int64, 0)
+	err := QueryWithMap(tx, ctx, query, map[string]interface{}{
+		"active_contractor": model.ContractorStatusActive,
+		"active_contract":   model.ContractStatusActive,
+		"without_contracts": withoutContracts,
+		"today":             today,
+		"limit":             limit,
+	}).ForEach(
+		model.NewSimpleModelProvider(func(reader model.DbModelReader) (interface{}, error) {
+			var id int64
+			err := reader.Scan(&id)
+			return id, err
+		}),
+		func(item interface{}) error {
+			result = append(result, item.(*model.SimpleModelProvider).Value().(int64))
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (c *ContractRepository) LockExpiringContracts(ctx context.Context, tx pgx.Tx, today time.Time,
+	until time.Time, limit int) ([]model.Contract, error) {
+	// Договор не считается последним, если его продолжает другой действующий договор контрагента
+	query := `select ` + contractColumns + ` from contractors_contractor_contract ct
+				join contractors_contractor c on c.id = ct.contractor_id
+				where ` + activeContractCondition + `
+				  and ct.expiry_warned_at is null
+				  and ct.end_date <= :until::date
+				  and c.is_delete = false
+				  and c.status = :active_contractor
+				  and not exists(
+						select 1 from contractors_contractor_contract n
+						where n.contractor_id = ct.contractor_id
+						  and n.id <> ct.id
+						  and n.is_delete = false
+						  and n.status = :active_contract
+						  and n.start_date <= ct.end_date + 1
+						  and (n.end_date is null or n.end_date > ct.end_date)
+					)
+				order by ct.end_date, ct.id
+				limit :limit
+				for update of ct skip locked`
+
+	res, err := QueryWithMap(tx, ctx, query, map[string]interface{}{
+		"active_contractor": model.ContractorStatusActive,
+		"active_contract":   model.ContractStatusActive,
+		"today":             today,
+		"until":             until,
+		"limit":             limit,
+	}).ReadAll(model.Contract{})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.([]model.Contract), nil
+}
+
+func (c *ContractRepository) MarkContractExpiryWarned(ctx context.Context, tx pgx.Tx, id int64) error {
+	query := `update contractors_contractor_contract set expiry_warned_at = now() where id = :id`
+
+	finalQuery, queryArgs, err := InlineNamedPlaceholders(query, map[string]interface{}{"id": id})
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, finalQuery, queryArgs...)
+	return err
+}
@@ -2,10 +2,12 @@ package postgres
 
 import (
 	"context"
+	"fmt"
 	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 	log "github.com/sirupsen/logrus"
 	"service_admin_contractor/domain/model"
+	"time"
 )
 
 type ContractorRepository struct {
@@ -35,22 +37,15 @@ func (c *ContractorRepository) FindContractors(ctx context.Context,
 	params model.ContractorSearchParameters) ([]model.Contractor, int64, error) {
 	args := model.NamedArguments{}
 	queryTotal := `select count(*)`
-	querySelect := `select c.id, c.resident, c.bin, c.name, c.email, c.block_date, c.status,
-							(
-								SELECT
-									JSON_AGG(e.*)
-								FROM contractors_contractor_employee e WHERE e.contractor_id = c.id and e.is_delete = false
-							) as employees,c.agent_name,c.agent_position`
+	querySelect := `select ` + contractorColumns
 	queryFrom := ` from contractors_contractor c`
-	filters := ` where 1=1 and c.is_delete = false`
-
-	AppendEqualsFilter(&filters, args, "c.bin", params.Bin)
-	AppendStringLikeFilter(&filters, args, "c.name", params.Name, "%s%%")
-	AppendStringLikeFilter(&filters, args, "c.email", params.Email, "%s%%")
-	AppendEqualsFilter(&filters, args, "c.status", params.Status)
+	filters, err := contractorSearchFilters(args, params)
+	if err != nil {
+		return nil, 0, err
+	}
 
 	var total int64
-	_, err := QueryWithMap(c.db, ctx, queryTotal+queryFrom+filters, args).Scan(&total)
+	_, err = QueryWithMap(c.db, ctx, queryTotal+queryFrom+filters, args).Scan(&total)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -67,18 +62,165 @@ func (c *ContractorRepository) FindContractors(ctx context.Context,
 		return nil, 0, err
 	}
 
-	return result.([]model.Contractor), total, nil
+	contractors := result.([]model.Contractor)
+	if params.WithEmployees {
+		if err = c.loadEmployees(ctx, contractors); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	if err = c.loadTags(ctx, contractors); err != nil {
+		return nil, 0, err
+	}
+
+	return contractors, total, nil
+}
+
+// StreamContractors передает в fn всех контрагентов, удовлетворяющих фильтрам, читая их из курсора по одному.
+// Пагинация не применяется.
+func (c *ContractorRepository) StreamContractors(ctx context.Context, params model.ContractorSearchParameters,
+	fn func(contractor model.Contractor) error) error {
+	args := model.NamedArguments{}
+	filters, err := contractorSearchFilters(args, params)
+	if err != nil {
+		return err
+	}
+
+	query := `select ` + contractorColumns + ` from contractors_contractor c` + filters + ` order by c.id`
+
+	return QueryWithMap(c.db, ctx, query, args).ForEach(model.Contractor{}, func(item interface{}) error {
+		return fn(*item.(*model.Contractor))
+	})
+}
+
+// StreamContractorEmployees передает в fn контрагентов, удовлетворяющих фильтрам, развернутых по сотрудникам.
+// Контрагент без сотрудников передается одной строкой без сотрудника.
+func (c *ContractorRepository) StreamContractorEmployees(ctx context.Context, params model.ContractorSearchParameters,
+	fn func(row model.ContractorEmployeeRow) error) error {
+	args := model.NamedArguments{}
+	filters, err := contractorSearchFilters(args, params)
+	if err != nil {
+		return err
+	}
+
+	query := `select ` + contractorColumns + `, ` + employeeColumns + `
+				from contractors_contractor c
+				left join contractors_contractor_employee e on e.contractor_id = c.id and e.is_delete = false` +
+		filters + ` order by c.id, e.id`
+
+	return QueryWithMap(c.db, ctx, query, args).ForEach(model.ContractorEmployeeRow{}, func(item interface{}) error {
+		return fn(*item.(*model.ContractorEmployeeRow))
+	})
+}
+
+func (c *ContractorRepository) FindContractorFacets(ctx context.Context,
+	params model.ContractorSearchParameters) (map[model.ContractorFacet][]model.FacetValue, error) {
+	args := model.NamedArguments{}
+	filters, err := contractorSearchFilters(args, params)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[model.ContractorFacet][]model.FacetValue, len(params.Facets))
+	for _, facet := range params.Facets {
+		expression, ok := contractorFacetExpressions[facet]
+		if !ok {
+			return nil, fmt.Errorf("неизвестный агрегат '%s'", facet)
+		}
+
+		query := `select ` + expression + `, count(*) from contractors_contractor c` + filters + ` group by 1 order by 1`
+
+		values, err := QueryWithMap(c.db, ctx, query, args).ReadAll(model.FacetValue{})
+		if err != nil {
+			return nil, err
+		}
+
+		result[facet] = values.([]model.FacetValue)
+	}
+
+	return result, nil
+}
+
+// contractorFacetExpressions содержит выражения группировки для каждого агрегата
+var contractorFacetExpressions = map[model.ContractorFacet]string{
+	model.ContractorFacetStatus:   `c.status::text`,
+	model.ContractorFacetResident: `c.resident::text`,
+	model.ContractorFacetEmployees: `exists(
+										select 1 from contractors_contractor_employee e
+										where e.contractor_id = c.id and e.is_delete = false
+									)::text`,
+}
+
+// contractorRsqlColumns сопоставляет поля model.ContractorRsqlFields колонкам таблицы контрагентов
+var contractorRsqlColumns = map[string]string{
+	"id":            "c.id",
+	"parentId":      "c.parent_id",
+	"resident":      "c.resident",
+	"bin":           "c.bin",
+	"country":       "c.country",
+	"foreignTaxId":  "c.foreign_tax_id",
+	"name":          "c.name",
+	"email":         "c.email",
+	"agentName":     "c.agent_name",
+	"agentPosition": "c.agent_position",
+	"blockDate":     "c.block_date",
+	"createdAt":     "c.created_at",
+	"updatedAt":     "c.updated_at",
+	"status":        "c.status",
+}
+
+// contractorSearchFilters формирует условия поиска контрагентов, общие для выборки и агрегатов
+func contractorSearchFilters(args model.NamedArguments, params model.ContractorSearchParameters) (string, error) {
+	filters := ` where 1=1`
+	AppendEqualsFilter(&filters, args, "c.is_delete", &params.Deleted)
+
+	AppendEqualsFilter(&filters, args, "c.bin", params.Bin)
+	AppendStringLikeFilter(&filters, args, "c.name", params.Name, "%s%%")
+	AppendStringLikeFilter(&filters, args, "c.email", params.Email, "%s%%")
+	AppendEqualsFilter(&filters, args, "c.status", params.Status)
+	appendContractorCountryFilter(&filters, args, params.Countries)
+	appendContractorTagFilter(&filters, args, params.TagIds)
+
+	err := AppendRsqlFilter(&filters, args, params.Filter, model.ContractorRsqlFields, contractorRsqlColumns)
+	if err != nil {
+		return "", err
+	}
+
+	return filters, nil
+}
+
+// appendContractorCountryFilter отбирает контрагентов из любой из стран countries
+func appendContractorCountryFilter(filters *string, args model.NamedArguments, countries []string) {
+	if len(countries) == 0 {
+		return
+	}
+
+	filterKey := genFilterKey(args)
+	args[filterKey] = countries
+	*filters = *filters + fmt.Sprintf(` and c.country = any(:%s)`, filterKey)
+}
+
+// appendContractorTagFilter отбирает контрагентов, которым назначены все теги tagIds. ИД тегов не повторяются.
+func appendContractorTagFilter(filters *string, args model.NamedArguments, tagIds []int64) {
+	if len(tagIds) == 0 {
+		return
+	}
+
+	idsKey := genFilterKey(args)
+	args[idsKey] = tagIds
+	countKey := genFilterKey(args)
+	args[countKey] = len(tagIds)
+
+	*filters = *filters + fmt.Sprintf(` and (
+				select count(distinct ct.tag_id) from contractors_contractor_tag ct
+				where ct.contractor_id = c.id and ct.tag_id = any(:%s)) = :%s`,
+		idsKey, countKey)
 }
 
 func (c *ContractorRepository) GetContractor(ctx context.Context, id int64) (model.Contractor, error) {
 	args := make(model.NamedArguments)
 	args["id"] = id
-	query := `SELECT c.id, c.resident, c.bin, c.name, c.email, c.block_date, c.status,
-					(
-						SELECT
-							JSON_AGG(e.*)
-						FROM contractors_contractor_employee e WHERE e.contractor_id = c.id and e.is_delete = false
-					) as employees,c.agent_name,c.agent_position
+	query := `SELECT ` + contractorColumns + `
 				FROM contractors_contractor c
 						 where c.id = :id and c.is_delete = false`
 	res, err := QueryWithMap(c.db, ctx, query, args).Read(model.Contractor{})
@@ -86,9 +228,195 @@ func (c *ContractorRepository) GetContractor(ctx context.Context, id int64) (mod
 		return model.Contractor{}, err
 	}
 
+	if res == nil {
+		return model.Contractor{}, model.NewNotFoundError(model.EntityContractor, id)
+	}
+	contractor := c.unwrapContractorSlice(res)
+
+	contractors := []model.Contractor{contractor}
+	if err = c.loadEmployees(ctx, contractors); err != nil {
+		return model.Contractor{}, err
+	}
+
+	if err = c.loadTags(ctx, contractors); err != nil {
+		return model.Contractor{}, err
+	}
+
+	return contractors[0], nil
+}
+
+// LockContractor блокирует строку контрагента до конца транзакции tx и возвращает его без сотрудников и тегов.
+// Удаленный контрагент тоже возвращается, чтобы его можно было проверить перед восстановлением.
+func (c *ContractorRepository) LockContractor(ctx context.Context, tx pgx.Tx, id int64) (model.Contractor, error) {
+	query := `select ` + contractorColumns + ` from contractors_contractor c where c.id = :id for update`
+
+	res, err := QueryWithMap(tx, ctx, query, map[string]interface{}{"id": id}).Read(model.Contractor{})
+	if err != nil {
+		return model.Contractor{}, err
+	}
+
+	if res == nil {
+		return model.Contractor{}, model.NewNotFoundError(model.EntityContractor, id)
+	}
+
 	return c.unwrapContractorSlice(res), nil
 }
 
+// ShareLockContractor блокирует строку неудаленного контрагента id от изменения до конца транзакции tx.
+// Учитывает изменения, сделанные в самой транзакции tx.
+func (c *ContractorRepository) ShareLockContractor(ctx context.Context, tx pgx.Tx, id int64) error {
+	query := `select c.id from contractors_contractor c where c.id = :id and c.is_delete = false for share`
+
+	ids, err := readIds(ctx, tx, query, map[string]interface{}{"id": id})
+	if err != nil {
+		return err
+	}
+
+	if len(ids) == 0 {
+		return model.NewNotFoundError(model.EntityContractor, id)
+	}
+
+	return nil
+}
+
+// FindContractorIds возвращает ИД не более limit контрагентов, удовлетворяющих фильтрам. Пагинация не применяется.
+func (c *ContractorRepository) FindContractorIds(ctx context.Context,
+	params model.ContractorSearchParameters, limit int) ([]int64, error) {
+	args := model.NamedArguments{}
+	filters, err := contractorSearchFilters(args, params)
+	if err != nil {
+		return nil, err
+	}
+
+	args["limit"] = limit
+	query := `select c.id from contractors_contractor c` + filters + ` order by c.id limit :limit`
+
+	result := make([]int64, 0)
+	err = QueryWithMap(c.db, ctx, query, args).ForEach(
+		model.NewSimpleModelProvider(func(reader model.DbModelReader) (interface{}, error) {
+			var id int64
+			err := reader.Scan(&id)
+			return id, err
+		}),
+		func(item interface{}) error {
+			result = append(result, item.(*model.SimpleModelProvider).Value().(int64))
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FindExistingEmails возвращает email-ы из списка, уже занятые неудаленными контрагентами или сотрудниками.
+// Сравнение производится без учета регистра.
+func (c *ContractorRepository) FindExistingEmails(ctx context.Context, emails []string) ([]string, error) {
+	lowerEmails := make([]string, len(emails))
+	for i, email := range emails {
+		lowerEmails[i] = model.NormalizeEmail(email)
+	}
+
+	query := `select lower(c.email) from contractors_contractor c
+				where c.is_delete = false and lower(c.email) = any(:emails)
+				union
+				select lower(e.email) from contractors_contractor_employee e
+				where e.is_delete = false and lower(e.email) = any(:emails)`
+
+	result := make([]string, 0)
+	res, err := QueryWithMap(c.db, ctx, query, map[string]interface{}{"emails": lowerEmails}).
+		ReadAll(model.NewSimpleModelProvider(func(reader model.DbModelReader) (interface{}, error) {
+			var email string
+			err := reader.Scan(&email)
+			return email, err
+		}))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range res.([]model.SimpleModelProvider) {
+		result = append(result, item.Value().(string))
+	}
+
+	return result, nil
+}
+
+// contractorColumns перечисляет колонки в порядке, ожидаемом model.Contractor.ReadModel
+const contractorColumns = `c.id, c.resident, c.bin, c.name, c.email, c.block_date, c.status,
+							c.agent_name, c.agent_position, c.created_at, c.updated_at, c.parent_id,
+							c.country, c.foreign_tax_id, c.registration_address`
+
+// employeeColumns перечисляет колонки в порядке, ожидаемом model.Employee.ReadModel
+const employeeColumns = `e.id, e.contractor_id, e.email, e.full_name, e.position, e.block_date, e.status,
+							e.created_at, e.updated_at`
+
+// loadEmployees загружает сотрудников контрагентов одним запросом и распределяет их по контрагентам
+func (c *ContractorRepository) loadEmployees(ctx context.Context, contractors []model.Contractor) error {
+	if len(contractors) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(contractors))
+	for i := range contractors {
+		ids[i] = contractors[i].Id
+	}
+
+	query := `select ` + employeeColumns + `
+				from contractors_contractor_employee e
+				where e.contractor_id = any(:ids) and e.is_delete = false
+				order by e.contractor_id, e.id`
+
+	result, err := QueryWithMap(c.db, ctx, query, map[string]interface{}{"ids": ids}).ReadAll(model.Employee{})
+	if err != nil {
+		return err
+	}
+
+	employees := make(map[int64][]model.Employee)
+	for _, e := range result.([]model.Employee) {
+		employees[e.ContractorId] = append(employees[e.ContractorId], e)
+	}
+
+	for i := range contractors {
+		contractors[i].Employees = employees[contractors[i].Id]
+	}
+
+	return nil
+}
+
+// loadTags заполняет действующие теги контрагентов
+func (c *ContractorRepository) loadTags(ctx context.Context, contractors []model.Contractor) error {
+	if len(contractors) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(contractors))
+	for i := range contractors {
+		ids[i] = contractors[i].Id
+	}
+
+	query := `select ct.contractor_id, ` + tagColumns + `
+				from contractors_contractor_tag ct
+				join contractors_tag g on g.id = ct.tag_id and g.is_delete = false
+				where ct.contractor_id = any(:ids)
+				order by g.category, g.name`
+
+	result, err := QueryWithMap(c.db, ctx, query, map[string]interface{}{"ids": ids}).ReadAll(model.ContractorTag{})
+	if err != nil {
+		return err
+	}
+
+	tags := make(map[int64][]model.Tag)
+	for _, t := range result.([]model.ContractorTag) {
+		tags[t.ContractorId] = append(tags[t.ContractorId], t.Tag)
+	}
+
+	for i := range contractors {
+		contractors[i].Tags = tags[contractors[i].Id]
+	}
+
+	return nil
+}
+
 func (c *ContractorRepository) unwrapContractorSlice(res interface{}) model.Contractor {
 	if res == nil {
 		return model.Contractor{}
@@ -100,19 +428,26 @@ func (c *ContractorRepository) unwrapContractorSlice(res interface{}) model.Cont
 
 func (c *ContractorRepository) CreateContractor(ctx context.Context, tx pgx.Tx, contractor *model.Contractor) error {
 	query := `INSERT INTO contractors_contractor (
-					 resident, bin, name, email, status,agent_name,agent_position
+					 resident, bin, name, email, status,agent_name,agent_position, parent_id,
+					 country, foreign_tax_id, registration_address
 				) VALUES (
-					:resident, :bin, :name, :email, :status,:agent_name,:agent_position
+					:resident, :bin, :name, :email, :status,:agent_name,:agent_position, :parent_id,
+					:country, :foreign_tax_id, :registration_address
 				) RETURNING id`
 
+	contractor.Email = model.NormalizeEmail(contractor.Email)
 	finalQuery, queryArgs, err := InlineNamedPlaceholders(query, map[string]interface{}{
-		"resident":       contractor.Resident,
-		"bin":            contractor.Bin,
-		"name":           contractor.Name,
-		"email":          contractor.Email,
-		"status":         model.ContractorStatusActive,
-		"agent_name":     contractor.AgentName,
-		"agent_position": contractor.AgentPosition,
+		"resident":             contractor.Resident,
+		"bin":                  contractor.Bin,
+		"name":                 contractor.Name,
+		"email":                contractor.Email,
+		"status":               model.ContractorStatusActive,
+		"agent_name":           contractor.AgentName,
+		"agent_position":       contractor.AgentPosition,
+		"parent_id":            contractor.ParentId,
+		"country":              contractor.Country,
+		"foreign_tax_id":       contractor.ForeignTaxId,
+		"registration_address": contractor.RegistrationAddress,
 	})
 	if err != nil {
 		return err
@@ -120,12 +455,43 @@ func (c *ContractorRepository) CreateContractor(ctx context.Context, tx pgx.Tx,
 
 	err = tx.QueryRow(ctx, finalQuery, queryArgs...).Scan(&contractor.Id)
 	if err != nil {
-		return err
+		return c.translateContractorEmailError(ctx, err, contractor.Email, 0)
 	}
 
 	return nil
 }
 
+// translateContractorEmailError находит неудаленного контрагента, отличного от contractorId, с email-ом
+// записываемого контрагента. Для восстановления email берется из записи contractorId.
+func (c *ContractorRepository) translateContractorEmailError(ctx context.Context, err error, email string,
+	contractorId int64) error {
+	query := `select o.id, o.email from contractors_contractor o
+				where o.is_delete = false and o.id <> :id
+				  and lower(o.email) = coalesce(:email::varchar,
+					(select lower(r.email) from contractors_contractor r where r.id = :id))`
+
+	var emailArg *string
+	if email != "" {
+		emailArg = &email
+	}
+
+	return translateEmailError(ctx, c.db, err, model.EntityContractor, contractorEmailIndex, query,
+		map[string]interface{}{"id": contractorId, "email": emailArg})
+}
+
+// translateEmployeeEmailError находит неудаленного сотрудника того же контрагента, отличного от employeeId,
+// с email-ом записываемого сотрудника. Если contractorId равен 0, контрагент берется из записи employeeId.
+func (c *ContractorRepository) translateEmployeeEmailError(ctx context.Context, err error, email string,
+	contractorId int64, employeeId int64) error {
+	query := `select o.id, o.email from contractors_contractor_employee o
+				where o.is_delete = false and o.id <> :employee_id and lower(o.email) = :email
+				  and o.contractor_id = coalesce(nullif(:contractor_id::bigint, 0),
+					(select e.contractor_id from contractors_contractor_employee e where e.id = :employee_id))`
+
+	return translateEmailError(ctx, c.db, err, model.EntityEmployee, employeeEmailIndex, query,
+		map[string]interface{}{"email": email, "contractor_id": contractorId, "employee_id": employeeId})
+}
+
 func (c *ContractorRepository) UpdateContractorData(ctx context.Context, tx pgx.Tx, contractorId int64,
 	contractor *model.Contractor) error {
 	query := `UPDATE contractors_contractor 
@@ -137,46 +503,96 @@ func (c *ContractorRepository) UpdateContractorData(ctx context.Context, tx pgx.
 					block_date =	:block_date,
 					status = 		:status,
 					agent_name = 	:agent_name,
-					agent_position = :agent_position
-				WHERE ID = :id_value`
+					agent_position = :agent_position,
+					parent_id = 	:parent_id,
+					country = 		:country,
+					foreign_tax_id = :foreign_tax_id,
+					registration_address = :registration_address
+				WHERE ID = :id_value and is_delete = false`
+
+	contractor.Email = model.NormalizeEmail(contractor.Email)
+	finalQuery, queryArgs, err := InlineNamedPlaceholders(query, map[string]interface{}{
+		"resident":             contractor.Resident,
+		"bin":                  contractor.Bin,
+		"name":                 contractor.Name,
+		"email":                contractor.Email,
+		"block_date":           contractor.BlockDate,
+		"status":               contractor.Status,
+		"agent_name":           contractor.AgentName,
+		"agent_position":       contractor.AgentPosition,
+		"parent_id":            contractor.ParentId,
+		"country":              contractor.Country,
+		"foreign_tax_id":       contractor.ForeignTaxId,
+		"registration_address": contractor.RegistrationAddress,
+		"id_value":             contractorId,
+	})
+	if err != nil {
+		return err
+	}
+
+	tag, err := tx.Exec(ctx, finalQuery, queryArgs...)
+	if err != nil {
+		return c.translateContractorEmailError(ctx, err, contractor.Email, contractorId)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return model.NewNotFoundError(model.EntityContractor, contractorId)
+	}
+
+	return nil
+}
+
+// UpdateContractorStatus изменяет статус и дату блокировки действующего контрагента
+func (c *ContractorRepository) UpdateContractorStatus(ctx context.Context, tx pgx.Tx, contractorId int64,
+	status model.ContractorStatus, blockDate *time.Time) error {
+	query := `update contractors_contractor
+				set status = :status, block_date = :block_date
+				where id = :id and is_delete = false`
 
 	finalQuery, queryArgs, err := InlineNamedPlaceholders(query, map[string]interface{}{
-		"resident":       contractor.Resident,
-		"bin":            contractor.Bin,
-		"name":           contractor.Name,
-		"email":          contractor.Email,
-		"block_date":     contractor.BlockDate,
-		"status":         contractor.Status,
-		"agent_name":     contractor.AgentName,
-		"agent_position": contractor.AgentPosition,
-		"id_value":       contractorId,
+		"status":     status,
+		"block_date": blockDate,
+		"id":         contractorId,
 	})
 	if err != nil {
 		return err
 	}
 
-	_, err = tx.Exec(ctx, finalQuery, queryArgs...)
+	tag, err := tx.Exec(ctx, finalQuery, queryArgs...)
 	if err != nil {
 		return err
 	}
 
+	if tag.RowsAffected() == 0 {
+		return model.NewNotFoundError(model.EntityContractor, contractorId)
+	}
+
 	return nil
 }
 
-func (c *ContractorRepository) DeleteContractor(id int64) error {
-	query := `update contractors_contractor 
-				set is_delete = true where id = :id`
+// SetContractorDeleted помечает контрагента удаленным или восстанавливает его
+func (c *ContractorRepository) SetContractorDeleted(ctx context.Context, tx pgx.Tx, contractorId int64,
+	deleted bool) error {
+	query := `update contractors_contractor
+				set is_delete = :deleted
+				where id = :id and is_delete = :current`
 
 	finalQuery, queryArgs, err := InlineNamedPlaceholders(query, map[string]interface{}{
-		"id": id,
+		"deleted": deleted,
+		"current": !deleted,
+		"id":      contractorId,
 	})
 	if err != nil {
 		return err
 	}
 
-	_, err = c.db.Exec(context.Background(), finalQuery, queryArgs...)
+	tag, err := tx.Exec(ctx, finalQuery, queryArgs...)
 	if err != nil {
-		return err
+		return c.translateContractorEmailError(ctx, err, "", contractorId)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return model.NewNotFoundError(model.EntityContractor, contractorId)
 	}
 
 	return nil
@@ -190,6 +606,7 @@ func (c *ContractorRepository) CreateContractorEmployee(ctx context.Context, tx
 					:contractor_id, :email, :full_name, :position
 				) RETURNING id`
 
+	employee.Email = model.NormalizeEmail(employee.Email)
 	finalQuery, queryArgs, err := InlineNamedPlaceholders(query, map[string]interface{}{
 		"contractor_id": contractorId,
 		"email":         employee.Email,
@@ -202,11 +619,43 @@ func (c *ContractorRepository) CreateContractorEmployee(ctx context.Context, tx
 
 	err = tx.QueryRow(ctx, finalQuery, queryArgs...).Scan(&employee.Id)
 	if err != nil {
-		return err
+		return c.translateEmployeeEmailError(ctx, err, employee.Email, contractorId, 0)
 	}
+
 	return nil
 }
 
+// FindContractorEmployeeIds возвращает ИД неудаленных сотрудников контрагента по email-у в нижнем регистре
+func (c *ContractorRepository) FindContractorEmployeeIds(ctx context.Context, tx pgx.Tx,
+	contractorId int64) (map[string]int64, error) {
+	query := `select lower(e.email), e.id from contractors_contractor_employee e
+				where e.contractor_id = :contractor_id and e.is_delete = false`
+
+	res, err := QueryWithMap(tx, ctx, query, map[string]interface{}{"contractor_id": contractorId}).
+		ReadAll(model.NewSimpleModelProvider(func(reader model.DbModelReader) (interface{}, error) {
+			var value employeeEmailId
+			err := reader.Scan(&value.email, &value.id)
+			return value, err
+		}))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]int64)
+	for _, item := range res.([]model.SimpleModelProvider) {
+		value := item.Value().(employeeEmailId)
+		result[value.email] = value.id
+	}
+
+	return result, nil
+}
+
+type employeeEmailId struct {
+	email string
+	id    int64
+}
+
+// UpdateContractorEmployeeData обновляет данные сотрудника и заполняет employee.ContractorId
 func (c *ContractorRepository) UpdateContractorEmployeeData(ctx context.Context, tx pgx.Tx, employeeId int64,
 	employee *model.Employee) error {
 	query := `UPDATE contractors_contractor_employee 
@@ -216,8 +665,10 @@ func (c *ContractorRepository) UpdateContractorEmployeeData(ctx context.Context,
 					position = 		:position, 
 					block_date =	:block_date,
 					status = 		:status
-				WHERE ID = :id_value`
+				WHERE ID = :id_value and is_delete = false
+				RETURNING contractor_id`
 
+	employee.Email = model.NormalizeEmail(employee.Email)
 	finalQuery, queryArgs, err := InlineNamedPlaceholders(query, map[string]interface{}{
 		"email":      employee.Email,
 		"full_name":  employee.FullName,
@@ -230,31 +681,196 @@ func (c *ContractorRepository) UpdateContractorEmployeeData(ctx context.Context,
 		return err
 	}
 
+	err = tx.QueryRow(ctx, finalQuery, queryArgs...).Scan(&employee.ContractorId)
+	if err == pgx.ErrNoRows {
+		return model.NewNotFoundError(model.EntityEmployee, employeeId)
+	}
+	if err != nil {
+		return c.translateEmployeeEmailError(ctx, err, employee.Email, 0, employeeId)
+	}
+
+	return nil
+}
+
+// hierarchyLockNamespace отделяет advisory-блокировку изменения иерархии контрагентов от прочих блокировок в БД
+const hierarchyLockNamespace = 1002
+
+// maxHierarchyDepth ограничивает обход иерархии контрагентов вверх
+const maxHierarchyDepth = 100
+
+// LockContractorHierarchy сериализует изменения родителей контрагентов до конца транзакции, чтобы параллельные
+// изменения не образовали цикл
+func (c *ContractorRepository) LockContractorHierarchy(ctx context.Context, tx pgx.Tx) error {
+	query := `select pg_advisory_xact_lock(:namespace, 0)`
+
+	finalQuery, queryArgs, err := InlineNamedPlaceholders(query, map[string]interface{}{
+		"namespace": hierarchyLockNamespace,
+	})
+	if err != nil {
+		return err
+	}
+
 	_, err = tx.Exec(ctx, finalQuery, queryArgs...)
+	return err
+}
+
+// FindContractorDescendants возвращает и блокирует всех действующих потомков контрагента id
+func (c *ContractorRepository) FindContractorDescendants(ctx context.Context, tx pgx.Tx,
+	id int64) ([]model.Contractor, error) {
+	query := `with recursive descendants as (
+					select d.id from contractors_contractor d
+					where d.parent_id = :id and d.is_delete = false
+					union
+					select d.id from contractors_contractor d
+					join descendants a on d.parent_id = a.id
+					where d.is_delete = false
+				)
+				select ` + contractorColumns + ` from contractors_contractor c
+				where c.id in (select id from descendants) and c.id <> :id
+				order by c.id
+				for update of c`
+
+	res, err := QueryWithMap(tx, ctx, query, map[string]interface{}{"id": id}).ReadAll(model.Contractor{})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.([]model.Contractor), nil
+}
+
+// FindContractorDescendantIds возвращает ИД всех потомков контрагента id, в том числе удаленных и потомков
+// удаленных, чтобы проверка цикла учитывала ветви, которые могут быть восстановлены
+func (c *ContractorRepository) FindContractorDescendantIds(ctx context.Context, tx pgx.Tx,
+	id int64) ([]int64, error) {
+	query := `with recursive descendants as (
+					select d.id from contractors_contractor d
+					where d.parent_id = :id
+					union
+					select d.id from contractors_contractor d
+					join descendants a on d.parent_id = a.id
+				)
+				select id from descendants where id <> :id order by id`
+
+	return readIds(ctx, tx, query, map[string]interface{}{"id": id})
+}
+
+// FindContractorTree возвращает иерархию действующих контрагентов, в которую входит контрагент id,
+// начиная с ее корня. Узлы упорядочены по глубине. Обход не заходит повторно в уже пройденные узлы,
+// поэтому цикл, записанный в обход сервиса, не размножает узлы.
+func (c *ContractorRepository) FindContractorTree(ctx context.Context, id int64) ([]model.ContractorTreeNode, error) {
+	query := `with recursive ancestors as (
+					select a.id, a.parent_id, 0 as level, array[a.id] as path from contractors_contractor a
+					where a.id = :id and a.is_delete = false
+					union all
+					select a.id, a.parent_id, s.level + 1, s.path || a.id from contractors_contractor a
+					join ancestors s on a.id = s.parent_id
+					where a.is_delete = false and s.level < :max_depth and a.id <> all(s.path)
+				), root as (
+					select id from ancestors order by level desc limit 1
+				), tree as (
+					select r.id, 0 as depth, array[r.id] as path from root r
+					union all
+					select d.id, t.depth + 1, t.path || d.id from contractors_contractor d
+					join tree t on d.parent_id = t.id
+					where d.is_delete = false and t.depth < :max_depth and d.id <> all(t.path)
+				)
+				select ` + contractorColumns + `, t.depth from tree t
+				join contractors_contractor c on c.id = t.id
+				order by t.depth, c.name, c.id`
+
+	res, err := QueryWithMap(c.db, ctx, query, map[string]interface{}{
+		"id":        id,
+		"max_depth": maxHierarchyDepth,
+	}).ReadAll(model.ContractorTreeNode{})
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := res.([]model.ContractorTreeNode)
+	if len(nodes) == 0 {
+		return nil, model.NewNotFoundError(model.EntityContractor, id)
+	}
+
+	return nodes, nil
+}
+
+// emailLockNamespace отделяет advisory-блокировки email-ов от прочих блокировок в БД
+const emailLockNamespace = 1001
+
+// LockEmail блокирует email до конца транзакции, чтобы проверки его уникальности, не обеспеченные индексами,
+// выполнялись последовательно
+func (c *ContractorRepository) LockEmail(ctx context.Context, tx pgx.Tx, email string) error {
+	query := `select pg_advisory_xact_lock(:namespace, hashtext(:email))`
+
+	finalQuery, queryArgs, err := InlineNamedPlaceholders(query, map[string]interface{}{
+		"namespace": emailLockNamespace,
+		"email":     model.NormalizeEmail(email),
+	})
 	if err != nil {
 		return err
 	}
 
-	return nil
+	_, err = tx.Exec(ctx, finalQuery, queryArgs...)
+	return err
 }
 
-func (c *ContractorRepository) DeleteContractorEmployee(id int64) error {
+// FindContractorIdByEmail возвращает ИД неудаленного контрагента с email-ом без учета регистра или nil
+func (c *ContractorRepository) FindContractorIdByEmail(ctx context.Context, tx pgx.Tx, email string) (*int64, error) {
+	query := `select c.id from contractors_contractor c
+				where c.is_delete = false and lower(c.email) = :email`
+
+	var id int64
+	ok, err := QueryWithMap(tx, ctx, query, map[string]interface{}{"email": model.NormalizeEmail(email)}).Scan(&id)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	return &id, nil
+}
+
+// FindEmployeeByEmail возвращает неудаленного сотрудника любого контрагента с email-ом без учета регистра,
+// кроме сотрудника excludeId, или nil
+func (c *ContractorRepository) FindEmployeeByEmail(ctx context.Context, tx pgx.Tx, email string,
+	excludeId int64) (*model.Employee, error) {
+	query := `select ` + employeeColumns + ` from contractors_contractor_employee e
+				where e.is_delete = false and lower(e.email) = :email and e.id <> :exclude_id
+				order by e.id
+				limit 1`
+
+	res, err := QueryWithMap(tx, ctx, query, map[string]interface{}{
+		"email":      model.NormalizeEmail(email),
+		"exclude_id": excludeId,
+	}).Read(model.Employee{})
+	if err != nil || res == nil {
+		return nil, err
+	}
+
+	return res.(*model.Employee), nil
+}
+
+// DeleteContractorEmployee помечает сотрудника удаленным и возвращает ИД его контрагента
+func (c *ContractorRepository) DeleteContractorEmployee(ctx context.Context, tx pgx.Tx, id int64) (int64, error) {
 	query := `update contractors_contractor_employee 
-				set is_delete = true where id = :id`
+				set is_delete = true where id = :id and is_delete = false
+				returning contractor_id`
 
 	finalQuery, queryArgs, err := InlineNamedPlaceholders(query, map[string]interface{}{
 		"id": id,
 	})
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	_, err = c.db.Exec(context.Background(), finalQuery, queryArgs...)
+	var contractorId int64
+	err = tx.QueryRow(ctx, finalQuery, queryArgs...).Scan(&contractorId)
+	if err == pgx.ErrNoRows {
+		return 0, model.NewNotFoundError(model.EntityEmployee, id)
+	}
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	return nil
+	return contractorId, nil
 }
 
 func (c *ContractorRepository) CreateCredentials(ctx context.Context, tx pgx.Tx, credentials model.Credentials) error {
@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"context"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"service_admin_contractor/domain/model"
+)
+
+// documentColumns перечисляет колонки в порядке, ожидаемом model.Document.ReadModel
+const documentColumns = `d.id, d.contractor_id, d.type, d.number, d.issue_date, d.expiry_date, d.file_name,
+							d.content_type, d.size, d.sha256, d.storage_key, d.uploaded_by, d.uploaded_at`
+
+type DocumentRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewDocumentRepository(db *pgxpool.Pool) *DocumentRepository {
+	return &DocumentRepository{db}
+}
+
+func (d *DocumentRepository) FindDocuments(ctx context.Context, contractorId int64) ([]model.Document, error) {
+	query := `select ` + documentColumns + ` from contractors_contractor_document d
+				where d.contractor_id = :contractor_id and d.is_delete = false
+				order by d.uploaded_at desc, d.id desc`
+
+	res, err := QueryWithMap(d.db, ctx, query, map[string]interface{}{"contractor_id": contractorId}).
+		ReadAll(model.Document{})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.([]model.Document), nil
+}
+
+func (d *DocumentRepository) GetDocument(ctx context.Context, contractorId int64, id int64) (model.Document, error) {
+	query := `select ` + documentColumns + ` from contractors_contractor_document d
+				where d.id = :id and d.contractor_id = :contractor_id and d.is_delete = false`
+
+	res, err := QueryWithMap(d.db, ctx, query, map[string]interface{}{
+		"id":            id,
+		"contractor_id": contractorId,
+	}).Read(model.Document{})
+	if err != nil {
+		return model.Document{}, err
+	}
+	if res == nil {
+		return model.Document{}, model.NewNotFoundError(model.EntityDocument, id)
+	}
+
+	return *res.(*model.Document), nil
+}
+
+func (d *DocumentRepository) CreateDocument(ctx context.Context, document *model.Document) error {
+	query := `insert into contractors_contractor_document (
+					contractor_id, type, number, issue_date, expiry_date, file_name, content_type, size, sha256,
+					storage_key, uploaded_by
+				) values (
+					:contractor_id, :type, :number, :issue_date, :expiry_date, :file_name, :content_type, :size, :sha256,
+					:storage_key, :uploaded_by
+				) returning id, uploaded_at`
+
+	finalQuery, queryArgs, err := InlineNamedPlaceholders(query, map[string]interface{}{
+		"contractor_id": document.ContractorId,
+		"type":          document.Type,
+		"number":        document.Number,
+		"issue_date":    document.IssueDate,
+		"expiry_date":   document.ExpiryDate,
+		"file_name":     document.FileName,
+		"content_type":  document.ContentType,
+		"size":          document.Size,
+		"sha256":        document.Sha256,
+		"storage_key":   document.StorageKey,
+		"uploaded_by":   document.UploadedBy,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = d.db.QueryRow(ctx, finalQuery, queryArgs...).Scan(&document.Id, &document.UploadedAt)
+	if err != nil {
+		return translateError(err, model.EntityDocument)
+	}
+
+	return nil
+}
+
+// DeleteDocument помечает документ удаленным и возвращает его метаданные
+func (d *DocumentRepository) DeleteDocument(ctx context.Context, contractorId int64, id int64) (model.Document, error) {
+	query := `update contractors_contractor_document d
+				set is_delete = true
+				where d.id = :id and d.contractor_id = :contractor_id and d.is_delete = false
+				returning ` + documentColumns
+
+	res, err := QueryWithMap(d.db, ctx, query, map[string]interface{}{
+		"id":            id,
+		"contractor_id": contractorId,
+	}).Read(model.Document{})
+	if err != nil {
+		return model.Document{}, err
+	}
+	if res == nil {
+		return model.Document{}, model.NewNotFoundError(model.EntityDocument, id)
+	}
+
+	return *res.(*model.Document), nil
+}
@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgconn"
+	"service_admin_contractor/domain/model"
+)
+
+// Уникальные индексы email-ов неудаленных записей
+const (
+	contractorEmailIndex = "contractors_contractor_email_uindex"
+	employeeEmailIndex   = "contractors_contractor_employee_email_uindex"
+)
+
+// Коды ошибок Postgres класса 23 (Integrity Constraint Violation)
+const (
+	pgNotNullViolation    = "23502"
+	pgForeignKeyViolation = "23503"
+	pgUniqueViolation     = "23505"
+	pgCheckViolation      = "23514"
+	pgExclusionViolation  = "23P01"
+)
+
+// translateError преобразует ошибки Postgres, вызванные нарушением ограничений, в доменные ошибки
+func translateError(err error, entity string) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	switch pgErr.Code {
+	case pgUniqueViolation, pgForeignKeyViolation, pgExclusionViolation:
+		return &model.ConflictError{
+			Entity:  entity,
+			Field:   pgErr.ColumnName,
+			Message: constraintMessage(pgErr),
+			Err:     err,
+		}
+	case pgNotNullViolation, pgCheckViolation:
+		return model.NewValidationError(pgErr.ColumnName, constraintMessage(pgErr))
+	default:
+		return err
+	}
+}
+
+func constraintMessage(pgErr *pgconn.PgError) string {
+	if pgErr.Detail != "" {
+		return pgErr.Detail
+	}
+
+	return pgErr.Message
+}
+
+// translateEmailError преобразует нарушение уникального индекса email-а index в конфликт с записью, уже занявшей
+// email. Запрос lookup должен вернуть id и email этой записи; он выполняется вне транзакции, так как она прервана.
+// Прочие ошибки преобразуются translateError.
+func translateEmailError(ctx context.Context, db pgExecutor, err error, entity string, index string,
+	lookup string, args map[string]interface{}) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != pgUniqueViolation || pgErr.ConstraintName != index {
+		return translateError(err, entity)
+	}
+
+	conflict := &model.ConflictError{
+		Entity:  entity,
+		Field:   "email",
+		Message: "В базе уже есть такой email",
+		Err:     err,
+	}
+
+	var id int64
+	var email string
+	if ok, lookupErr := QueryWithMap(db, ctx, lookup, args).Scan(&id, &email); lookupErr == nil && ok {
+		conflict.Value = email
+		conflict.ConflictingId = &id
+		conflict.Message = fmt.Sprintf("email %s уже занят: %s с ИД %d", email, entity, id)
+	}
+
+	return conflict
+}
@@ -5,7 +5,9 @@ import (
 	"github.com/lib/pq"
 	"reflect"
 	"service_admin_contractor/domain/model"
+	"strconv"
 	"strings"
+	"time"
 )
 
 func EscapeLikeFilterValue(value string) string {
@@ -102,6 +104,168 @@ func AppendNotInListFilter(filters *string, args model.NamedArguments, columnNam
 	*filters = *filters + fmt.Sprintf(" and %s<>all(:%s)", columnName, filterKey)
 }
 
+// AppendRsqlFilter компилирует RSQL выражение в параметризованное условие.
+// columns сопоставляет поля выражения колонкам запроса.
+func AppendRsqlFilter(filters *string, args model.NamedArguments, node model.RsqlNode, fields model.RsqlFields,
+	columns map[string]string) error {
+	if node == nil {
+		return nil
+	}
+
+	condition, err := compileRsqlNode(args, node, fields, columns)
+	if err != nil {
+		return err
+	}
+
+	*filters = *filters + " and " + condition
+	return nil
+}
+
+func compileRsqlNode(args model.NamedArguments, node model.RsqlNode, fields model.RsqlFields,
+	columns map[string]string) (string, error) {
+	switch n := node.(type) {
+	case model.RsqlLogical:
+		conditions := make([]string, len(n.Children))
+		for i, child := range n.Children {
+			condition, err := compileRsqlNode(args, child, fields, columns)
+			if err != nil {
+				return "", err
+			}
+			conditions[i] = condition
+		}
+		return "(" + strings.Join(conditions, " "+string(n.Operator)+" ") + ")", nil
+	case model.RsqlComparison:
+		return compileRsqlComparison(args, n, fields, columns)
+	default:
+		return "", fmt.Errorf("неизвестный узел выражения %T", node)
+	}
+}
+
+func compileRsqlComparison(args model.NamedArguments, c model.RsqlComparison, fields model.RsqlFields,
+	columns map[string]string) (string, error) {
+	field, ok := fields[c.Selector]
+	if !ok {
+		return "", fmt.Errorf("фильтрация по полю '%s' не поддерживается", c.Selector)
+	}
+	columnName, ok := columns[c.Selector]
+	if !ok {
+		return "", fmt.Errorf("для поля '%s' не задана колонка", c.Selector)
+	}
+
+	switch c.Operator {
+	case model.RsqlIsNull:
+		if isNull, _ := strconv.ParseBool(c.Arguments[0]); isNull {
+			return fmt.Sprintf("%s is null", columnName), nil
+		}
+		return fmt.Sprintf("%s is not null", columnName), nil
+	case model.RsqlLike:
+		pattern := c.Arguments[0]
+		if !strings.Contains(pattern, model.RsqlWildcard) {
+			pattern = model.RsqlWildcard + pattern + model.RsqlWildcard
+		}
+		return appendRsqlLike(args, columnName, pattern, false), nil
+	case model.RsqlEqual, model.RsqlNotEqual:
+		negate := c.Operator == model.RsqlNotEqual
+		if field.Type == model.RsqlFieldString && strings.Contains(c.Arguments[0], model.RsqlWildcard) {
+			return appendRsqlLike(args, columnName, c.Arguments[0], negate), nil
+		}
+
+		filterKey, err := appendRsqlArgument(args, field, c.Arguments[0])
+		if err != nil {
+			return "", err
+		}
+		if negate {
+			return fmt.Sprintf("(%s is null or %s<>:%s)", columnName, columnName, filterKey), nil
+		}
+		return fmt.Sprintf("%s=:%s", columnName, filterKey), nil
+	case model.RsqlIn, model.RsqlNotIn:
+		filterKey, err := appendRsqlArrayArgument(args, field, c.Arguments)
+		if err != nil {
+			return "", err
+		}
+		if c.Operator == model.RsqlNotIn {
+			return fmt.Sprintf("(%s is null or not (%s = any(:%s)))", columnName, columnName, filterKey), nil
+		}
+		return fmt.Sprintf("%s = any(:%s)", columnName, filterKey), nil
+	}
+
+	operator, ok := rsqlSqlOperators[c.Operator]
+	if !ok {
+		return "", fmt.Errorf("неизвестный оператор '%s'", c.Operator)
+	}
+
+	filterKey, err := appendRsqlArgument(args, field, c.Arguments[0])
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s%s:%s", columnName, operator, filterKey), nil
+}
+
+var rsqlSqlOperators = map[model.RsqlOperator]string{
+	model.RsqlLessThan:       "<",
+	model.RsqlLessOrEqual:    "<=",
+	model.RsqlGreaterThan:    ">",
+	model.RsqlGreaterOrEqual: ">=",
+}
+
+func appendRsqlArgument(args model.NamedArguments, field model.RsqlField, value string) (string, error) {
+	converted, err := field.Convert(value)
+	if err != nil {
+		return "", err
+	}
+
+	filterKey := genFilterKey(args)
+	args[filterKey] = converted
+
+	return filterKey, nil
+}
+
+// appendRsqlArrayArgument передает значения списка одним параметром-массивом, тип элементов которого
+// соответствует типу поля
+func appendRsqlArrayArgument(args model.NamedArguments, field model.RsqlField, values []string) (string, error) {
+	var converted interface{}
+	switch field.Type {
+	case model.RsqlFieldInteger:
+		converted = make([]int64, len(values))
+	case model.RsqlFieldBoolean:
+		converted = make([]bool, len(values))
+	case model.RsqlFieldDate:
+		converted = make([]time.Time, len(values))
+	default:
+		converted = make([]string, len(values))
+	}
+
+	array := reflect.ValueOf(converted)
+	for i, value := range values {
+		item, err := field.Convert(value)
+		if err != nil {
+			return "", err
+		}
+		array.Index(i).Set(reflect.ValueOf(item))
+	}
+
+	filterKey := genFilterKey(args)
+	args[filterKey] = converted
+
+	return filterKey, nil
+}
+
+func appendRsqlLike(args model.NamedArguments, columnName string, pattern string, negate bool) string {
+	parts := strings.Split(strings.ToUpper(pattern), model.RsqlWildcard)
+	for i := range parts {
+		parts[i] = EscapeLikeFilterValue(parts[i])
+	}
+
+	filterKey := genFilterKey(args)
+	args[filterKey] = strings.Join(parts, "%")
+
+	if negate {
+		return fmt.Sprintf("(%s is null or upper(%s) not like :%s)", columnName, columnName, filterKey)
+	}
+	return fmt.Sprintf("upper(%s) like :%s", columnName, filterKey)
+}
+
 func AppendPagination(filters *string, args model.NamedArguments, pagination model.Pagination) {
 	args["limit"] = pagination.Limit()
 	args["offset"] = pagination.Offset()
@@ -0,0 +1,164 @@
+package postgres
+
+import (
+	"context"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	log "github.com/sirupsen/logrus"
+	"service_admin_contractor/domain/model"
+	"sort"
+	"time"
+)
+
+type OutboxRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewOutboxRepository(db *pgxpool.Pool) *OutboxRepository {
+	return &OutboxRepository{db}
+}
+
+func (o *OutboxRepository) RollbackQuietly(tx pgx.Tx, ctx context.Context) {
+	err := tx.Rollback(ctx)
+	if err != nil {
+		log.Warn(err)
+	}
+}
+
+func (o *OutboxRepository) WithTransaction(ctx context.Context) (pgx.Tx, error) {
+	return o.db.BeginTx(ctx, pgx.TxOptions{})
+}
+
+// CreateOutboxEvent записывает событие в outbox в транзакции изменения контрагента
+func (o *OutboxRepository) CreateOutboxEvent(ctx context.Context, tx pgx.Tx, event *model.OutboxEvent) error {
+	query := `insert into contractors_outbox (
+					event_id, event_type, event_version, aggregate_id, payload, occurred_at
+				) values (
+					:event_id, :event_type, :event_version, :aggregate_id, :payload, :occurred_at
+				) returning id`
+
+	finalQuery, queryArgs, err := InlineNamedPlaceholders(query, map[string]interface{}{
+		"event_id":      event.EventId,
+		"event_type":    event.Type,
+		"event_version": event.Version,
+		"aggregate_id":  event.AggregateId,
+		"payload":       event.Payload,
+		"occurred_at":   event.OccurredAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.QueryRow(ctx, finalQuery, queryArgs...).Scan(&event.Id)
+}
+
+// ClaimOutboxEvents захватывает до limit событий, готовых к доставке, переносом следующей попытки на lease вперед
+// и сразу фиксирует захват, чтобы доставка выполнялась вне транзакции. Для каждого контрагента выбирается
+// только самое раннее недоставленное событие: пока есть более раннее событие в статусе PENDING или FAILED,
+// следующие события контрагента не доставляются. Заблокированные другими экземплярами события пропускаются.
+func (o *OutboxRepository) ClaimOutboxEvents(ctx context.Context, limit int,
+	lease time.Duration) ([]model.OutboxEvent, error) {
+	query := `with claimed as (
+					select o.id from contractors_outbox o
+					where o.status = :pending
+					  and o.next_attempt_at <= now()
+					  and not exists(
+							select 1 from contractors_outbox p
+							where p.aggregate_id = o.aggregate_id and p.status <> :delivered and p.id < o.id
+						)
+					order by o.id
+					limit :limit
+					for update skip locked
+				)
+				update contractors_outbox o
+				set next_attempt_at = now() + make_interval(secs => :lease::double precision)
+				from claimed
+				where o.id = claimed.id
+				returning o.id, o.event_id, o.event_type, o.event_version, o.aggregate_id, o.payload,
+					o.occurred_at, o.attempts`
+
+	result, err := QueryWithMap(o.db, ctx, query, map[string]interface{}{
+		"pending":   model.OutboxStatusPending,
+		"delivered": model.OutboxStatusDelivered,
+		"limit":     limit,
+		"lease":     lease.Seconds(),
+	}).ReadAll(model.OutboxEvent{})
+	if err != nil {
+		return nil, err
+	}
+
+	events := result.([]model.OutboxEvent)
+	sort.Slice(events, func(i, j int) bool { return events[i].Id < events[j].Id })
+
+	return events, nil
+}
+
+func (o *OutboxRepository) MarkOutboxEventDelivered(ctx context.Context, tx pgx.Tx, id int64) error {
+	query := `update contractors_outbox
+				set status = :status, attempts = attempts + 1, delivered_at = now(), last_error = null
+				where id = :id`
+
+	finalQuery, queryArgs, err := InlineNamedPlaceholders(query, map[string]interface{}{
+		"status": model.OutboxStatusDelivered,
+		"id":     id,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, finalQuery, queryArgs...)
+	return err
+}
+
+// MarkOutboxEventFailed фиксирует неудачную попытку доставки. Если nextAttemptAt не задан,
+// попытки исчерпаны и событие переводится в статус FAILED.
+func (o *OutboxRepository) MarkOutboxEventFailed(ctx context.Context, tx pgx.Tx, id int64, lastError string,
+	nextAttemptAt *time.Time) error {
+	query := `update contractors_outbox
+				set status = :status, attempts = attempts + 1, last_error = :last_error,
+					next_attempt_at = coalesce(:next_attempt_at, next_attempt_at)
+				where id = :id`
+
+	status := model.OutboxStatusPending
+	if nextAttemptAt == nil {
+		status = model.OutboxStatusFailed
+	}
+
+	finalQuery, queryArgs, err := InlineNamedPlaceholders(query, map[string]interface{}{
+		"status":          status,
+		"last_error":      lastError,
+		"next_attempt_at": nextAttemptAt,
+		"id":              id,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, finalQuery, queryArgs...)
+	return err
+}
+
+// RequeueFailedOutboxEvents возвращает события в статусе FAILED контрагента aggregateId (всех контрагентов,
+// если nil) в PENDING со сброшенным счетчиком попыток и возвращает их количество
+func (o *OutboxRepository) RequeueFailedOutboxEvents(ctx context.Context, tx pgx.Tx,
+	aggregateId *int64) (int64, error) {
+	query := `update contractors_outbox
+				set status = :pending, attempts = 0, next_attempt_at = now()
+				where status = :failed and (:aggregate_id::bigint is null or aggregate_id = :aggregate_id)`
+
+	finalQuery, queryArgs, err := InlineNamedPlaceholders(query, map[string]interface{}{
+		"pending":      model.OutboxStatusPending,
+		"failed":       model.OutboxStatusFailed,
+		"aggregate_id": aggregateId,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	tag, err := tx.Exec(ctx, finalQuery, queryArgs...)
+	if err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
@@ -73,6 +73,10 @@ func (p pgRowsWrapper) Scan(dest ...interface{}) error {
 	return p.rows.Scan(dest...)
 }
 
+func (p pgRowsWrapper) Err() error {
+	return p.rows.Err()
+}
+
 //endregion
 
 func Query(con pgExecutor, ctx context.Context, query string, placeholders ...interface{}) persistence.DbScanner {
@@ -102,6 +106,6 @@ func InlineNamedPlaceholders(query string, placeholders map[string]interface{})
 	return persistence.InlineNamedPlaceholders(renamePlaceholder, query, placeholders)
 }
 
-func renamePlaceholder(i uint8) string {
+func renamePlaceholder(i int) string {
 	return fmt.Sprintf("$%d", i+1)
 }
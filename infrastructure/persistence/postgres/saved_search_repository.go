@@ -0,0 +1,108 @@
+package postgres
+
+import (
+	"context"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"service_admin_contractor/domain/model"
+)
+
+type SavedSearchRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewSavedSearchRepository(db *pgxpool.Pool) *SavedSearchRepository {
+	return &SavedSearchRepository{db}
+}
+
+func (s *SavedSearchRepository) FindSavedSearches(ctx context.Context, login string,
+	roles []model.RoleCode) ([]model.SavedSearch, error) {
+	roleCodes := make([]string, len(roles))
+	for i, role := range roles {
+		roleCodes[i] = string(role)
+	}
+
+	query := `select s.id, s.owner_login, s.name, s.shared_role, s.query, s.created_at
+				from contractors_saved_search s
+				where s.is_delete = false and (s.owner_login = :login or s.shared_role = any(:roles))
+				order by s.name, s.id`
+
+	result, err := QueryWithMap(s.db, ctx, query, map[string]interface{}{
+		"login": login,
+		"roles": roleCodes,
+	}).ReadAll(model.SavedSearch{})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.([]model.SavedSearch), nil
+}
+
+func (s *SavedSearchRepository) GetSavedSearch(ctx context.Context, id int64) (*model.SavedSearch, error) {
+	query := `select s.id, s.owner_login, s.name, s.shared_role, s.query, s.created_at
+				from contractors_saved_search s
+				where s.id = :id and s.is_delete = false`
+
+	res, err := QueryWithMap(s.db, ctx, query, map[string]interface{}{"id": id}).Read(model.SavedSearch{})
+	if err != nil || res == nil {
+		return nil, err
+	}
+
+	return res.(*model.SavedSearch), nil
+}
+
+func (s *SavedSearchRepository) CreateSavedSearch(ctx context.Context, search *model.SavedSearch) error {
+	query := `INSERT INTO contractors_saved_search (
+					owner_login, name, shared_role, query
+				) VALUES (
+					:owner_login, :name, :shared_role, :query
+				) RETURNING id, created_at`
+
+	finalQuery, queryArgs, err := InlineNamedPlaceholders(query, map[string]interface{}{
+		"owner_login": search.OwnerLogin,
+		"name":        search.Name,
+		"shared_role": search.SharedRole,
+		"query":       search.Query,
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.db.QueryRow(ctx, finalQuery, queryArgs...).Scan(&search.Id, &search.CreatedAt)
+}
+
+func (s *SavedSearchRepository) UpdateSavedSearch(ctx context.Context, search *model.SavedSearch) error {
+	query := `UPDATE contractors_saved_search
+				SET
+					name = 			:name,
+					shared_role = 	:shared_role,
+					query = 		:query
+				WHERE id = :id and is_delete = false`
+
+	finalQuery, queryArgs, err := InlineNamedPlaceholders(query, map[string]interface{}{
+		"name":        search.Name,
+		"shared_role": search.SharedRole,
+		"query":       search.Query,
+		"id":          search.Id,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(ctx, finalQuery, queryArgs...)
+	return err
+}
+
+func (s *SavedSearchRepository) DeleteSavedSearch(ctx context.Context, id int64) error {
+	query := `update contractors_saved_search
+				set is_delete = true where id = :id`
+
+	finalQuery, queryArgs, err := InlineNamedPlaceholders(query, map[string]interface{}{
+		"id": id,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(ctx, finalQuery, queryArgs...)
+	return err
+}
@@ -0,0 +1,299 @@
+package postgres
+
+import (
+	"context"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	log "github.com/sirupsen/logrus"
+	"service_admin_contractor/domain/model"
+	"strings"
+)
+
+// tagColumns перечисляет колонки в порядке, ожидаемом model.Tag.ReadModel
+const tagColumns = `g.id, g.category, g.name, g.description, g.created_at, g.updated_at`
+
+type TagRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewTagRepository(db *pgxpool.Pool) *TagRepository {
+	return &TagRepository{db}
+}
+
+func (t *TagRepository) RollbackQuietly(tx pgx.Tx, ctx context.Context) {
+	err := tx.Rollback(ctx)
+	if err != nil {
+		log.Warn(err)
+	}
+}
+
+func (t *TagRepository) WithTransaction(ctx context.Context) (pgx.Tx, error) {
+	return t.db.BeginTx(ctx, pgx.TxOptions{})
+}
+
+func (t *TagRepository) FindTags(ctx context.Context, category *string) ([]model.Tag, error) {
+	args := model.NamedArguments{}
+	filters := ` where g.is_delete = false`
+	AppendEqualsFilter(&filters, args, "lower(g.category)", lowerOptional(category))
+
+	query := `select ` + tagColumns + `, (
+					select count(*) from contractors_contractor_tag ct
+					join contractors_contractor c on c.id = ct.contractor_id and c.is_delete = false
+					where ct.tag_id = g.id
+				) from contractors_tag g` + filters + `
+				order by g.category, g.name`
+
+	result := make([]model.Tag, 0)
+	err := QueryWithMap(t.db, ctx, query, args).ForEach(
+		model.NewSimpleModelProvider(func(reader model.DbModelReader) (interface{}, error) {
+			tag := model.Tag{}
+			err := reader.Scan(&tag.Id, &tag.Category, &tag.Name, &tag.Description, &tag.CreatedAt, &tag.UpdatedAt,
+				&tag.UsageCount)
+			return tag, err
+		}),
+		func(item interface{}) error {
+			result = append(result, item.(*model.SimpleModelProvider).Value().(model.Tag))
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (t *TagRepository) GetTag(ctx context.Context, id int64) (model.Tag, error) {
+	query := `select ` + tagColumns + ` from contractors_tag g where g.id = :id and g.is_delete = false`
+
+	res, err := QueryWithMap(t.db, ctx, query, map[string]interface{}{"id": id}).Read(model.Tag{})
+	if err != nil {
+		return model.Tag{}, err
+	}
+	if res == nil {
+		return model.Tag{}, model.NewNotFoundError(model.EntityTag, id)
+	}
+
+	return *res.(*model.Tag), nil
+}
+
+func (t *TagRepository) FindExistingTagIds(ctx context.Context, ids []int64) ([]int64, error) {
+	query := `select g.id from contractors_tag g where g.id = any(:ids) and g.is_delete = false`
+
+	result := make([]int64, 0, len(ids))
+	err := QueryWithMap(t.db, ctx, query, map[string]interface{}{"ids": ids}).ForEach(
+		model.NewSimpleModelProvider(func(reader model.DbModelReader) (interface{}, error) {
+			var id int64
+			err := reader.Scan(&id)
+			return id, err
+		}),
+		func(item interface{}) error {
+			result = append(result, item.(*model.SimpleModelProvider).Value().(int64))
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (t *TagRepository) CreateTag(ctx context.Context, tx pgx.Tx, tag *model.Tag) error {
+	query := `insert into contractors_tag (category, name, description)
+				values (:category, :name, :description)
+				returning id, created_at, updated_at`
+
+	finalQuery, queryArgs, err := InlineNamedPlaceholders(query, map[string]interface{}{
+		"category":    tag.Category,
+		"name":        tag.Name,
+		"description": tag.Description,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = tx.QueryRow(ctx, finalQuery, queryArgs...).Scan(&tag.Id, &tag.CreatedAt, &tag.UpdatedAt)
+	if err != nil {
+		return translateError(err, model.EntityTag)
+	}
+
+	return nil
+}
+
+func (t *TagRepository) UpdateTag(ctx context.Context, tx pgx.Tx, tag *model.Tag) error {
+	query := `update contractors_tag
+				set
+					category = 		:category,
+					name = 			:name,
+					description = 	:description
+				where id = :id and is_delete = false
+				returning created_at, updated_at`
+
+	finalQuery, queryArgs, err := InlineNamedPlaceholders(query, map[string]interface{}{
+		"category":    tag.Category,
+		"name":        tag.Name,
+		"description": tag.Description,
+		"id":          tag.Id,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = tx.QueryRow(ctx, finalQuery, queryArgs...).Scan(&tag.CreatedAt, &tag.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return model.NewNotFoundError(model.EntityTag, tag.Id)
+	}
+	if err != nil {
+		return translateError(err, model.EntityTag)
+	}
+
+	return nil
+}
+
+func (t *TagRepository) DeleteTag(ctx context.Context, tx pgx.Tx, id int64) ([]int64, error) {
+	query := `update contractors_tag set is_delete = true where id = :id and is_delete = false`
+
+	finalQuery, queryArgs, err := InlineNamedPlaceholders(query, map[string]interface{}{"id": id})
+	if err != nil {
+		return nil, err
+	}
+
+	tag, err := tx.Exec(ctx, finalQuery, queryArgs...)
+	if err != nil {
+		return nil, err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return nil, model.NewNotFoundError(model.EntityTag, id)
+	}
+
+	query = `delete from contractors_contractor_tag where tag_id = :id returning contractor_id`
+
+	return readIds(ctx, tx, query, map[string]interface{}{"id": id})
+}
+
+func (t *TagRepository) FindContractorTags(ctx context.Context, contractorId int64) ([]model.Tag, error) {
+	query := `select ` + tagColumns + ` from contractors_tag g
+				join contractors_contractor_tag ct on ct.tag_id = g.id
+				where ct.contractor_id = :contractor_id and g.is_delete = false
+				order by g.category, g.name`
+
+	res, err := QueryWithMap(t.db, ctx, query, map[string]interface{}{"contractor_id": contractorId}).
+		ReadAll(model.Tag{})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.([]model.Tag), nil
+}
+
+func (t *TagRepository) FindContractorTagIds(ctx context.Context, tx pgx.Tx, contractorId int64) ([]int64, error) {
+	query := `select ct.tag_id from contractors_contractor_tag ct
+				where ct.contractor_id = :contractor_id
+				order by ct.tag_id`
+
+	return readIds(ctx, tx, query, map[string]interface{}{"contractor_id": contractorId})
+}
+
+// readIds выполняет в транзакции tx запрос, возвращающий один столбец ИД
+func readIds(ctx context.Context, tx pgx.Tx, query string, args map[string]interface{}) ([]int64, error) {
+	result := make([]int64, 0)
+	err := QueryWithMap(tx, ctx, query, args).ForEach(
+		model.NewSimpleModelProvider(func(reader model.DbModelReader) (interface{}, error) {
+			var id int64
+			err := reader.Scan(&id)
+			return id, err
+		}),
+		func(item interface{}) error {
+			result = append(result, item.(*model.SimpleModelProvider).Value().(int64))
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// AddContractorTag назначает тег контрагенту. Повторное назначение не является ошибкой.
+func (t *TagRepository) AddContractorTag(ctx context.Context, tx pgx.Tx, contractorId int64, tagId int64) error {
+	query := `insert into contractors_contractor_tag (contractor_id, tag_id)
+				values (:contractor_id, :tag_id)
+				on conflict (contractor_id, tag_id) do nothing`
+
+	finalQuery, queryArgs, err := InlineNamedPlaceholders(query, map[string]interface{}{
+		"contractor_id": contractorId,
+		"tag_id":        tagId,
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.Exec(ctx, finalQuery, queryArgs...); err != nil {
+		return translateError(err, model.EntityTag)
+	}
+
+	return nil
+}
+
+func (t *TagRepository) RemoveContractorTag(ctx context.Context, tx pgx.Tx, contractorId int64, tagId int64) error {
+	query := `delete from contractors_contractor_tag where contractor_id = :contractor_id and tag_id = :tag_id`
+
+	finalQuery, queryArgs, err := InlineNamedPlaceholders(query, map[string]interface{}{
+		"contractor_id": contractorId,
+		"tag_id":        tagId,
+	})
+	if err != nil {
+		return err
+	}
+
+	tag, err := tx.Exec(ctx, finalQuery, queryArgs...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return model.NewNotFoundError(model.EntityTag, tagId)
+	}
+
+	return nil
+}
+
+// ReplaceContractorTags заменяет теги контрагента набором tagIds
+func (t *TagRepository) ReplaceContractorTags(ctx context.Context, tx pgx.Tx, contractorId int64,
+	tagIds []int64) error {
+	if tagIds == nil {
+		tagIds = []int64{}
+	}
+
+	query := `delete from contractors_contractor_tag
+				where contractor_id = :contractor_id and tag_id <> all(:tag_ids)`
+
+	finalQuery, queryArgs, err := InlineNamedPlaceholders(query, map[string]interface{}{
+		"contractor_id": contractorId,
+		"tag_ids":       tagIds,
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.Exec(ctx, finalQuery, queryArgs...); err != nil {
+		return err
+	}
+
+	for _, tagId := range tagIds {
+		if err = t.AddContractorTag(ctx, tx, contractorId, tagId); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func lowerOptional(value *string) *string {
+	if value == nil {
+		return nil
+	}
+
+	lower := strings.ToLower(*value)
+	return &lower
+}
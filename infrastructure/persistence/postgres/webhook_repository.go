@@ -0,0 +1,408 @@
+package postgres
+
+import (
+	"context"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	log "github.com/sirupsen/logrus"
+	"service_admin_contractor/domain/model"
+	"sort"
+	"time"
+)
+
+type WebhookRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewWebhookRepository(db *pgxpool.Pool) *WebhookRepository {
+	return &WebhookRepository{db}
+}
+
+func (w *WebhookRepository) RollbackQuietly(tx pgx.Tx, ctx context.Context) {
+	err := tx.Rollback(ctx)
+	if err != nil {
+		log.Warn(err)
+	}
+}
+
+func (w *WebhookRepository) WithTransaction(ctx context.Context) (pgx.Tx, error) {
+	return w.db.BeginTx(ctx, pgx.TxOptions{})
+}
+
+const webhookSubscriptionColumns = `s.id, s.url, s.event_types, s.secret, s.active, s.created_by, s.created_at`
+
+const webhookDeliveryColumns = `d.id, d.subscription_id, d.event_id, d.event_type, d.payload, d.status, d.attempts,
+							d.next_attempt_at, d.last_status_code, d.last_error, d.created_at, d.delivered_at`
+
+func (w *WebhookRepository) FindWebhookSubscriptions(ctx context.Context) ([]model.WebhookSubscription, error) {
+	query := `select ` + webhookSubscriptionColumns + `
+				from contractors_webhook_subscription s
+				where s.is_delete = false
+				order by s.id`
+
+	result, err := QueryWithMap(w.db, ctx, query, map[string]interface{}{}).ReadAll(model.WebhookSubscription{})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.([]model.WebhookSubscription), nil
+}
+
+func (w *WebhookRepository) GetWebhookSubscription(ctx context.Context,
+	id int64) (model.WebhookSubscription, error) {
+	query := `select ` + webhookSubscriptionColumns + `
+				from contractors_webhook_subscription s
+				where s.id = :id and s.is_delete = false`
+
+	res, err := QueryWithMap(w.db, ctx, query, map[string]interface{}{"id": id}).Read(model.WebhookSubscription{})
+	if err != nil {
+		return model.WebhookSubscription{}, err
+	}
+
+	if res == nil {
+		return model.WebhookSubscription{}, model.NewNotFoundError(model.EntityWebhook, id)
+	}
+
+	return *res.(*model.WebhookSubscription), nil
+}
+
+func (w *WebhookRepository) CreateWebhookSubscription(ctx context.Context,
+	subscription *model.WebhookSubscription) error {
+	query := `insert into contractors_webhook_subscription (
+					url, event_types, secret, active, created_by
+				) values (
+					:url, :event_types, :secret, :active, :created_by
+				) returning id, created_at`
+
+	finalQuery, queryArgs, err := InlineNamedPlaceholders(query, map[string]interface{}{
+		"url":         subscription.Url,
+		"event_types": eventTypeNames(subscription.EventTypes),
+		"secret":      subscription.Secret,
+		"active":      subscription.Active,
+		"created_by":  subscription.CreatedBy,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = w.db.QueryRow(ctx, finalQuery, queryArgs...).Scan(&subscription.Id, &subscription.CreatedAt)
+	if err != nil {
+		return translateError(err, model.EntityWebhook)
+	}
+
+	return nil
+}
+
+func (w *WebhookRepository) UpdateWebhookSubscription(ctx context.Context,
+	subscription *model.WebhookSubscription) error {
+	query := `update contractors_webhook_subscription
+				set
+					url = 			:url,
+					event_types = 	:event_types,
+					secret = 		:secret,
+					active = 		:active
+				where id = :id and is_delete = false`
+
+	finalQuery, queryArgs, err := InlineNamedPlaceholders(query, map[string]interface{}{
+		"url":         subscription.Url,
+		"event_types": eventTypeNames(subscription.EventTypes),
+		"secret":      subscription.Secret,
+		"active":      subscription.Active,
+		"id":          subscription.Id,
+	})
+	if err != nil {
+		return err
+	}
+
+	tag, err := w.db.Exec(ctx, finalQuery, queryArgs...)
+	if err != nil {
+		return translateError(err, model.EntityWebhook)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return model.NewNotFoundError(model.EntityWebhook, subscription.Id)
+	}
+
+	return nil
+}
+
+func (w *WebhookRepository) DeleteWebhookSubscription(ctx context.Context, id int64) error {
+	query := `update contractors_webhook_subscription
+				set is_delete = true, active = false where id = :id and is_delete = false`
+
+	finalQuery, queryArgs, err := InlineNamedPlaceholders(query, map[string]interface{}{
+		"id": id,
+	})
+	if err != nil {
+		return err
+	}
+
+	tag, err := w.db.Exec(ctx, finalQuery, queryArgs...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return model.NewNotFoundError(model.EntityWebhook, id)
+	}
+
+	return nil
+}
+
+// CreateWebhookDeliveries создает доставки события всем активным подпискам на его тип.
+// Повторная обработка того же события не создает дублей.
+func (w *WebhookRepository) CreateWebhookDeliveries(ctx context.Context, event model.OutboxEvent,
+	payload []byte) error {
+	query := `insert into contractors_webhook_delivery (subscription_id, event_id, event_type, payload)
+				select s.id, :event_id::varchar, :event_type::varchar, :payload::jsonb
+				from contractors_webhook_subscription s
+				where s.is_delete = false and s.active = true and :event_type = any(s.event_types)
+				on conflict (subscription_id, event_id) do nothing`
+
+	finalQuery, queryArgs, err := InlineNamedPlaceholders(query, map[string]interface{}{
+		"event_id":   event.EventId,
+		"event_type": string(event.Type),
+		"payload":    payload,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = w.db.Exec(ctx, finalQuery, queryArgs...)
+	return err
+}
+
+// FindWebhookDeliveries возвращает доставки подписки от новых к старым вместе с журналом попыток
+func (w *WebhookRepository) FindWebhookDeliveries(ctx context.Context, subscriptionId int64,
+	pagination model.Pagination) ([]model.WebhookDelivery, int64, error) {
+	args := model.NamedArguments{"subscription_id": subscriptionId}
+	queryFrom := ` from contractors_webhook_delivery d where d.subscription_id = :subscription_id`
+
+	var total int64
+	_, err := QueryWithMap(w.db, ctx, `select count(*)`+queryFrom, args).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if total == 0 {
+		return []model.WebhookDelivery{}, 0, nil
+	}
+
+	paginated := queryFrom + ` order by d.id desc`
+	AppendPagination(&paginated, args, pagination)
+
+	result, err := QueryWithMap(w.db, ctx, `select `+webhookDeliveryColumns+paginated, args).
+		ReadAll(model.WebhookDelivery{})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	deliveries := result.([]model.WebhookDelivery)
+	if err = w.loadAttempts(ctx, deliveries); err != nil {
+		return nil, 0, err
+	}
+
+	return deliveries, total, nil
+}
+
+func (w *WebhookRepository) GetWebhookDelivery(ctx context.Context, subscriptionId int64,
+	id int64) (model.WebhookDelivery, error) {
+	query := `select ` + webhookDeliveryColumns + `
+				from contractors_webhook_delivery d
+				where d.id = :id and d.subscription_id = :subscription_id`
+
+	res, err := QueryWithMap(w.db, ctx, query, map[string]interface{}{
+		"id":              id,
+		"subscription_id": subscriptionId,
+	}).Read(model.WebhookDelivery{})
+	if err != nil {
+		return model.WebhookDelivery{}, err
+	}
+
+	if res == nil {
+		return model.WebhookDelivery{}, model.NewNotFoundError(model.EntityDelivery, id)
+	}
+
+	deliveries := []model.WebhookDelivery{*res.(*model.WebhookDelivery)}
+	if err = w.loadAttempts(ctx, deliveries); err != nil {
+		return model.WebhookDelivery{}, err
+	}
+
+	return deliveries[0], nil
+}
+
+func (w *WebhookRepository) loadAttempts(ctx context.Context, deliveries []model.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(deliveries))
+	for i := range deliveries {
+		ids[i] = deliveries[i].Id
+	}
+
+	query := `select a.id, a.delivery_id, a.attempted_at, a.status_code, a.error, a.duration_ms
+				from contractors_webhook_delivery_attempt a
+				where a.delivery_id = any(:ids)
+				order by a.delivery_id, a.id`
+
+	result, err := QueryWithMap(w.db, ctx, query, map[string]interface{}{"ids": ids}).
+		ReadAll(model.WebhookDeliveryAttempt{})
+	if err != nil {
+		return err
+	}
+
+	attempts := make(map[int64][]model.WebhookDeliveryAttempt)
+	for _, a := range result.([]model.WebhookDeliveryAttempt) {
+		attempts[a.DeliveryId] = append(attempts[a.DeliveryId], a)
+	}
+
+	for i := range deliveries {
+		deliveries[i].AttemptLog = attempts[deliveries[i].Id]
+	}
+
+	return nil
+}
+
+// ResetWebhookDelivery ставит доставку в очередь на немедленную повторную отправку с полным запасом попыток
+func (w *WebhookRepository) ResetWebhookDelivery(ctx context.Context, subscriptionId int64, id int64) error {
+	query := `update contractors_webhook_delivery
+				set status = :status, attempts = 0, next_attempt_at = now(), delivered_at = null
+				where id = :id and subscription_id = :subscription_id`
+
+	finalQuery, queryArgs, err := InlineNamedPlaceholders(query, map[string]interface{}{
+		"status":          model.WebhookDeliveryPending,
+		"id":              id,
+		"subscription_id": subscriptionId,
+	})
+	if err != nil {
+		return err
+	}
+
+	tag, err := w.db.Exec(ctx, finalQuery, queryArgs...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return model.NewNotFoundError(model.EntityDelivery, id)
+	}
+
+	return nil
+}
+
+// ClaimWebhookDeliveries захватывает до limit доставок, время отправки которых наступило, переносом следующей
+// попытки на lease вперед и сразу фиксирует захват, чтобы отправка выполнялась вне транзакции.
+// Доставки отключенных подписок пропускаются, заблокированные другими экземплярами - тоже.
+func (w *WebhookRepository) ClaimWebhookDeliveries(ctx context.Context, limit int,
+	lease time.Duration) ([]model.PendingWebhookDelivery, error) {
+	query := `with claimed as (
+					select d.id, d.subscription_id from contractors_webhook_delivery d
+					join contractors_webhook_subscription s on s.id = d.subscription_id
+					where d.status = :status and d.next_attempt_at <= now()
+					  and s.active = true and s.is_delete = false
+					order by d.next_attempt_at, d.id
+					limit :limit
+					for update of d skip locked
+				)
+				update contractors_webhook_delivery d
+				set next_attempt_at = now() + make_interval(secs => :lease::double precision)
+				from claimed
+				join contractors_webhook_subscription s on s.id = claimed.subscription_id
+				where d.id = claimed.id
+				returning d.id, d.subscription_id, d.event_id, d.event_type, d.payload, d.attempts, s.url, s.secret`
+
+	result, err := QueryWithMap(w.db, ctx, query, map[string]interface{}{
+		"status": model.WebhookDeliveryPending,
+		"limit":  limit,
+		"lease":  lease.Seconds(),
+	}).ReadAll(model.PendingWebhookDelivery{})
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := result.([]model.PendingWebhookDelivery)
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].Id < deliveries[j].Id })
+
+	return deliveries, nil
+}
+
+func (w *WebhookRepository) CreateWebhookDeliveryAttempt(ctx context.Context, tx pgx.Tx,
+	attempt *model.WebhookDeliveryAttempt) error {
+	query := `insert into contractors_webhook_delivery_attempt (
+					delivery_id, attempted_at, status_code, error, duration_ms
+				) values (
+					:delivery_id, :attempted_at, :status_code, :error, :duration_ms
+				) returning id`
+
+	finalQuery, queryArgs, err := InlineNamedPlaceholders(query, map[string]interface{}{
+		"delivery_id":  attempt.DeliveryId,
+		"attempted_at": attempt.AttemptedAt,
+		"status_code":  attempt.StatusCode,
+		"error":        attempt.Error,
+		"duration_ms":  attempt.Duration.Milliseconds(),
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.QueryRow(ctx, finalQuery, queryArgs...).Scan(&attempt.Id)
+}
+
+func (w *WebhookRepository) MarkWebhookDeliveryDelivered(ctx context.Context, tx pgx.Tx, id int64,
+	statusCode int) error {
+	query := `update contractors_webhook_delivery
+				set status = :status, attempts = attempts + 1, last_status_code = :status_code,
+					last_error = null, delivered_at = now()
+				where id = :id`
+
+	finalQuery, queryArgs, err := InlineNamedPlaceholders(query, map[string]interface{}{
+		"status":      model.WebhookDeliveryDelivered,
+		"status_code": statusCode,
+		"id":          id,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, finalQuery, queryArgs...)
+	return err
+}
+
+// MarkWebhookDeliveryFailed фиксирует неудачную попытку. Если nextAttemptAt не задан,
+// попытки исчерпаны и доставка переводится в статус FAILED.
+func (w *WebhookRepository) MarkWebhookDeliveryFailed(ctx context.Context, tx pgx.Tx, id int64, statusCode *int,
+	lastError string, nextAttemptAt *time.Time) error {
+	query := `update contractors_webhook_delivery
+				set status = :status, attempts = attempts + 1, last_status_code = :status_code,
+					last_error = :last_error, next_attempt_at = coalesce(:next_attempt_at, next_attempt_at)
+				where id = :id`
+
+	status := model.WebhookDeliveryPending
+	if nextAttemptAt == nil {
+		status = model.WebhookDeliveryFailed
+	}
+
+	finalQuery, queryArgs, err := InlineNamedPlaceholders(query, map[string]interface{}{
+		"status":          status,
+		"status_code":     statusCode,
+		"last_error":      lastError,
+		"next_attempt_at": nextAttemptAt,
+		"id":              id,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, finalQuery, queryArgs...)
+	return err
+}
+
+func eventTypeNames(eventTypes []model.EventType) []string {
+	result := make([]string, len(eventTypes))
+	for i, eventType := range eventTypes {
+		result[i] = string(eventType)
+	}
+
+	return result
+}
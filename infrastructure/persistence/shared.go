@@ -7,7 +7,6 @@ import (
 	"reflect"
 	"regexp"
 	"service_admin_contractor/domain/model"
-	"strings"
 )
 
 type AnonymousModelProvider func(dest ...interface{}) error
@@ -41,12 +40,25 @@ type DbScanner interface {
 	//     ok, _ := PgQuery(db, ctx, query).Scan(&result)
 	//
 	Scan(dest ...interface{}) (bool, error)
+
+	// ForEach последовательно конвертирует записи в модели и передает их в fn, не загружая весь набор
+	// результатов в память. Обработка прекращается при первой ошибке, возвращенной fn.
+	//
+	// Пример использования:
+	//
+	//     err := s.ForEach(SampleModel{}, func(tmp interface{}) error {
+	//         result := tmp.(*SampleModel)
+	//         ...
+	//     })
+	//
+	ForEach(mp model.DbModelProvider, fn func(item interface{}) error) error
 }
 
 type DbRows interface {
 	model.DbModelReader
 	Close() error
 	Next() bool
+	Err() error
 }
 
 //region DbScannerWithError
@@ -70,6 +82,10 @@ func (s *dbScannerWithError) Scan(...interface{}) (bool, error) {
 	return false, s.err
 }
 
+func (s *dbScannerWithError) ForEach(model.DbModelProvider, func(item interface{}) error) error {
+	return s.err
+}
+
 //endregion
 
 //region DefaultDbScanner
@@ -133,14 +149,31 @@ func (s *defaultDbScanner) Scan(dest ...interface{}) (bool, error) {
 	return false, nil
 }
 
+func (s *defaultDbScanner) ForEach(mp model.DbModelProvider, fn func(item interface{}) error) error {
+	defer s.rows.Close()
+
+	for s.rows.Next() {
+		l, err := mp.ReadModel(s.rows)
+		if err != nil {
+			return err
+		}
+
+		if err = fn(l); err != nil {
+			return err
+		}
+	}
+
+	return s.rows.Err()
+}
+
 //endregion
 
 // Преобразует запрос с именованными параметрами вида ":name" в запрос с позиционными параметрами
-func InlineNamedPlaceholders(renamePlaceholder func(i uint8) string, query string, placeholders map[string]interface{}) (string, []interface{}, error) {
+func InlineNamedPlaceholders(renamePlaceholder func(i int) string, query string, placeholders map[string]interface{}) (string, []interface{}, error) {
 	var re = regexp.MustCompile(`(?m)[^:](:\w+)`)
 
-	paramN := uint8(0)
-	paramKeys := make(map[string]uint8)
+	paramN := 0
+	paramKeys := make(map[string]int)
 	for _, match := range re.FindAllStringSubmatch(query, -1) {
 		pKey := match[1]
 		if _, ok := paramKeys[pKey]; !ok {
@@ -149,10 +182,8 @@ func InlineNamedPlaceholders(renamePlaceholder func(i uint8) string, query strin
 		}
 	}
 
-	inlineQuery := query
 	inlinePlaceholders := make([]interface{}, paramN)
 	for pKey, pN := range paramKeys {
-		inlineQuery = strings.ReplaceAll(inlineQuery, pKey, renamePlaceholder(pN))
 		if placeholderValue, ok := placeholders[pKey[1:]]; ok {
 			inlinePlaceholders[pN] = placeholderValue
 		} else {
@@ -160,6 +191,11 @@ func InlineNamedPlaceholders(renamePlaceholder func(i uint8) string, query strin
 		}
 	}
 
+	// Замена производится по совпадениям целиком, чтобы ':filter1' не затронул ':filter10'
+	inlineQuery := re.ReplaceAllStringFunc(query, func(match string) string {
+		return match[:1] + renamePlaceholder(paramKeys[match[1:]])
+	})
+
 	return inlineQuery, inlinePlaceholders, nil
 }
 
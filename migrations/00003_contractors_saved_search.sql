@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+create table if not exists contractors_saved_search
+(
+    id bigserial
+    constraint contractors_saved_search_pk
+    primary key,
+    owner_login varchar not null,
+    name varchar not null,
+    shared_role varchar,
+    query varchar not null,
+    created_at timestamp with time zone default now() not null,
+    is_delete boolean default false not null
+);
+
+create index if not exists contractors_saved_search_owner_login_index
+    on contractors_saved_search (owner_login);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS contractors_saved_search;
+-- +goose StatementEnd
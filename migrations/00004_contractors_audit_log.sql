@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+create table if not exists contractors_audit_log
+(
+    id bigserial
+    constraint contractors_audit_log_pk
+    primary key,
+    entity varchar not null,
+    entity_id bigint not null,
+    action varchar not null,
+    actor_login varchar,
+    details jsonb,
+    created_at timestamp with time zone default now() not null
+);
+
+create index if not exists contractors_audit_log_entity_index
+    on contractors_audit_log (entity, entity_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS contractors_audit_log;
+-- +goose StatementEnd
@@ -0,0 +1,107 @@
+-- +goose Up
+-- +goose StatementBegin
+alter table contractors_contractor
+    add column if not exists created_at timestamp with time zone default now() not null,
+    add column if not exists updated_at timestamp with time zone default now() not null;
+
+alter table contractors_contractor_employee
+    add column if not exists created_at timestamp with time zone default now() not null,
+    add column if not exists updated_at timestamp with time zone default now() not null;
+
+create table if not exists contractors_change_log
+(
+    id bigserial
+    constraint contractors_change_log_pk
+    primary key,
+    tx_id bigint default txid_current() not null,
+    entity varchar not null,
+    entity_id bigint not null,
+    contractor_id bigint,
+    operation varchar not null,
+    changed_at timestamp with time zone default now() not null
+);
+
+create index if not exists contractors_change_log_tx_id_index
+    on contractors_change_log (tx_id, id);
+
+-- contractors_touch_updated_at обновляет updated_at при любом фактическом изменении записи
+create or replace function contractors_touch_updated_at() returns trigger as
+$$
+begin
+    if (to_jsonb(new) - 'updated_at') is distinct from (to_jsonb(old) - 'updated_at') then
+        new.updated_at = now();
+    end if;
+    return new;
+end;
+$$ language plpgsql;
+
+-- contractors_log_change записывает изменение записи в contractors_change_log.
+-- Мягкое удаление (is_delete) журналируется как DELETED, восстановление - как INSERTED.
+create or replace function contractors_log_change() returns trigger as
+$$
+declare
+    v_operation     varchar;
+    v_entity_id     bigint;
+    v_contractor_id bigint;
+begin
+    if tg_op = 'INSERT' then
+        v_operation = 'INSERTED';
+    elsif tg_op = 'DELETE' then
+        if old.is_delete then
+            return null;
+        end if;
+        v_operation = 'DELETED';
+    elsif new.is_delete and not old.is_delete then
+        v_operation = 'DELETED';
+    elsif old.is_delete and not new.is_delete then
+        v_operation = 'INSERTED';
+    elsif new.is_delete or (to_jsonb(new) - 'updated_at') = (to_jsonb(old) - 'updated_at') then
+        return null;
+    else
+        v_operation = 'UPDATED';
+    end if;
+
+    if tg_op = 'DELETE' then
+        v_entity_id = old.id;
+        v_contractor_id = case when tg_argv[0] = 'CONTRACTOR' then old.id else (to_jsonb(old) ->> 'contractor_id')::bigint end;
+    else
+        v_entity_id = new.id;
+        v_contractor_id = case when tg_argv[0] = 'CONTRACTOR' then new.id else (to_jsonb(new) ->> 'contractor_id')::bigint end;
+    end if;
+
+    insert into contractors_change_log (entity, entity_id, contractor_id, operation)
+    values (tg_argv[0], v_entity_id, v_contractor_id, v_operation);
+
+    return null;
+end;
+$$ language plpgsql;
+
+create trigger contractors_contractor_touch_updated_at
+    before update on contractors_contractor
+    for each row execute procedure contractors_touch_updated_at();
+
+create trigger contractors_contractor_log_change
+    after insert or update or delete on contractors_contractor
+    for each row execute procedure contractors_log_change('CONTRACTOR');
+
+create trigger contractors_contractor_employee_touch_updated_at
+    before update on contractors_contractor_employee
+    for each row execute procedure contractors_touch_updated_at();
+
+create trigger contractors_contractor_employee_log_change
+    after insert or update or delete on contractors_contractor_employee
+    for each row execute procedure contractors_log_change('EMPLOYEE');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop trigger if exists contractors_contractor_employee_log_change on contractors_contractor_employee;
+drop trigger if exists contractors_contractor_employee_touch_updated_at on contractors_contractor_employee;
+drop trigger if exists contractors_contractor_log_change on contractors_contractor;
+drop trigger if exists contractors_contractor_touch_updated_at on contractors_contractor;
+drop function if exists contractors_log_change();
+drop function if exists contractors_touch_updated_at();
+DROP TABLE IF EXISTS contractors_change_log;
+alter table contractors_contractor_employee drop column if exists created_at, drop column if exists updated_at;
+alter table contractors_contractor drop column if exists created_at, drop column if exists updated_at;
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+create table if not exists contractors_outbox
+(
+    id bigserial
+    constraint contractors_outbox_pk
+    primary key,
+    event_id varchar not null
+    constraint contractors_outbox_event_id_key
+    unique,
+    event_type varchar not null,
+    event_version integer not null,
+    aggregate_id bigint not null,
+    payload jsonb not null,
+    occurred_at timestamp with time zone default now() not null,
+    status varchar default 'PENDING'::character varying not null,
+    attempts integer default 0 not null,
+    next_attempt_at timestamp with time zone default now() not null,
+    last_error varchar,
+    delivered_at timestamp with time zone
+);
+
+create index if not exists contractors_outbox_pending_index
+    on contractors_outbox (aggregate_id, id)
+    where status = 'PENDING';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS contractors_outbox;
+-- +goose StatementEnd
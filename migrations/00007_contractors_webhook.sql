@@ -0,0 +1,66 @@
+-- +goose Up
+-- +goose StatementBegin
+create table if not exists contractors_webhook_subscription
+(
+    id bigserial
+    constraint contractors_webhook_subscription_pk
+    primary key,
+    url varchar not null,
+    event_types varchar[] not null,
+    secret varchar not null,
+    active boolean default true not null,
+    created_by varchar,
+    created_at timestamp with time zone default now() not null,
+    is_delete boolean default false not null
+);
+
+create table if not exists contractors_webhook_delivery
+(
+    id bigserial
+    constraint contractors_webhook_delivery_pk
+    primary key,
+    subscription_id bigint not null
+    constraint contractors_webhook_delivery_subscription_id_fk
+    references contractors_webhook_subscription,
+    event_id varchar not null,
+    event_type varchar not null,
+    payload jsonb not null,
+    status varchar default 'PENDING'::character varying not null,
+    attempts integer default 0 not null,
+    next_attempt_at timestamp with time zone default now() not null,
+    last_status_code integer,
+    last_error varchar,
+    created_at timestamp with time zone default now() not null,
+    delivered_at timestamp with time zone,
+    constraint contractors_webhook_delivery_subscription_event_key
+    unique (subscription_id, event_id)
+);
+
+create index if not exists contractors_webhook_delivery_pending_index
+    on contractors_webhook_delivery (next_attempt_at)
+    where status = 'PENDING';
+
+create table if not exists contractors_webhook_delivery_attempt
+(
+    id bigserial
+    constraint contractors_webhook_delivery_attempt_pk
+    primary key,
+    delivery_id bigint not null
+    constraint contractors_webhook_delivery_attempt_delivery_id_fk
+    references contractors_webhook_delivery,
+    attempted_at timestamp with time zone default now() not null,
+    status_code integer,
+    error varchar,
+    duration_ms bigint not null
+);
+
+create index if not exists contractors_webhook_delivery_attempt_delivery_id_index
+    on contractors_webhook_delivery_attempt (delivery_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS contractors_webhook_delivery_attempt;
+DROP TABLE IF EXISTS contractors_webhook_delivery;
+DROP TABLE IF EXISTS contractors_webhook_subscription;
+-- +goose StatementEnd
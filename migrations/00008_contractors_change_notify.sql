@@ -0,0 +1,102 @@
+-- +goose Up
+-- +goose StatementBegin
+-- contractors_log_change записывает изменение записи в contractors_change_log и уведомляет
+-- слушателей канала contractors_changes. Уведомление доставляется после фиксации транзакции.
+-- Мягкое удаление (is_delete) журналируется как DELETED, восстановление - как INSERTED.
+create or replace function contractors_log_change() returns trigger as
+$$
+declare
+    v_operation     varchar;
+    v_entity_id     bigint;
+    v_contractor_id bigint;
+    v_id            bigint;
+    v_tx_id         bigint;
+    v_changed_at    timestamp with time zone;
+begin
+    if tg_op = 'INSERT' then
+        v_operation = 'INSERTED';
+    elsif tg_op = 'DELETE' then
+        if old.is_delete then
+            return null;
+        end if;
+        v_operation = 'DELETED';
+    elsif new.is_delete and not old.is_delete then
+        v_operation = 'DELETED';
+    elsif old.is_delete and not new.is_delete then
+        v_operation = 'INSERTED';
+    elsif new.is_delete or (to_jsonb(new) - 'updated_at') = (to_jsonb(old) - 'updated_at') then
+        return null;
+    else
+        v_operation = 'UPDATED';
+    end if;
+
+    if tg_op = 'DELETE' then
+        v_entity_id = old.id;
+        v_contractor_id = case when tg_argv[0] = 'CONTRACTOR' then old.id else (to_jsonb(old) ->> 'contractor_id')::bigint end;
+    else
+        v_entity_id = new.id;
+        v_contractor_id = case when tg_argv[0] = 'CONTRACTOR' then new.id else (to_jsonb(new) ->> 'contractor_id')::bigint end;
+    end if;
+
+    insert into contractors_change_log (entity, entity_id, contractor_id, operation)
+    values (tg_argv[0], v_entity_id, v_contractor_id, v_operation)
+    returning id, tx_id, changed_at into v_id, v_tx_id, v_changed_at;
+
+    perform pg_notify('contractors_changes', json_build_object(
+        'id', v_id,
+        'txId', v_tx_id,
+        'entity', tg_argv[0],
+        'entityId', v_entity_id,
+        'contractorId', v_contractor_id,
+        'operation', v_operation,
+        'changedAt', v_changed_at
+    )::text);
+
+    return null;
+end;
+$$ language plpgsql;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- contractors_log_change записывает изменение записи в contractors_change_log.
+-- Мягкое удаление (is_delete) журналируется как DELETED, восстановление - как INSERTED.
+create or replace function contractors_log_change() returns trigger as
+$$
+declare
+    v_operation     varchar;
+    v_entity_id     bigint;
+    v_contractor_id bigint;
+begin
+    if tg_op = 'INSERT' then
+        v_operation = 'INSERTED';
+    elsif tg_op = 'DELETE' then
+        if old.is_delete then
+            return null;
+        end if;
+        v_operation = 'DELETED';
+    elsif new.is_delete and not old.is_delete then
+        v_operation = 'DELETED';
+    elsif old.is_delete and not new.is_delete then
+        v_operation = 'INSERTED';
+    elsif new.is_delete or (to_jsonb(new) - 'updated_at') = (to_jsonb(old) - 'updated_at') then
+        return null;
+    else
+        v_operation = 'UPDATED';
+    end if;
+
+    if tg_op = 'DELETE' then
+        v_entity_id = old.id;
+        v_contractor_id = case when tg_argv[0] = 'CONTRACTOR' then old.id else (to_jsonb(old) ->> 'contractor_id')::bigint end;
+    else
+        v_entity_id = new.id;
+        v_contractor_id = case when tg_argv[0] = 'CONTRACTOR' then new.id else (to_jsonb(new) ->> 'contractor_id')::bigint end;
+    end if;
+
+    insert into contractors_change_log (entity, entity_id, contractor_id, operation)
+    values (tg_argv[0], v_entity_id, v_contractor_id, v_operation);
+
+    return null;
+end;
+$$ language plpgsql;
+-- +goose StatementEnd
@@ -0,0 +1,54 @@
+-- +goose Up
+-- +goose StatementBegin
+update contractors_contractor
+set email = lower(trim(email))
+where email <> lower(trim(email));
+
+update contractors_contractor_employee
+set email = lower(trim(email))
+where email <> lower(trim(email));
+
+do $$
+declare
+    v_duplicates text;
+begin
+    select string_agg(email || ' (id ' || ids || ')', ', ')
+    into v_duplicates
+    from (select email, string_agg(id::text, ', ' order by id) as ids
+          from contractors_contractor
+          where is_delete = false
+          group by email
+          having count(*) > 1) d;
+
+    if v_duplicates is not null then
+        raise exception 'duplicate contractor emails must be resolved before migration: %', v_duplicates;
+    end if;
+
+    select string_agg(email || ' (contractor ' || contractor_id || ', id ' || ids || ')', ', ')
+    into v_duplicates
+    from (select contractor_id, email, string_agg(id::text, ', ' order by id) as ids
+          from contractors_contractor_employee
+          where is_delete = false
+          group by contractor_id, email
+          having count(*) > 1) d;
+
+    if v_duplicates is not null then
+        raise exception 'duplicate employee emails must be resolved before migration: %', v_duplicates;
+    end if;
+end
+$$;
+
+create unique index if not exists contractors_contractor_email_uindex
+    on contractors_contractor (lower(email))
+    where is_delete = false;
+
+create unique index if not exists contractors_contractor_employee_email_uindex
+    on contractors_contractor_employee (contractor_id, lower(email))
+    where is_delete = false;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop index if exists contractors_contractor_employee_email_uindex;
+drop index if exists contractors_contractor_email_uindex;
+-- +goose StatementEnd
@@ -0,0 +1,83 @@
+-- +goose Up
+-- +goose StatementBegin
+create table if not exists contractors_contractor_address
+(
+    id bigserial
+    constraint contractors_contractor_address_pk
+    primary key,
+    contractor_id bigint not null
+    constraint contractors_contractor_address_contractor_id_fk
+    references contractors_contractor,
+    type varchar not null,
+    country varchar,
+    region varchar,
+    city varchar not null,
+    street varchar not null,
+    postal_code varchar,
+    created_at timestamp with time zone default now() not null,
+    updated_at timestamp with time zone default now() not null,
+    is_delete boolean default false not null
+);
+
+-- У контрагента может быть только один действующий адрес каждого типа
+create unique index if not exists contractors_contractor_address_type_uindex
+    on contractors_contractor_address (contractor_id, type)
+    where is_delete = false;
+
+create table if not exists contractors_contractor_contact
+(
+    id bigserial
+    constraint contractors_contractor_contact_pk
+    primary key,
+    contractor_id bigint not null
+    constraint contractors_contractor_contact_contractor_id_fk
+    references contractors_contractor,
+    full_name varchar not null,
+    position varchar,
+    email varchar,
+    comment varchar,
+    created_at timestamp with time zone default now() not null,
+    updated_at timestamp with time zone default now() not null,
+    is_delete boolean default false not null
+);
+
+create index if not exists contractors_contractor_contact_contractor_id_index
+    on contractors_contractor_contact (contractor_id)
+    where is_delete = false;
+
+-- Телефон принадлежит контрагенту (contact_id is null) или его контактному лицу.
+-- Телефоны заменяются набором целиком, поэтому удаляются физически.
+create table if not exists contractors_contractor_phone
+(
+    id bigserial
+    constraint contractors_contractor_phone_pk
+    primary key,
+    contractor_id bigint not null
+    constraint contractors_contractor_phone_contractor_id_fk
+    references contractors_contractor,
+    contact_id bigint
+    constraint contractors_contractor_phone_contact_id_fk
+    references contractors_contractor_contact,
+    type varchar not null,
+    number varchar not null,
+    extension varchar
+);
+
+create index if not exists contractors_contractor_phone_contractor_id_index
+    on contractors_contractor_phone (contractor_id);
+
+create trigger contractors_contractor_address_touch_updated_at
+    before update on contractors_contractor_address
+    for each row execute procedure contractors_touch_updated_at();
+
+create trigger contractors_contractor_contact_touch_updated_at
+    before update on contractors_contractor_contact
+    for each row execute procedure contractors_touch_updated_at();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS contractors_contractor_phone;
+DROP TABLE IF EXISTS contractors_contractor_contact;
+DROP TABLE IF EXISTS contractors_contractor_address;
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+create table if not exists contractors_contractor_document
+(
+    id bigserial
+    constraint contractors_contractor_document_pk
+    primary key,
+    contractor_id bigint not null
+    constraint contractors_contractor_document_contractor_id_fk
+    references contractors_contractor,
+    type varchar not null,
+    number varchar,
+    issue_date date,
+    expiry_date date,
+    file_name varchar not null,
+    content_type varchar not null,
+    size bigint not null,
+    sha256 varchar not null,
+    storage_key varchar not null
+    constraint contractors_contractor_document_storage_key_uindex
+    unique,
+    uploaded_by varchar,
+    uploaded_at timestamp with time zone default now() not null,
+    is_delete boolean default false not null
+);
+
+create index if not exists contractors_contractor_document_contractor_id_index
+    on contractors_contractor_document (contractor_id)
+    where is_delete = false;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS contractors_contractor_document;
+-- +goose StatementEnd
@@ -0,0 +1,45 @@
+-- +goose Up
+-- +goose StatementBegin
+create table if not exists contractors_contractor_contract
+(
+    id bigserial
+    constraint contractors_contractor_contract_pk
+    primary key,
+    contractor_id bigint not null
+    constraint contractors_contractor_contract_contractor_id_fk
+    references contractors_contractor,
+    number varchar not null,
+    start_date date not null,
+    end_date date,
+    amount numeric(18, 2) not null
+    constraint contractors_contractor_contract_amount_check
+    check (amount >= 0),
+    currency varchar(3) not null,
+    status varchar not null,
+    -- Время отправки предупреждения об окончании договора; сбрасывается при изменении end_date
+    expiry_warned_at timestamp with time zone,
+    created_at timestamp with time zone default now() not null,
+    updated_at timestamp with time zone default now() not null,
+    is_delete boolean default false not null,
+    constraint contractors_contractor_contract_dates_check
+    check (end_date is null or end_date >= start_date)
+);
+
+create unique index if not exists contractors_contractor_contract_number_uindex
+    on contractors_contractor_contract (contractor_id, number)
+    where is_delete = false;
+
+-- Поиск действующих и истекающих договоров фоновой проверкой
+create index if not exists contractors_contractor_contract_end_date_index
+    on contractors_contractor_contract (status, end_date)
+    where is_delete = false;
+
+create trigger contractors_contractor_contract_touch_updated_at
+    before update on contractors_contractor_contract
+    for each row execute procedure contractors_touch_updated_at();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS contractors_contractor_contract;
+-- +goose StatementEnd
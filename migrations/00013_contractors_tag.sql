@@ -0,0 +1,50 @@
+-- +goose Up
+-- +goose StatementBegin
+create table if not exists contractors_tag
+(
+    id bigserial
+    constraint contractors_tag_pk
+    primary key,
+    category varchar not null,
+    name varchar not null,
+    description varchar,
+    created_at timestamp with time zone default now() not null,
+    updated_at timestamp with time zone default now() not null,
+    is_delete boolean default false not null
+);
+
+-- Название тега уникально в рамках категории без учета регистра
+create unique index if not exists contractors_tag_name_uindex
+    on contractors_tag (lower(category), lower(name))
+    where is_delete = false;
+
+-- Назначения тегов удаляются физически, в том числе при удалении тега
+create table if not exists contractors_contractor_tag
+(
+    id bigserial
+    constraint contractors_contractor_tag_pk
+    primary key,
+    contractor_id bigint not null
+    constraint contractors_contractor_tag_contractor_id_fk
+    references contractors_contractor,
+    tag_id bigint not null
+    constraint contractors_contractor_tag_tag_id_fk
+    references contractors_tag,
+    created_at timestamp with time zone default now() not null,
+    constraint contractors_contractor_tag_uindex
+    unique (contractor_id, tag_id)
+);
+
+create index if not exists contractors_contractor_tag_tag_id_index
+    on contractors_contractor_tag (tag_id);
+
+create trigger contractors_tag_touch_updated_at
+    before update on contractors_tag
+    for each row execute procedure contractors_touch_updated_at();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS contractors_contractor_tag;
+DROP TABLE IF EXISTS contractors_tag;
+-- +goose StatementEnd
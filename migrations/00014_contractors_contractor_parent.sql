@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Ссылка проверяется в конце транзакции, чтобы восстановление из резервной копии не зависело от порядка id
+alter table contractors_contractor
+    add column if not exists parent_id bigint
+    constraint contractors_contractor_parent_id_fk
+    references contractors_contractor
+    deferrable initially deferred,
+    add constraint contractors_contractor_parent_id_check
+    check (parent_id <> id);
+
+create index if not exists contractors_contractor_parent_id_index
+    on contractors_contractor (parent_id)
+    where parent_id is not null;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS contractors_contractor_parent_id_index;
+ALTER TABLE contractors_contractor DROP CONSTRAINT IF EXISTS contractors_contractor_parent_id_check;
+ALTER TABLE contractors_contractor DROP COLUMN IF EXISTS parent_id;
+-- +goose StatementEnd
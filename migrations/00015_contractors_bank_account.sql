@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+create table if not exists contractors_contractor_bank_account
+(
+    id bigserial
+    constraint contractors_contractor_bank_account_pk
+    primary key,
+    contractor_id bigint not null
+    constraint contractors_contractor_bank_account_contractor_id_fk
+    references contractors_contractor,
+    iban varchar(34) not null,
+    bik varchar(11) not null,
+    bank_name varchar not null,
+    currency varchar(3) not null,
+    is_primary boolean default false not null,
+    created_at timestamp with time zone default now() not null,
+    updated_at timestamp with time zone default now() not null,
+    is_delete boolean default false not null
+);
+
+create unique index if not exists contractors_contractor_bank_account_iban_uindex
+    on contractors_contractor_bank_account (contractor_id, iban)
+    where is_delete = false;
+
+-- У контрагента может быть только один основной счет
+create unique index if not exists contractors_contractor_bank_account_primary_uindex
+    on contractors_contractor_bank_account (contractor_id)
+    where is_primary = true and is_delete = false;
+
+create trigger contractors_contractor_bank_account_touch_updated_at
+    before update on contractors_contractor_bank_account
+    for each row execute procedure contractors_touch_updated_at();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS contractors_contractor_bank_account;
+-- +goose StatementEnd
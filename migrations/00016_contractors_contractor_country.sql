@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Код страны ISO 3166-1 alpha-2; у резидентов всегда KZ. Налоговый номер и адрес регистрации заполняются
+-- для нерезидентов, у ранее созданных нерезидентов остаются пустыми до изменения
+alter table contractors_contractor
+    add column if not exists country varchar(2),
+    add column if not exists foreign_tax_id varchar(30),
+    add column if not exists registration_address varchar;
+
+update contractors_contractor set country = 'KZ' where resident = true and country is null;
+
+create index if not exists contractors_contractor_country_index
+    on contractors_contractor (country)
+    where is_delete = false;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS contractors_contractor_country_index;
+ALTER TABLE contractors_contractor DROP COLUMN IF EXISTS registration_address;
+ALTER TABLE contractors_contractor DROP COLUMN IF EXISTS foreign_tax_id;
+ALTER TABLE contractors_contractor DROP COLUMN IF EXISTS country;
+-- +goose StatementEnd
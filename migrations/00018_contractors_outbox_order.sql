@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Следующее событие контрагента не доставляется, пока есть более раннее недоставленное событие в любом статусе
+create index if not exists contractors_outbox_undelivered_index
+    on contractors_outbox (aggregate_id, id)
+    where status <> 'DELIVERED';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS contractors_outbox_undelivered_index;
+-- +goose StatementEnd
@@ -0,0 +1,61 @@
+-- +goose Up
+-- +goose StatementBegin
+-- contractors_write_change записывает изменение в contractors_change_log и уведомляет слушателей канала
+-- contractors_changes. Уведомление доставляется после фиксации транзакции. Если в транзакции установлен
+-- параметр contractors.restore = 'on', изменение не записывается: восстановление резервной копии не является
+-- изменением данных для потребителей журнала.
+create or replace function contractors_write_change(p_entity varchar, p_entity_id bigint, p_contractor_id bigint,
+                                                    p_operation varchar) returns void as
+$$
+declare
+    v_id         bigint;
+    v_tx_id      bigint;
+    v_changed_at timestamp with time zone;
+begin
+    if current_setting('contractors.restore', true) = 'on' then
+        return;
+    end if;
+
+    insert into contractors_change_log (entity, entity_id, contractor_id, operation)
+    values (p_entity, p_entity_id, p_contractor_id, p_operation)
+    returning id, tx_id, changed_at into v_id, v_tx_id, v_changed_at;
+
+    perform pg_notify('contractors_changes', json_build_object(
+        'id', v_id,
+        'txId', v_tx_id,
+        'entity', p_entity,
+        'entityId', p_entity_id,
+        'contractorId', p_contractor_id,
+        'operation', p_operation,
+        'changedAt', v_changed_at
+    )::text);
+end;
+$$ language plpgsql;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+create or replace function contractors_write_change(p_entity varchar, p_entity_id bigint, p_contractor_id bigint,
+                                                    p_operation varchar) returns void as
+$$
+declare
+    v_id         bigint;
+    v_tx_id      bigint;
+    v_changed_at timestamp with time zone;
+begin
+    insert into contractors_change_log (entity, entity_id, contractor_id, operation)
+    values (p_entity, p_entity_id, p_contractor_id, p_operation)
+    returning id, tx_id, changed_at into v_id, v_tx_id, v_changed_at;
+
+    perform pg_notify('contractors_changes', json_build_object(
+        'id', v_id,
+        'txId', v_tx_id,
+        'entity', p_entity,
+        'entityId', p_entity_id,
+        'contractorId', p_contractor_id,
+        'operation', p_operation,
+        'changedAt', v_changed_at
+    )::text);
+end;
+$$ language plpgsql;
+-- +goose StatementEnd
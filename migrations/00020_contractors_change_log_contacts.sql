@@ -0,0 +1,60 @@
+-- +goose Up
+-- +goose StatementBegin
+-- contractors_log_phone_change журналирует изменение телефона как изменение его владельца: контактного лица
+-- или самого контрагента. Телефоны заменяются набором целиком, поэтому владелец журналируется
+-- не более одного раза за транзакцию.
+create or replace function contractors_log_phone_change() returns trigger as
+$$
+declare
+    v_contractor_id bigint;
+    v_contact_id    bigint;
+    v_entity        varchar;
+    v_entity_id     bigint;
+begin
+    if tg_op = 'DELETE' then
+        v_contractor_id = old.contractor_id;
+        v_contact_id = old.contact_id;
+    else
+        v_contractor_id = new.contractor_id;
+        v_contact_id = new.contact_id;
+    end if;
+
+    if v_contact_id is null then
+        v_entity = 'CONTRACTOR';
+        v_entity_id = v_contractor_id;
+    else
+        v_entity = 'CONTACT';
+        v_entity_id = v_contact_id;
+    end if;
+
+    if exists(select 1 from contractors_change_log l
+              where l.tx_id = txid_current() and l.entity = v_entity and l.entity_id = v_entity_id) then
+        return null;
+    end if;
+
+    perform contractors_write_change(v_entity, v_entity_id, v_contractor_id, 'UPDATED');
+
+    return null;
+end;
+$$ language plpgsql;
+
+create trigger contractors_contractor_address_log_change
+    after insert or update or delete on contractors_contractor_address
+    for each row execute procedure contractors_log_change('ADDRESS');
+
+create trigger contractors_contractor_contact_log_change
+    after insert or update or delete on contractors_contractor_contact
+    for each row execute procedure contractors_log_change('CONTACT');
+
+create trigger contractors_contractor_phone_log_change
+    after insert or update or delete on contractors_contractor_phone
+    for each row execute procedure contractors_log_phone_change();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop trigger if exists contractors_contractor_phone_log_change on contractors_contractor_phone;
+drop trigger if exists contractors_contractor_contact_log_change on contractors_contractor_contact;
+drop trigger if exists contractors_contractor_address_log_change on contractors_contractor_address;
+drop function if exists contractors_log_phone_change();
+-- +goose StatementEnd
@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+-- contractors_log_tag_change журналирует изменение назначенных тегов как изменение контрагента. Теги заменяются
+-- набором целиком, а удаление тега снимает его со всех контрагентов, поэтому каждый контрагент журналируется
+-- не более одного раза за транзакцию.
+create or replace function contractors_log_tag_change() returns trigger as
+$$
+declare
+    v_contractor_id bigint;
+begin
+    if tg_op = 'DELETE' then
+        v_contractor_id = old.contractor_id;
+    else
+        v_contractor_id = new.contractor_id;
+    end if;
+
+    if exists(select 1 from contractors_change_log l
+              where l.tx_id = txid_current() and l.entity = 'CONTRACTOR' and l.entity_id = v_contractor_id) then
+        return null;
+    end if;
+
+    perform contractors_write_change('CONTRACTOR', v_contractor_id, v_contractor_id, 'UPDATED');
+
+    return null;
+end;
+$$ language plpgsql;
+
+create trigger contractors_contractor_tag_log_change
+    after insert or update or delete on contractors_contractor_tag
+    for each row execute procedure contractors_log_tag_change();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop trigger if exists contractors_contractor_tag_log_change on contractors_contractor_tag;
+drop function if exists contractors_log_tag_change();
+-- +goose StatementEnd